@@ -2,18 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	queries "github.com/oatsmoke/warehouse_backend/internal/db"
 	"github.com/oatsmoke/warehouse_backend/internal/handler"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/env"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/kafka"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/redis"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/server"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
 	"github.com/oatsmoke/warehouse_backend/internal/repository"
 	"github.com/oatsmoke/warehouse_backend/internal/service"
 )
@@ -24,16 +29,40 @@ func main() {
 
 	logger.Init(env.GetLogLevel())
 
+	if err := role.LoadPermissions(env.GetRolePermissions()); err != nil {
+		logger.Error(logger.MsgFailedToParse, err)
+		return
+	}
+
+	stockCheck, err := strconv.Atoi(env.GetStockCheck())
+	if err != nil {
+		logger.Error(logger.MsgFailedToParse, fmt.Errorf("%s: %w", env.StockCheck, err))
+		return
+	}
+	if stockCheck < 1 {
+		logger.Error(logger.MsgFailedToValidate, fmt.Errorf("%s: %w", env.StockCheck, logger.ErrInvalidInterval))
+		return
+	}
+
 	postgresDB := postgresql.Connect(ctx, env.GetPostgresDsn())
 	defer postgresDB.Close()
 
 	redisDB := redis.Connect(env.GetRedisDsn())
 	defer redis.Disconnect(redisDB)
 
+	hub := websocket.NewHub()
+	go hub.Run()
+
 	newQ := queries.New(postgresDB)
 	newR := repository.New(postgresDB, redisDB, newQ)
-	newS := service.New(newR)
-	newH := handler.New(newS)
+	publisher := kafka.NewWriter(env.GetKafkaBrokers())
+	defer publisher.Close()
+
+	newS := service.New(newR, hub, publisher)
+	newH := handler.New(newS, hub)
+
+	go newS.StockMinimum.Run(ctx, time.Duration(stockCheck)*time.Second)
+	go newS.Outbox.Run(ctx, time.Second)
 
 	httpS := server.New(env.GetHttpPort(), newH)
 	httpS.Run()
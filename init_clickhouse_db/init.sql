@@ -1,24 +1,107 @@
-CREATE TABLE IF NOT EXISTS kafka_stream
+-- the first schema kept one action string per event, its consumer would
+-- share the topic with the one below
+DROP VIEW IF EXISTS kafka_to_events;
+DROP TABLE IF EXISTS kafka_stream;
+
+-- every message is a model.Event envelope, it is read whole and unpacked
+-- by the view so that a new payload field does not break the consumer
+CREATE TABLE IF NOT EXISTS kafka_events_v1
 (
-    action String
+    raw String
 )
     ENGINE = Kafka
         SETTINGS
             kafka_broker_list = 'kafka:9094',
             kafka_topic_list = 'actions',
-            kafka_group_name = 'clickhouse_group',
-            kafka_format = 'JSONEachRow',
+            kafka_group_name = 'clickhouse_events_v1',
+            kafka_format = 'JSONAsString',
             kafka_num_consumers = 1;
 
-CREATE TABLE IF NOT EXISTS events
+-- the outbox can publish an event twice, the copies share event_id
+CREATE TABLE IF NOT EXISTS events_v1
+(
+    version            UInt16,
+    event_id           UUID,
+    event_type         LowCardinality(String),
+    occurred_at        DateTime64(3, 'UTC'),
+    user_id            Int64,
+    entity_type        LowCardinality(String),
+    entity_id          Int64,
+    location_id        Nullable(Int64),
+    move_code          LowCardinality(Nullable(String)),
+    from_department_id Nullable(Int64),
+    from_employee_id   Nullable(Int64),
+    from_contract_id   Nullable(Int64),
+    to_department_id   Nullable(Int64),
+    to_employee_id     Nullable(Int64),
+    to_contract_id     Nullable(Int64),
+    profile_id         Nullable(Int64),
+    category_id        Nullable(Int64),
+    company_id         Nullable(Int64),
+    duplicate_id       Nullable(Int64)
+)
+    ENGINE = ReplacingMergeTree()
+        PARTITION BY toYYYYMM(occurred_at)
+        ORDER BY (event_type, entity_type, occurred_at, event_id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS kafka_to_events_v1
+    TO events_v1
+AS
+SELECT JSONExtract(raw, 'version', 'UInt16')                                  AS version,
+       toUUID(JSONExtractString(raw, 'event_id'))                             AS event_id,
+       JSONExtractString(raw, 'event_type')                                   AS event_type,
+       parseDateTime64BestEffort(JSONExtractString(raw, 'occurred_at'), 3)    AS occurred_at,
+       JSONExtract(raw, 'user_id', 'Int64')                                   AS user_id,
+       JSONExtractString(raw, 'entity_type')                                  AS entity_type,
+       JSONExtract(raw, 'entity_id', 'Int64')                                 AS entity_id,
+       JSONExtract(raw, 'payload', 'location_id', 'Nullable(Int64)')          AS location_id,
+       JSONExtract(raw, 'payload', 'move_code', 'Nullable(String)')           AS move_code,
+       JSONExtract(raw, 'payload', 'from_department_id', 'Nullable(Int64)')   AS from_department_id,
+       JSONExtract(raw, 'payload', 'from_employee_id', 'Nullable(Int64)')     AS from_employee_id,
+       JSONExtract(raw, 'payload', 'from_contract_id', 'Nullable(Int64)')     AS from_contract_id,
+       JSONExtract(raw, 'payload', 'to_department_id', 'Nullable(Int64)')     AS to_department_id,
+       JSONExtract(raw, 'payload', 'to_employee_id', 'Nullable(Int64)')       AS to_employee_id,
+       JSONExtract(raw, 'payload', 'to_contract_id', 'Nullable(Int64)')       AS to_contract_id,
+       JSONExtract(raw, 'payload', 'profile_id', 'Nullable(Int64)')           AS profile_id,
+       JSONExtract(raw, 'payload', 'category_id', 'Nullable(Int64)')          AS category_id,
+       JSONExtract(raw, 'payload', 'company_id', 'Nullable(Int64)')           AS company_id,
+       JSONExtract(raw, 'payload', 'duplicate_id', 'Nullable(Int64)')         AS duplicate_id
+FROM kafka_events_v1
+WHERE JSONExtract(raw, 'version', 'UInt16') = 1;
+
+-- moves per day by dimension, an event published twice is counted twice
+-- here. The moves of a category between departments:
+-- SELECT day, from_department_id, to_department_id, sum(moves)
+-- FROM movements_daily
+-- WHERE category_id = 3
+-- GROUP BY day, from_department_id, to_department_id
+CREATE TABLE IF NOT EXISTS movements_daily
 (
-    action String
+    day                Date,
+    event_type         LowCardinality(String),
+    move_code          LowCardinality(String),
+    from_department_id Int64,
+    to_department_id   Int64,
+    profile_id         Int64,
+    category_id        Int64,
+    company_id         Int64,
+    moves              UInt64
 )
-    ENGINE = MergeTree()
-        ORDER BY tuple();
+    ENGINE = SummingMergeTree(moves)
+        ORDER BY (day, event_type, move_code, from_department_id, to_department_id, profile_id, category_id,
+                  company_id);
 
-CREATE MATERIALIZED VIEW IF NOT EXISTS kafka_to_events
-    TO events
+CREATE MATERIALIZED VIEW IF NOT EXISTS events_v1_to_movements_daily
+    TO movements_daily
 AS
-SELECT *
-FROM kafka_stream;
+SELECT toDate(occurred_at)                AS day,
+       event_type,
+       ifNull(move_code, '')              AS move_code,
+       ifNull(from_department_id, 0)      AS from_department_id,
+       ifNull(to_department_id, 0)        AS to_department_id,
+       ifNull(profile_id, 0)              AS profile_id,
+       ifNull(category_id, 0)             AS category_id,
+       ifNull(company_id, 0)              AS company_id,
+       1                                  AS moves
+FROM events_v1
+WHERE entity_type = 'equipment';
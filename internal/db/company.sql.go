@@ -99,6 +99,38 @@ func (q *Queries) ListCompany(ctx context.Context, arg *ListCompanyParams) ([]*L
 	return items, nil
 }
 
+const listCompanyByTitles = `-- name: ListCompanyByTitles :many
+SELECT id, title
+FROM companies
+WHERE title = ANY ($1::text[])
+  AND deleted_at IS NULL
+`
+
+type ListCompanyByTitlesRow struct {
+	ID    int64  `db:"id" json:"id"`
+	Title string `db:"title" json:"title"`
+}
+
+func (q *Queries) ListCompanyByTitles(ctx context.Context, titles []string) ([]*ListCompanyByTitlesRow, error) {
+	rows, err := q.db.Query(ctx, listCompanyByTitles, titles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListCompanyByTitlesRow
+	for rows.Next() {
+		var i ListCompanyByTitlesRow
+		if err := rows.Scan(&i.ID, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readCompany = `-- name: ReadCompany :one
 SELECT id, title, deleted_at
 FROM companies
@@ -15,7 +15,7 @@ import (
 const createEquipment = `-- name: CreateEquipment :one
 INSERT INTO equipments (serial_number, profile_id, company_id)
 VALUES ($1, $2, $3)
-RETURNING id, serial_number, profile_id, deleted_at, company_id
+RETURNING id, serial_number, profile_id, deleted_at, company_id, written_off_at, canonical_serial_number
 `
 
 type CreateEquipmentParams struct {
@@ -33,6 +33,8 @@ func (q *Queries) CreateEquipment(ctx context.Context, arg *CreateEquipmentParam
 		&i.ProfileID,
 		&i.DeletedAt,
 		&i.CompanyID,
+		&i.WrittenOffAt,
+		&i.CanonicalSerialNumber,
 	)
 	return &i, err
 }
@@ -48,36 +50,91 @@ func (q *Queries) DeleteEquipment(ctx context.Context, id int64) (pgconn.Command
 	return q.db.Exec(ctx, deleteEquipment, id)
 }
 
+const getEquipmentDimensions = `-- name: GetEquipmentDimensions :one
+SELECT e.profile_id,
+       p.category_id,
+       e.company_id
+FROM equipments e
+         INNER JOIN profiles p ON p.id = e.profile_id
+WHERE e.id = $1
+`
+
+type GetEquipmentDimensionsRow struct {
+	ProfileID  int64 `db:"profile_id" json:"profile_id"`
+	CategoryID int64 `db:"category_id" json:"category_id"`
+	CompanyID  int64 `db:"company_id" json:"company_id"`
+}
+
+func (q *Queries) GetEquipmentDimensions(ctx context.Context, id int64) (*GetEquipmentDimensionsRow, error) {
+	row := q.db.QueryRow(ctx, getEquipmentDimensions, id)
+	var i GetEquipmentDimensionsRow
+	err := row.Scan(&i.ProfileID, &i.CategoryID, &i.CompanyID)
+	return &i, err
+}
+
+const getEquipmentIDBySerialNumber = `-- name: GetEquipmentIDBySerialNumber :one
+SELECT id
+FROM equipments
+WHERE canonical_serial_number = $1
+ORDER BY serial_number = $2 DESC, deleted_at IS NOT NULL, id
+LIMIT 1
+`
+
+type GetEquipmentIDBySerialNumberParams struct {
+	CanonicalSerialNumber string `db:"canonical_serial_number" json:"canonical_serial_number"`
+	SerialNumber          string `db:"serial_number" json:"serial_number"`
+}
+
+func (q *Queries) GetEquipmentIDBySerialNumber(ctx context.Context, arg *GetEquipmentIDBySerialNumberParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getEquipmentIDBySerialNumber, arg.CanonicalSerialNumber, arg.SerialNumber)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listEquipment = `-- name: ListEquipment :many
 SELECT e.id,
        e.serial_number,
        e.deleted_at,
+       co.id            as company_id,
+       co.title         as company_title,
        p.id             as profile_id,
        p.title          as profile_title,
        c.id             as category_id,
        c.title          as category_title,
        COUNT(*) OVER () AS total
 FROM equipments e
+         INNER JOIN companies co ON co.id = e.company_id
          INNER JOIN profiles p ON p.id = e.profile_id
          INNER JOIN categories c ON c.id = p.category_id
 WHERE ($1::bool = true OR e.deleted_at IS NULL)
   AND ($2::text = '' OR (e.serial_number || ' ' || p.title || ' ' || c.title) ILIKE '%' || $2 || '%')
   AND (array_length($3::bigint[], 1) IS NULL OR e.id = ANY ($3))
-ORDER BY CASE WHEN $4::text = 'id' AND $5::text = 'asc' THEN e.id::text END,
-         CASE WHEN $4 = 'id' AND $5 = 'desc' THEN e.id::text END DESC,
-         CASE WHEN $4 = 'serial_number' AND $5 = 'asc' THEN e.serial_number END,
-         CASE WHEN $4 = 'serial_number' AND $5 = 'desc' THEN e.serial_number END DESC,
-         CASE WHEN $4 = 'profile_title' AND $5 = 'asc' THEN p.title END,
-         CASE WHEN $4 = 'profile_title' AND $5 = 'desc' THEN p.title END DESC,
-         CASE WHEN $4 = 'category_title' AND $5 = 'asc' THEN c.title END,
-         CASE WHEN $4 = 'category_title' AND $5 = 'desc' THEN c.title END DESC
-LIMIT $7 OFFSET $6
+  AND ($4::bigint = 0 OR EXISTS (SELECT 1
+                                             FROM (SELECT to_department_id, to_employee_id
+                                                   FROM locations
+                                                   WHERE equipment_id = e.id
+                                                   ORDER BY move_at DESC, id DESC
+                                                   LIMIT 1) l
+                                                      LEFT JOIN employees em ON em.id = l.to_employee_id
+                                             WHERE l.to_department_id = $4
+                                                OR em.department_id = $4))
+ORDER BY CASE WHEN $5::text = 'id' AND $6::text = 'asc' THEN e.id::text END,
+         CASE WHEN $5 = 'id' AND $6 = 'desc' THEN e.id::text END DESC,
+         CASE WHEN $5 = 'serial_number' AND $6 = 'asc' THEN e.serial_number END,
+         CASE WHEN $5 = 'serial_number' AND $6 = 'desc' THEN e.serial_number END DESC,
+         CASE WHEN $5 = 'profile_title' AND $6 = 'asc' THEN p.title END,
+         CASE WHEN $5 = 'profile_title' AND $6 = 'desc' THEN p.title END DESC,
+         CASE WHEN $5 = 'category_title' AND $6 = 'asc' THEN c.title END,
+         CASE WHEN $5 = 'category_title' AND $6 = 'desc' THEN c.title END DESC
+LIMIT $8 OFFSET $7
 `
 
 type ListEquipmentParams struct {
 	WithDeleted      bool    `db:"with_deleted" json:"with_deleted"`
 	Search           string  `db:"search" json:"search"`
 	Ids              []int64 `db:"ids" json:"ids"`
+	DepartmentID     int64   `db:"department_id" json:"department_id"`
 	SortColumn       string  `db:"sort_column" json:"sort_column"`
 	SortOrder        string  `db:"sort_order" json:"sort_order"`
 	PaginationOffset int32   `db:"pagination_offset" json:"pagination_offset"`
@@ -88,6 +145,8 @@ type ListEquipmentRow struct {
 	ID            int64              `db:"id" json:"id"`
 	SerialNumber  string             `db:"serial_number" json:"serial_number"`
 	DeletedAt     pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
+	CompanyID     int64              `db:"company_id" json:"company_id"`
+	CompanyTitle  string             `db:"company_title" json:"company_title"`
 	ProfileID     int64              `db:"profile_id" json:"profile_id"`
 	ProfileTitle  string             `db:"profile_title" json:"profile_title"`
 	CategoryID    int64              `db:"category_id" json:"category_id"`
@@ -100,6 +159,7 @@ func (q *Queries) ListEquipment(ctx context.Context, arg *ListEquipmentParams) (
 		arg.WithDeleted,
 		arg.Search,
 		arg.Ids,
+		arg.DepartmentID,
 		arg.SortColumn,
 		arg.SortOrder,
 		arg.PaginationOffset,
@@ -116,6 +176,8 @@ func (q *Queries) ListEquipment(ctx context.Context, arg *ListEquipmentParams) (
 			&i.ID,
 			&i.SerialNumber,
 			&i.DeletedAt,
+			&i.CompanyID,
+			&i.CompanyTitle,
 			&i.ProfileID,
 			&i.ProfileTitle,
 			&i.CategoryID,
@@ -132,10 +194,129 @@ func (q *Queries) ListEquipment(ctx context.Context, arg *ListEquipmentParams) (
 	return items, nil
 }
 
+const listExistingSerialNumbers = `-- name: ListExistingSerialNumbers :many
+SELECT serial_number
+FROM equipments
+WHERE serial_number = ANY ($1::text[])
+`
+
+func (q *Queries) ListExistingSerialNumbers(ctx context.Context, serialNumbers []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listExistingSerialNumbers, serialNumbers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var serial_number string
+		if err := rows.Scan(&serial_number); err != nil {
+			return nil, err
+		}
+		items = append(items, serial_number)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSerialNumberDuplicates = `-- name: ListSerialNumberDuplicates :many
+WITH normalized AS (SELECT id,
+                           company_id,
+                           profile_id,
+                           serial_number,
+                           canonical_serial_number AS normalized_serial_number
+                    FROM equipments e
+                    WHERE deleted_at IS NULL
+                      AND ($1::bigint = 0 OR EXISTS (SELECT 1
+                                                                 FROM (SELECT to_department_id, to_employee_id
+                                                                       FROM locations
+                                                                       WHERE equipment_id = e.id
+                                                                       ORDER BY move_at DESC, id DESC
+                                                                       LIMIT 1) l
+                                                                          LEFT JOIN employees em ON em.id = l.to_employee_id
+                                                                 WHERE l.to_department_id = $1
+                                                                    OR em.department_id = $1)))
+SELECT n.normalized_serial_number::text AS normalized_serial_number,
+       n.id,
+       n.serial_number,
+       c.id                             AS company_id,
+       c.title                          AS company_title,
+       p.id                             AS profile_id,
+       p.title                          AS profile_title
+FROM normalized n
+         INNER JOIN companies c ON c.id = n.company_id
+         INNER JOIN profiles p ON p.id = n.profile_id
+WHERE n.normalized_serial_number IN (SELECT normalized_serial_number
+                                     FROM normalized
+                                     GROUP BY normalized_serial_number
+                                     HAVING COUNT(*) > 1)
+ORDER BY n.normalized_serial_number, n.id
+`
+
+type ListSerialNumberDuplicatesRow struct {
+	NormalizedSerialNumber string `db:"normalized_serial_number" json:"normalized_serial_number"`
+	ID                     int64  `db:"id" json:"id"`
+	SerialNumber           string `db:"serial_number" json:"serial_number"`
+	CompanyID              int64  `db:"company_id" json:"company_id"`
+	CompanyTitle           string `db:"company_title" json:"company_title"`
+	ProfileID              int64  `db:"profile_id" json:"profile_id"`
+	ProfileTitle           string `db:"profile_title" json:"profile_title"`
+}
+
+func (q *Queries) ListSerialNumberDuplicates(ctx context.Context, departmentID int64) ([]*ListSerialNumberDuplicatesRow, error) {
+	rows, err := q.db.Query(ctx, listSerialNumberDuplicates, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListSerialNumberDuplicatesRow
+	for rows.Next() {
+		var i ListSerialNumberDuplicatesRow
+		if err := rows.Scan(
+			&i.NormalizedSerialNumber,
+			&i.ID,
+			&i.SerialNumber,
+			&i.CompanyID,
+			&i.CompanyTitle,
+			&i.ProfileID,
+			&i.ProfileTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockEquipment = `-- name: LockEquipment :one
+SELECT id, deleted_at, written_off_at
+FROM equipments
+WHERE id = $1
+    FOR UPDATE
+`
+
+type LockEquipmentRow struct {
+	ID           int64              `db:"id" json:"id"`
+	DeletedAt    pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
+	WrittenOffAt pgtype.Timestamptz `db:"written_off_at" json:"written_off_at"`
+}
+
+func (q *Queries) LockEquipment(ctx context.Context, id int64) (*LockEquipmentRow, error) {
+	row := q.db.QueryRow(ctx, lockEquipment, id)
+	var i LockEquipmentRow
+	err := row.Scan(&i.ID, &i.DeletedAt, &i.WrittenOffAt)
+	return &i, err
+}
+
 const readEquipment = `-- name: ReadEquipment :one
 SELECT e.id,
        e.serial_number,
        e.deleted_at,
+       e.written_off_at,
        co.id    as company_id,
        co.title as company_title,
        p.id     as profile_id,
@@ -153,6 +334,7 @@ type ReadEquipmentRow struct {
 	ID            int64              `db:"id" json:"id"`
 	SerialNumber  string             `db:"serial_number" json:"serial_number"`
 	DeletedAt     pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
+	WrittenOffAt  pgtype.Timestamptz `db:"written_off_at" json:"written_off_at"`
 	CompanyID     int64              `db:"company_id" json:"company_id"`
 	CompanyTitle  string             `db:"company_title" json:"company_title"`
 	ProfileID     int64              `db:"profile_id" json:"profile_id"`
@@ -168,6 +350,7 @@ func (q *Queries) ReadEquipment(ctx context.Context, id int64) (*ReadEquipmentRo
 		&i.ID,
 		&i.SerialNumber,
 		&i.DeletedAt,
+		&i.WrittenOffAt,
 		&i.CompanyID,
 		&i.CompanyTitle,
 		&i.ProfileID,
@@ -189,6 +372,22 @@ func (q *Queries) RestoreEquipment(ctx context.Context, id int64) (pgconn.Comman
 	return q.db.Exec(ctx, restoreEquipment, id)
 }
 
+const setEquipmentWrittenOff = `-- name: SetEquipmentWrittenOff :execresult
+UPDATE equipments
+SET written_off_at = $1
+WHERE id = $2
+  AND written_off_at IS NULL
+`
+
+type SetEquipmentWrittenOffParams struct {
+	WrittenOffAt pgtype.Timestamptz `db:"written_off_at" json:"written_off_at"`
+	ID           int64              `db:"id" json:"id"`
+}
+
+func (q *Queries) SetEquipmentWrittenOff(ctx context.Context, arg *SetEquipmentWrittenOffParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, setEquipmentWrittenOff, arg.WrittenOffAt, arg.ID)
+}
+
 const updateEquipment = `-- name: UpdateEquipment :execresult
 UPDATE equipments
 SET company_id    = $1,
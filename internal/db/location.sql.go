@@ -39,53 +39,223 @@ func (q *Queries) AddToStorage(ctx context.Context, arg *AddToStorageParams) (pg
 	)
 }
 
+const deleteLocation = `-- name: DeleteLocation :execresult
+DELETE
+FROM locations
+WHERE id = $1
+`
+
+func (q *Queries) DeleteLocation(ctx context.Context, id int64) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteLocation, id)
+}
+
+const getCurrentLocation = `-- name: GetCurrentLocation :one
+SELECT l.id,
+       l.move_at,
+       l.move_code,
+       u.id           AS user_id,
+       u.username     AS user_username,
+       l.to_department_id,
+       td.title       AS to_department_title,
+       l.to_employee_id,
+       te.last_name   AS to_employee_last_name,
+       te.first_name  AS to_employee_first_name,
+       te.middle_name AS to_employee_middle_name,
+       l.to_contract_id,
+       tc.number      AS to_contract_number,
+       tc.address     AS to_contract_address
+FROM locations l
+         INNER JOIN users u ON u.id = l.user_id
+         LEFT JOIN departments td ON td.id = l.to_department_id
+         LEFT JOIN employees te ON te.id = l.to_employee_id
+         LEFT JOIN contracts tc ON tc.id = l.to_contract_id
+WHERE l.equipment_id = $1
+ORDER BY l.move_at DESC, l.id DESC
+LIMIT 1
+`
+
+type GetCurrentLocationRow struct {
+	ID                   int64              `db:"id" json:"id"`
+	MoveAt               pgtype.Timestamptz `db:"move_at" json:"move_at"`
+	MoveCode             string             `db:"move_code" json:"move_code"`
+	UserID               int64              `db:"user_id" json:"user_id"`
+	UserUsername         string             `db:"user_username" json:"user_username"`
+	ToDepartmentID       pgtype.Int8        `db:"to_department_id" json:"to_department_id"`
+	ToDepartmentTitle    pgtype.Text        `db:"to_department_title" json:"to_department_title"`
+	ToEmployeeID         pgtype.Int8        `db:"to_employee_id" json:"to_employee_id"`
+	ToEmployeeLastName   pgtype.Text        `db:"to_employee_last_name" json:"to_employee_last_name"`
+	ToEmployeeFirstName  pgtype.Text        `db:"to_employee_first_name" json:"to_employee_first_name"`
+	ToEmployeeMiddleName pgtype.Text        `db:"to_employee_middle_name" json:"to_employee_middle_name"`
+	ToContractID         pgtype.Int8        `db:"to_contract_id" json:"to_contract_id"`
+	ToContractNumber     pgtype.Text        `db:"to_contract_number" json:"to_contract_number"`
+	ToContractAddress    pgtype.Text        `db:"to_contract_address" json:"to_contract_address"`
+}
+
+func (q *Queries) GetCurrentLocation(ctx context.Context, equipmentID int64) (*GetCurrentLocationRow, error) {
+	row := q.db.QueryRow(ctx, getCurrentLocation, equipmentID)
+	var i GetCurrentLocationRow
+	err := row.Scan(
+		&i.ID,
+		&i.MoveAt,
+		&i.MoveCode,
+		&i.UserID,
+		&i.UserUsername,
+		&i.ToDepartmentID,
+		&i.ToDepartmentTitle,
+		&i.ToEmployeeID,
+		&i.ToEmployeeLastName,
+		&i.ToEmployeeFirstName,
+		&i.ToEmployeeMiddleName,
+		&i.ToContractID,
+		&i.ToContractNumber,
+		&i.ToContractAddress,
+	)
+	return &i, err
+}
+
+const getLastLocation = `-- name: GetLastLocation :one
+SELECT id, equipment_id, user_id, move_at, move_code, move_type, price, from_department_id, from_employee_id, from_contract_id, to_department_id, to_employee_id, to_contract_id, comment
+FROM locations
+WHERE equipment_id = $1
+ORDER BY move_at DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastLocation(ctx context.Context, equipmentID int64) (*Location, error) {
+	row := q.db.QueryRow(ctx, getLastLocation, equipmentID)
+	var i Location
+	err := row.Scan(
+		&i.ID,
+		&i.EquipmentID,
+		&i.UserID,
+		&i.MoveAt,
+		&i.MoveCode,
+		&i.MoveType,
+		&i.Price,
+		&i.FromDepartmentID,
+		&i.FromEmployeeID,
+		&i.FromContractID,
+		&i.ToDepartmentID,
+		&i.ToEmployeeID,
+		&i.ToContractID,
+		&i.Comment,
+	)
+	return &i, err
+}
+
+const getLocation = `-- name: GetLocation :one
+SELECT id, equipment_id, user_id, move_at, move_code, move_type, price, from_department_id, from_employee_id, from_contract_id, to_department_id, to_employee_id, to_contract_id, comment
+FROM locations
+WHERE id = $1
+`
+
+func (q *Queries) GetLocation(ctx context.Context, id int64) (*Location, error) {
+	row := q.db.QueryRow(ctx, getLocation, id)
+	var i Location
+	err := row.Scan(
+		&i.ID,
+		&i.EquipmentID,
+		&i.UserID,
+		&i.MoveAt,
+		&i.MoveCode,
+		&i.MoveType,
+		&i.Price,
+		&i.FromDepartmentID,
+		&i.FromEmployeeID,
+		&i.FromContractID,
+		&i.ToDepartmentID,
+		&i.ToEmployeeID,
+		&i.ToContractID,
+		&i.Comment,
+	)
+	return &i, err
+}
+
 const listEquipmentFromLocation = `-- name: ListEquipmentFromLocation :many
-select e.id,
+SELECT e.id,
        e.serial_number,
        e.company_title,
        e.profile_title,
        e.category_title,
-       e.total
-from (SELECT DISTINCT ON (l.equipment_id) eq.id,
+       r.id             AS reservation_id,
+       r.expires_at     AS reservation_expires_at,
+       COUNT(*) OVER () AS total
+FROM (SELECT DISTINCT ON (l.equipment_id) eq.id,
                                           eq.serial_number,
-                                          co.title         AS company_title,
-                                          p.title          AS profile_title,
-                                          ca.title         AS category_title,
+                                          co.title AS company_title,
+                                          p.title  AS profile_title,
+                                          ca.title AS category_title,
                                           l.to_department_id,
                                           l.to_employee_id,
                                           l.to_contract_id,
-                                          COUNT(*) OVER () AS total
+                                          l.move_code
       FROM locations l
                LEFT JOIN equipments eq ON eq.id = l.equipment_id
                LEFT JOIN companies co ON co.id = eq.company_id
                LEFT JOIN profiles p ON p.id = eq.profile_id
                LEFT JOIN categories ca ON ca.id = p.category_id
       WHERE eq.deleted_at IS NULL
+        AND eq.written_off_at IS NULL
       ORDER BY l.equipment_id, l.move_at DESC, l.id DESC) e
-WHERE (
-    $1::bigint = 0
+         LEFT JOIN reservations r ON r.equipment_id = e.id
+    AND r.status = 'active'
+    AND r.expires_at > now()
+WHERE e.move_code <> 'SentToRepair'
+  AND (
+    ($1::text = 'employee' AND e.to_employee_id = $2::bigint)
+        OR ($1 = 'contract' AND e.to_contract_id = $2)
+        OR ($1 NOT IN ('employee', 'contract') AND $2 = 0
         AND e.to_department_id IS NULL
         AND e.to_employee_id IS NULL
-        AND e.to_contract_id IS NULL
-    )
-   OR (
-    $1::bigint > 0
-        AND e.to_department_id = $1
+        AND e.to_contract_id IS NULL)
+        OR ($1 NOT IN ('employee', 'contract') AND $2 > 0
+        AND e.to_department_id = $2)
     )
-ORDER BY e.profile_title, e.serial_number
+  AND ($3::text = '' OR (e.serial_number || ' ' || e.profile_title || ' ' || e.category_title) ILIKE '%' || $3 || '%')
+ORDER BY CASE WHEN $4::text = 'id' AND $5::text = 'asc' THEN e.id::text END,
+         CASE WHEN $4 = 'id' AND $5 = 'desc' THEN e.id::text END DESC,
+         CASE WHEN $4 = 'serial_number' AND $5 = 'asc' THEN e.serial_number END,
+         CASE WHEN $4 = 'serial_number' AND $5 = 'desc' THEN e.serial_number END DESC,
+         CASE WHEN $4 = 'company_title' AND $5 = 'asc' THEN e.company_title END,
+         CASE WHEN $4 = 'company_title' AND $5 = 'desc' THEN e.company_title END DESC,
+         CASE WHEN $4 = 'profile_title' AND $5 = 'asc' THEN e.profile_title END,
+         CASE WHEN $4 = 'profile_title' AND $5 = 'desc' THEN e.profile_title END DESC,
+         CASE WHEN $4 = 'category_title' AND $5 = 'asc' THEN e.category_title END,
+         CASE WHEN $4 = 'category_title' AND $5 = 'desc' THEN e.category_title END DESC
+LIMIT $7 OFFSET $6
 `
 
+type ListEquipmentFromLocationParams struct {
+	Param            string `db:"param" json:"param"`
+	ParamID          int64  `db:"param_id" json:"param_id"`
+	Search           string `db:"search" json:"search"`
+	SortColumn       string `db:"sort_column" json:"sort_column"`
+	SortOrder        string `db:"sort_order" json:"sort_order"`
+	PaginationOffset int32  `db:"pagination_offset" json:"pagination_offset"`
+	PaginationLimit  int32  `db:"pagination_limit" json:"pagination_limit"`
+}
+
 type ListEquipmentFromLocationRow struct {
-	ID            pgtype.Int8 `db:"id" json:"id"`
-	SerialNumber  pgtype.Text `db:"serial_number" json:"serial_number"`
-	CompanyTitle  pgtype.Text `db:"company_title" json:"company_title"`
-	ProfileTitle  pgtype.Text `db:"profile_title" json:"profile_title"`
-	CategoryTitle pgtype.Text `db:"category_title" json:"category_title"`
-	Total         int64       `db:"total" json:"total"`
+	ID                   pgtype.Int8        `db:"id" json:"id"`
+	SerialNumber         pgtype.Text        `db:"serial_number" json:"serial_number"`
+	CompanyTitle         pgtype.Text        `db:"company_title" json:"company_title"`
+	ProfileTitle         pgtype.Text        `db:"profile_title" json:"profile_title"`
+	CategoryTitle        pgtype.Text        `db:"category_title" json:"category_title"`
+	ReservationID        pgtype.Int8        `db:"reservation_id" json:"reservation_id"`
+	ReservationExpiresAt pgtype.Timestamptz `db:"reservation_expires_at" json:"reservation_expires_at"`
+	Total                int64              `db:"total" json:"total"`
 }
 
-func (q *Queries) ListEquipmentFromLocation(ctx context.Context, toDepartmentID int64) ([]*ListEquipmentFromLocationRow, error) {
-	rows, err := q.db.Query(ctx, listEquipmentFromLocation, toDepartmentID)
+func (q *Queries) ListEquipmentFromLocation(ctx context.Context, arg *ListEquipmentFromLocationParams) ([]*ListEquipmentFromLocationRow, error) {
+	rows, err := q.db.Query(ctx, listEquipmentFromLocation,
+		arg.Param,
+		arg.ParamID,
+		arg.Search,
+		arg.SortColumn,
+		arg.SortOrder,
+		arg.PaginationOffset,
+		arg.PaginationLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +269,137 @@ func (q *Queries) ListEquipmentFromLocation(ctx context.Context, toDepartmentID
 			&i.CompanyTitle,
 			&i.ProfileTitle,
 			&i.CategoryTitle,
+			&i.ReservationID,
+			&i.ReservationExpiresAt,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLocationHistory = `-- name: ListLocationHistory :many
+SELECT l.id,
+       l.move_at,
+       l.move_code,
+       l.move_type,
+       l.price,
+       l.comment,
+       u.id             AS user_id,
+       u.username       AS user_username,
+       l.from_department_id,
+       fd.title         AS from_department_title,
+       l.from_employee_id,
+       fe.last_name     AS from_employee_last_name,
+       fe.first_name    AS from_employee_first_name,
+       fe.middle_name   AS from_employee_middle_name,
+       l.from_contract_id,
+       fc.number        AS from_contract_number,
+       l.to_department_id,
+       td.title         AS to_department_title,
+       l.to_employee_id,
+       te.last_name     AS to_employee_last_name,
+       te.first_name    AS to_employee_first_name,
+       te.middle_name   AS to_employee_middle_name,
+       l.to_contract_id,
+       tc.number        AS to_contract_number,
+       r.id             AS replace_id,
+       r.move_in_id     AS replace_move_in_id,
+       r.move_out_id    AS replace_move_out_id,
+       COUNT(*) OVER () AS total
+FROM locations l
+         INNER JOIN users u ON u.id = l.user_id
+         LEFT JOIN departments fd ON fd.id = l.from_department_id
+         LEFT JOIN employees fe ON fe.id = l.from_employee_id
+         LEFT JOIN contracts fc ON fc.id = l.from_contract_id
+         LEFT JOIN departments td ON td.id = l.to_department_id
+         LEFT JOIN employees te ON te.id = l.to_employee_id
+         LEFT JOIN contracts tc ON tc.id = l.to_contract_id
+         LEFT JOIN replaces r ON r.move_in_id = l.id OR r.move_out_id = l.id
+WHERE l.equipment_id = $1
+ORDER BY l.move_at, l.id
+LIMIT $3 OFFSET $2
+`
+
+type ListLocationHistoryParams struct {
+	EquipmentID      int64 `db:"equipment_id" json:"equipment_id"`
+	PaginationOffset int32 `db:"pagination_offset" json:"pagination_offset"`
+	PaginationLimit  int32 `db:"pagination_limit" json:"pagination_limit"`
+}
+
+type ListLocationHistoryRow struct {
+	ID                     int64              `db:"id" json:"id"`
+	MoveAt                 pgtype.Timestamptz `db:"move_at" json:"move_at"`
+	MoveCode               string             `db:"move_code" json:"move_code"`
+	MoveType               pgtype.Text        `db:"move_type" json:"move_type"`
+	Price                  pgtype.Text        `db:"price" json:"price"`
+	Comment                pgtype.Text        `db:"comment" json:"comment"`
+	UserID                 int64              `db:"user_id" json:"user_id"`
+	UserUsername           string             `db:"user_username" json:"user_username"`
+	FromDepartmentID       pgtype.Int8        `db:"from_department_id" json:"from_department_id"`
+	FromDepartmentTitle    pgtype.Text        `db:"from_department_title" json:"from_department_title"`
+	FromEmployeeID         pgtype.Int8        `db:"from_employee_id" json:"from_employee_id"`
+	FromEmployeeLastName   pgtype.Text        `db:"from_employee_last_name" json:"from_employee_last_name"`
+	FromEmployeeFirstName  pgtype.Text        `db:"from_employee_first_name" json:"from_employee_first_name"`
+	FromEmployeeMiddleName pgtype.Text        `db:"from_employee_middle_name" json:"from_employee_middle_name"`
+	FromContractID         pgtype.Int8        `db:"from_contract_id" json:"from_contract_id"`
+	FromContractNumber     pgtype.Text        `db:"from_contract_number" json:"from_contract_number"`
+	ToDepartmentID         pgtype.Int8        `db:"to_department_id" json:"to_department_id"`
+	ToDepartmentTitle      pgtype.Text        `db:"to_department_title" json:"to_department_title"`
+	ToEmployeeID           pgtype.Int8        `db:"to_employee_id" json:"to_employee_id"`
+	ToEmployeeLastName     pgtype.Text        `db:"to_employee_last_name" json:"to_employee_last_name"`
+	ToEmployeeFirstName    pgtype.Text        `db:"to_employee_first_name" json:"to_employee_first_name"`
+	ToEmployeeMiddleName   pgtype.Text        `db:"to_employee_middle_name" json:"to_employee_middle_name"`
+	ToContractID           pgtype.Int8        `db:"to_contract_id" json:"to_contract_id"`
+	ToContractNumber       pgtype.Text        `db:"to_contract_number" json:"to_contract_number"`
+	ReplaceID              pgtype.Int8        `db:"replace_id" json:"replace_id"`
+	ReplaceMoveInID        pgtype.Int8        `db:"replace_move_in_id" json:"replace_move_in_id"`
+	ReplaceMoveOutID       pgtype.Int8        `db:"replace_move_out_id" json:"replace_move_out_id"`
+	Total                  int64              `db:"total" json:"total"`
+}
+
+func (q *Queries) ListLocationHistory(ctx context.Context, arg *ListLocationHistoryParams) ([]*ListLocationHistoryRow, error) {
+	rows, err := q.db.Query(ctx, listLocationHistory, arg.EquipmentID, arg.PaginationOffset, arg.PaginationLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListLocationHistoryRow
+	for rows.Next() {
+		var i ListLocationHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MoveAt,
+			&i.MoveCode,
+			&i.MoveType,
+			&i.Price,
+			&i.Comment,
+			&i.UserID,
+			&i.UserUsername,
+			&i.FromDepartmentID,
+			&i.FromDepartmentTitle,
+			&i.FromEmployeeID,
+			&i.FromEmployeeLastName,
+			&i.FromEmployeeFirstName,
+			&i.FromEmployeeMiddleName,
+			&i.FromContractID,
+			&i.FromContractNumber,
+			&i.ToDepartmentID,
+			&i.ToDepartmentTitle,
+			&i.ToEmployeeID,
+			&i.ToEmployeeLastName,
+			&i.ToEmployeeFirstName,
+			&i.ToEmployeeMiddleName,
+			&i.ToContractID,
+			&i.ToContractNumber,
+			&i.ReplaceID,
+			&i.ReplaceMoveInID,
+			&i.ReplaceMoveOutID,
 			&i.Total,
 		); err != nil {
 			return nil, err
@@ -111,17 +412,62 @@ func (q *Queries) ListEquipmentFromLocation(ctx context.Context, toDepartmentID
 	return items, nil
 }
 
-const moveToLocation = `-- name: MoveToLocation :execresult
+const listLocationsOfEquipments = `-- name: ListLocationsOfEquipments :many
+SELECT id, equipment_id, user_id, move_at, move_code, move_type, price, from_department_id, from_employee_id, from_contract_id, to_department_id, to_employee_id, to_contract_id, comment
+FROM locations
+WHERE equipment_id = ANY ($1::bigint[])
+ORDER BY move_at, id
+`
+
+func (q *Queries) ListLocationsOfEquipments(ctx context.Context, ids []int64) ([]*Location, error) {
+	rows, err := q.db.Query(ctx, listLocationsOfEquipments, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Location
+	for rows.Next() {
+		var i Location
+		if err := rows.Scan(
+			&i.ID,
+			&i.EquipmentID,
+			&i.UserID,
+			&i.MoveAt,
+			&i.MoveCode,
+			&i.MoveType,
+			&i.Price,
+			&i.FromDepartmentID,
+			&i.FromEmployeeID,
+			&i.FromContractID,
+			&i.ToDepartmentID,
+			&i.ToEmployeeID,
+			&i.ToContractID,
+			&i.Comment,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveToLocation = `-- name: MoveToLocation :one
 INSERT INTO locations (equipment_id,
                        user_id,
                        move_at,
                        move_code,
+                       move_type,
+                       price,
                        from_department_id,
                        from_employee_id,
                        from_contract_id,
                        to_department_id,
                        to_employee_id,
-                       to_contract_id)
+                       to_contract_id,
+                       comment)
 VALUES ($1,
         $2,
         $3,
@@ -131,7 +477,11 @@ VALUES ($1,
         $7,
         $8,
         $9,
-        $10)
+        $10,
+        $11,
+        $12,
+        $13)
+RETURNING id
 `
 
 type MoveToLocationParams struct {
@@ -139,25 +489,49 @@ type MoveToLocationParams struct {
 	UserID           int64              `db:"user_id" json:"user_id"`
 	MoveAt           pgtype.Timestamptz `db:"move_at" json:"move_at"`
 	MoveCode         string             `db:"move_code" json:"move_code"`
+	MoveType         pgtype.Text        `db:"move_type" json:"move_type"`
+	Price            pgtype.Text        `db:"price" json:"price"`
 	FromDepartmentID pgtype.Int8        `db:"from_department_id" json:"from_department_id"`
 	FromEmployeeID   pgtype.Int8        `db:"from_employee_id" json:"from_employee_id"`
 	FromContractID   pgtype.Int8        `db:"from_contract_id" json:"from_contract_id"`
 	ToDepartmentID   pgtype.Int8        `db:"to_department_id" json:"to_department_id"`
 	ToEmployeeID     pgtype.Int8        `db:"to_employee_id" json:"to_employee_id"`
 	ToContractID     pgtype.Int8        `db:"to_contract_id" json:"to_contract_id"`
+	Comment          pgtype.Text        `db:"comment" json:"comment"`
 }
 
-func (q *Queries) MoveToLocation(ctx context.Context, arg *MoveToLocationParams) (pgconn.CommandTag, error) {
-	return q.db.Exec(ctx, moveToLocation,
+func (q *Queries) MoveToLocation(ctx context.Context, arg *MoveToLocationParams) (int64, error) {
+	row := q.db.QueryRow(ctx, moveToLocation,
 		arg.EquipmentID,
 		arg.UserID,
 		arg.MoveAt,
 		arg.MoveCode,
+		arg.MoveType,
+		arg.Price,
 		arg.FromDepartmentID,
 		arg.FromEmployeeID,
 		arg.FromContractID,
 		arg.ToDepartmentID,
 		arg.ToEmployeeID,
 		arg.ToContractID,
+		arg.Comment,
 	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const reparentLocations = `-- name: ReparentLocations :execresult
+UPDATE locations
+SET equipment_id = $1
+WHERE equipment_id = $2
+`
+
+type ReparentLocationsParams struct {
+	EquipmentID int64 `db:"equipment_id" json:"equipment_id"`
+	DuplicateID int64 `db:"duplicate_id" json:"duplicate_id"`
+}
+
+func (q *Queries) ReparentLocations(ctx context.Context, arg *ReparentLocationsParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, reparentLocations, arg.EquipmentID, arg.DuplicateID)
 }
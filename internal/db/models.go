@@ -5,9 +5,23 @@
 package queries
 
 import (
+	"net/netip"
+
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditLog struct {
+	ID        int64              `db:"id" json:"id"`
+	UserID    pgtype.Int8        `db:"user_id" json:"user_id"`
+	Entity    string             `db:"entity" json:"entity"`
+	EntityID  int64              `db:"entity_id" json:"entity_id"`
+	Action    string             `db:"action" json:"action"`
+	Diff      []byte             `db:"diff" json:"diff"`
+	RequestID pgtype.Text        `db:"request_id" json:"request_id"`
+	ClientIp  *netip.Addr        `db:"client_ip" json:"client_ip"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
 type Category struct {
 	ID        int64              `db:"id" json:"id"`
 	Title     string             `db:"title" json:"title"`
@@ -44,11 +58,22 @@ type Employee struct {
 }
 
 type Equipment struct {
-	ID           int64              `db:"id" json:"id"`
-	SerialNumber string             `db:"serial_number" json:"serial_number"`
-	ProfileID    int64              `db:"profile_id" json:"profile_id"`
-	DeletedAt    pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
-	CompanyID    int64              `db:"company_id" json:"company_id"`
+	ID                    int64              `db:"id" json:"id"`
+	SerialNumber          string             `db:"serial_number" json:"serial_number"`
+	ProfileID             int64              `db:"profile_id" json:"profile_id"`
+	DeletedAt             pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
+	CompanyID             int64              `db:"company_id" json:"company_id"`
+	WrittenOffAt          pgtype.Timestamptz `db:"written_off_at" json:"written_off_at"`
+	CanonicalSerialNumber string             `db:"canonical_serial_number" json:"canonical_serial_number"`
+}
+
+type EquipmentMerge struct {
+	ID          int64              `db:"id" json:"id"`
+	EquipmentID int64              `db:"equipment_id" json:"equipment_id"`
+	DuplicateID int64              `db:"duplicate_id" json:"duplicate_id"`
+	MergedBy    int64              `db:"merged_by" json:"merged_by"`
+	MergedAt    pgtype.Timestamptz `db:"merged_at" json:"merged_at"`
+	Locations   int64              `db:"locations" json:"locations"`
 }
 
 type Location struct {
@@ -68,11 +93,50 @@ type Location struct {
 	Comment          pgtype.Text        `db:"comment" json:"comment"`
 }
 
+type LocationReversal struct {
+	ID          int64              `db:"id" json:"id"`
+	LocationID  int64              `db:"location_id" json:"location_id"`
+	ReplaceID   pgtype.Int8        `db:"replace_id" json:"replace_id"`
+	EquipmentID int64              `db:"equipment_id" json:"equipment_id"`
+	RevertedBy  int64              `db:"reverted_by" json:"reverted_by"`
+	RevertedAt  pgtype.Timestamptz `db:"reverted_at" json:"reverted_at"`
+	Location    []byte             `db:"location" json:"location"`
+}
+
+type OutboxEvent struct {
+	ID            int64              `db:"id" json:"id"`
+	EventType     string             `db:"event_type" json:"event_type"`
+	EntityID      int64              `db:"entity_id" json:"entity_id"`
+	Payload       []byte             `db:"payload" json:"payload"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	Attempts      int32              `db:"attempts" json:"attempts"`
+	LastError     pgtype.Text        `db:"last_error" json:"last_error"`
+	NextAttemptAt pgtype.Timestamptz `db:"next_attempt_at" json:"next_attempt_at"`
+	PublishedAt   pgtype.Timestamptz `db:"published_at" json:"published_at"`
+}
+
 type Profile struct {
 	ID         int64              `db:"id" json:"id"`
 	Title      string             `db:"title" json:"title"`
 	CategoryID int64              `db:"category_id" json:"category_id"`
 	DeletedAt  pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
+	SerialRule []string           `db:"serial_rule" json:"serial_rule"`
+}
+
+type RepairOrder struct {
+	ID               int64              `db:"id" json:"id"`
+	EquipmentID      int64              `db:"equipment_id" json:"equipment_id"`
+	Status           string             `db:"status" json:"status"`
+	Vendor           pgtype.Text        `db:"vendor" json:"vendor"`
+	Cost             pgtype.Text        `db:"cost" json:"cost"`
+	Notes            pgtype.Text        `db:"notes" json:"notes"`
+	SentBy           int64              `db:"sent_by" json:"sent_by"`
+	SentAt           pgtype.Timestamptz `db:"sent_at" json:"sent_at"`
+	SendLocationID   int64              `db:"send_location_id" json:"send_location_id"`
+	ReturnedBy       pgtype.Int8        `db:"returned_by" json:"returned_by"`
+	ReturnedAt       pgtype.Timestamptz `db:"returned_at" json:"returned_at"`
+	ReturnLocationID pgtype.Int8        `db:"return_location_id" json:"return_location_id"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
 type Replace struct {
@@ -81,6 +145,55 @@ type Replace struct {
 	MoveOutID int64 `db:"move_out_id" json:"move_out_id"`
 }
 
+type Reservation struct {
+	ID             int64              `db:"id" json:"id"`
+	EquipmentID    int64              `db:"equipment_id" json:"equipment_id"`
+	ToDepartmentID pgtype.Int8        `db:"to_department_id" json:"to_department_id"`
+	ToEmployeeID   pgtype.Int8        `db:"to_employee_id" json:"to_employee_id"`
+	ToContractID   pgtype.Int8        `db:"to_contract_id" json:"to_contract_id"`
+	ExpiresAt      pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	Comment        pgtype.Text        `db:"comment" json:"comment"`
+	Status         string             `db:"status" json:"status"`
+	ReservedBy     int64              `db:"reserved_by" json:"reserved_by"`
+	ReservedAt     pgtype.Timestamptz `db:"reserved_at" json:"reserved_at"`
+	ClosedBy       pgtype.Int8        `db:"closed_by" json:"closed_by"`
+	ClosedAt       pgtype.Timestamptz `db:"closed_at" json:"closed_at"`
+	LocationID     pgtype.Int8        `db:"location_id" json:"location_id"`
+}
+
+type StockMinimum struct {
+	ID           int64              `db:"id" json:"id"`
+	ProfileID    int64              `db:"profile_id" json:"profile_id"`
+	DepartmentID pgtype.Int8        `db:"department_id" json:"department_id"`
+	MinQuantity  int32              `db:"min_quantity" json:"min_quantity"`
+	LowSince     pgtype.Timestamptz `db:"low_since" json:"low_since"`
+}
+
+type Stocktaking struct {
+	ID           int64              `db:"id" json:"id"`
+	DepartmentID pgtype.Int8        `db:"department_id" json:"department_id"`
+	EmployeeID   pgtype.Int8        `db:"employee_id" json:"employee_id"`
+	OpenedBy     int64              `db:"opened_by" json:"opened_by"`
+	OpenedAt     pgtype.Timestamptz `db:"opened_at" json:"opened_at"`
+	ClosedBy     pgtype.Int8        `db:"closed_by" json:"closed_by"`
+	ClosedAt     pgtype.Timestamptz `db:"closed_at" json:"closed_at"`
+	AppliedAt    pgtype.Timestamptz `db:"applied_at" json:"applied_at"`
+}
+
+type StocktakingItem struct {
+	StocktakingID int64 `db:"stocktaking_id" json:"stocktaking_id"`
+	EquipmentID   int64 `db:"equipment_id" json:"equipment_id"`
+}
+
+type StocktakingScan struct {
+	ID            int64              `db:"id" json:"id"`
+	StocktakingID int64              `db:"stocktaking_id" json:"stocktaking_id"`
+	SerialNumber  string             `db:"serial_number" json:"serial_number"`
+	EquipmentID   pgtype.Int8        `db:"equipment_id" json:"equipment_id"`
+	ScannedBy     int64              `db:"scanned_by" json:"scanned_by"`
+	ScannedAt     pgtype.Timestamptz `db:"scanned_at" json:"scanned_at"`
+}
+
 type User struct {
 	ID           int64              `db:"id" json:"id"`
 	Username     string             `db:"username" json:"username"`
@@ -91,3 +204,16 @@ type User struct {
 	LastLoginAt  pgtype.Timestamptz `db:"last_login_at" json:"last_login_at"`
 	EmployeeID   pgtype.Int8        `db:"employee_id" json:"employee_id"`
 }
+
+type WriteOff struct {
+	ID          int64              `db:"id" json:"id"`
+	EquipmentID int64              `db:"equipment_id" json:"equipment_id"`
+	Reason      string             `db:"reason" json:"reason"`
+	Comment     pgtype.Text        `db:"comment" json:"comment"`
+	Status      string             `db:"status" json:"status"`
+	RequestedBy int64              `db:"requested_by" json:"requested_by"`
+	RequestedAt pgtype.Timestamptz `db:"requested_at" json:"requested_at"`
+	DecidedBy   pgtype.Int8        `db:"decided_by" json:"decided_by"`
+	DecidedAt   pgtype.Timestamptz `db:"decided_at" json:"decided_at"`
+	LocationID  pgtype.Int8        `db:"location_id" json:"location_id"`
+}
@@ -13,24 +13,26 @@ import (
 )
 
 const createProfile = `-- name: CreateProfile :one
-INSERT INTO profiles (title, category_id)
-VALUES ($1, $2)
-RETURNING id, title, category_id, deleted_at
+INSERT INTO profiles (title, category_id, serial_rule)
+VALUES ($1, $2, $3)
+RETURNING id, title, category_id, deleted_at, serial_rule
 `
 
 type CreateProfileParams struct {
-	Title      string `db:"title" json:"title"`
-	CategoryID int64  `db:"category_id" json:"category_id"`
+	Title      string   `db:"title" json:"title"`
+	CategoryID int64    `db:"category_id" json:"category_id"`
+	SerialRule []string `db:"serial_rule" json:"serial_rule"`
 }
 
 func (q *Queries) CreateProfile(ctx context.Context, arg *CreateProfileParams) (*Profile, error) {
-	row := q.db.QueryRow(ctx, createProfile, arg.Title, arg.CategoryID)
+	row := q.db.QueryRow(ctx, createProfile, arg.Title, arg.CategoryID, arg.SerialRule)
 	var i Profile
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
 		&i.CategoryID,
 		&i.DeletedAt,
+		&i.SerialRule,
 	)
 	return &i, err
 }
@@ -49,6 +51,7 @@ func (q *Queries) DeleteProfile(ctx context.Context, id int64) (pgconn.CommandTa
 const listProfile = `-- name: ListProfile :many
 SELECT p.id,
        p.title,
+       p.serial_rule,
        p.deleted_at,
        c.id             as category_id,
        c.title          as category_title,
@@ -80,6 +83,7 @@ type ListProfileParams struct {
 type ListProfileRow struct {
 	ID            int64              `db:"id" json:"id"`
 	Title         string             `db:"title" json:"title"`
+	SerialRule    []string           `db:"serial_rule" json:"serial_rule"`
 	DeletedAt     pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
 	CategoryID    int64              `db:"category_id" json:"category_id"`
 	CategoryTitle string             `db:"category_title" json:"category_title"`
@@ -106,6 +110,7 @@ func (q *Queries) ListProfile(ctx context.Context, arg *ListProfileParams) ([]*L
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
+			&i.SerialRule,
 			&i.DeletedAt,
 			&i.CategoryID,
 			&i.CategoryTitle,
@@ -121,9 +126,73 @@ func (q *Queries) ListProfile(ctx context.Context, arg *ListProfileParams) ([]*L
 	return items, nil
 }
 
+const listProfileByTitles = `-- name: ListProfileByTitles :many
+SELECT id, title
+FROM profiles
+WHERE title = ANY ($1::text[])
+  AND deleted_at IS NULL
+`
+
+type ListProfileByTitlesRow struct {
+	ID    int64  `db:"id" json:"id"`
+	Title string `db:"title" json:"title"`
+}
+
+func (q *Queries) ListProfileByTitles(ctx context.Context, titles []string) ([]*ListProfileByTitlesRow, error) {
+	rows, err := q.db.Query(ctx, listProfileByTitles, titles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListProfileByTitlesRow
+	for rows.Next() {
+		var i ListProfileByTitlesRow
+		if err := rows.Scan(&i.ID, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProfileSerialRules = `-- name: ListProfileSerialRules :many
+SELECT id, serial_rule
+FROM profiles
+WHERE id = ANY ($1::bigint[])
+`
+
+type ListProfileSerialRulesRow struct {
+	ID         int64    `db:"id" json:"id"`
+	SerialRule []string `db:"serial_rule" json:"serial_rule"`
+}
+
+func (q *Queries) ListProfileSerialRules(ctx context.Context, ids []int64) ([]*ListProfileSerialRulesRow, error) {
+	rows, err := q.db.Query(ctx, listProfileSerialRules, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListProfileSerialRulesRow
+	for rows.Next() {
+		var i ListProfileSerialRulesRow
+		if err := rows.Scan(&i.ID, &i.SerialRule); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readProfile = `-- name: ReadProfile :one
 SELECT p.id,
        p.title,
+       p.serial_rule,
        p.deleted_at,
        c.id    as category_id,
        c.title as category_title
@@ -135,6 +204,7 @@ WHERE p.id = $1
 type ReadProfileRow struct {
 	ID            int64              `db:"id" json:"id"`
 	Title         string             `db:"title" json:"title"`
+	SerialRule    []string           `db:"serial_rule" json:"serial_rule"`
 	DeletedAt     pgtype.Timestamptz `db:"deleted_at" json:"deleted_at"`
 	CategoryID    int64              `db:"category_id" json:"category_id"`
 	CategoryTitle string             `db:"category_title" json:"category_title"`
@@ -146,6 +216,7 @@ func (q *Queries) ReadProfile(ctx context.Context, id int64) (*ReadProfileRow, e
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
+		&i.SerialRule,
 		&i.DeletedAt,
 		&i.CategoryID,
 		&i.CategoryTitle,
@@ -167,17 +238,24 @@ func (q *Queries) RestoreProfile(ctx context.Context, id int64) (pgconn.CommandT
 const updateProfile = `-- name: UpdateProfile :execresult
 UPDATE profiles
 SET title       = $1,
-    category_id = $2
-WHERE id = $3
-  AND (title != $1 OR category_id != $2)
+    category_id = $2,
+    serial_rule = $3
+WHERE id = $4
+  AND (title != $1 OR category_id != $2 OR serial_rule != $3)
 `
 
 type UpdateProfileParams struct {
-	Title      string `db:"title" json:"title"`
-	CategoryID int64  `db:"category_id" json:"category_id"`
-	ID         int64  `db:"id" json:"id"`
+	Title      string   `db:"title" json:"title"`
+	CategoryID int64    `db:"category_id" json:"category_id"`
+	SerialRule []string `db:"serial_rule" json:"serial_rule"`
+	ID         int64    `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateProfile(ctx context.Context, arg *UpdateProfileParams) (pgconn.CommandTag, error) {
-	return q.db.Exec(ctx, updateProfile, arg.Title, arg.CategoryID, arg.ID)
+	return q.db.Exec(ctx, updateProfile,
+		arg.Title,
+		arg.CategoryID,
+		arg.SerialRule,
+		arg.ID,
+	)
 }
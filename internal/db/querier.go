@@ -8,38 +8,97 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddToStorage(ctx context.Context, arg *AddToStorageParams) (pgconn.CommandTag, error)
+	CancelReservation(ctx context.Context, arg *CancelReservationParams) (pgconn.CommandTag, error)
+	ClearStockMinimumsLow(ctx context.Context, ids []int64) error
+	CloseStocktaking(ctx context.Context, arg *CloseStocktakingParams) (pgconn.CommandTag, error)
+	ConsumeReservation(ctx context.Context, arg *ConsumeReservationParams) error
+	CountReplacesBetween(ctx context.Context, arg *CountReplacesBetweenParams) (int64, error)
+	CountReservedBetween(ctx context.Context, arg *CountReservedBetweenParams) (int64, error)
+	CreateAuditLog(ctx context.Context, arg *CreateAuditLogParams) error
 	CreateCategory(ctx context.Context, title string) (*Category, error)
 	CreateCompany(ctx context.Context, title string) (*Company, error)
 	CreateContract(ctx context.Context, arg *CreateContractParams) (*Contract, error)
 	CreateDepartment(ctx context.Context, title string) (*Department, error)
 	CreateEmployee(ctx context.Context, arg *CreateEmployeeParams) (*Employee, error)
 	CreateEquipment(ctx context.Context, arg *CreateEquipmentParams) (*Equipment, error)
+	CreateEquipmentMerge(ctx context.Context, arg *CreateEquipmentMergeParams) (*EquipmentMerge, error)
+	CreateLocationReversal(ctx context.Context, arg *CreateLocationReversalParams) (*LocationReversal, error)
+	CreateOutboxEvent(ctx context.Context, arg *CreateOutboxEventParams) error
 	CreateProfile(ctx context.Context, arg *CreateProfileParams) (*Profile, error)
+	CreateRepairOrder(ctx context.Context, arg *CreateRepairOrderParams) (int64, error)
+	CreateReplace(ctx context.Context, arg *CreateReplaceParams) (*Replace, error)
+	CreateReservation(ctx context.Context, arg *CreateReservationParams) (int64, error)
+	CreateStocktaking(ctx context.Context, arg *CreateStocktakingParams) (*Stocktaking, error)
+	CreateStocktakingItems(ctx context.Context, arg *CreateStocktakingItemsParams) (pgconn.CommandTag, error)
+	CreateStocktakingScans(ctx context.Context, arg *CreateStocktakingScansParams) (pgconn.CommandTag, error)
 	CreateUser(ctx context.Context, arg *CreateUserParams) (*User, error)
+	CreateWriteOff(ctx context.Context, arg *CreateWriteOffParams) (int64, error)
+	DecideWriteOff(ctx context.Context, arg *DecideWriteOffParams) (pgconn.CommandTag, error)
 	DeleteCategory(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	DeleteCompany(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	DeleteContract(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	DeleteDepartment(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	DeleteEmployee(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	DeleteEquipment(ctx context.Context, id int64) (pgconn.CommandTag, error)
+	DeleteLocation(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	DeleteProfile(ctx context.Context, id int64) (pgconn.CommandTag, error)
+	DeleteStockMinimum(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	DeleteUser(ctx context.Context, id int64) (pgconn.CommandTag, error)
+	DepartmentBalanceReport(ctx context.Context, arg *DepartmentBalanceReportParams) ([]*DepartmentBalanceReportRow, error)
+	ExpireReservations(ctx context.Context, equipmentID int64) error
+	GetActiveReservation(ctx context.Context, equipmentID int64) (*Reservation, error)
 	GetByUsernameUser(ctx context.Context, id string) (*GetByUsernameUserRow, error)
+	GetCurrentLocation(ctx context.Context, equipmentID int64) (*GetCurrentLocationRow, error)
+	GetEquipmentDimensions(ctx context.Context, id int64) (*GetEquipmentDimensionsRow, error)
+	GetEquipmentIDBySerialNumber(ctx context.Context, arg *GetEquipmentIDBySerialNumberParams) (int64, error)
+	GetLastLocation(ctx context.Context, equipmentID int64) (*Location, error)
+	GetLocation(ctx context.Context, id int64) (*Location, error)
 	GetPasswordHashUser(ctx context.Context, id int64) (string, error)
+	GetRepairOrder(ctx context.Context, id int64) (*GetRepairOrderRow, error)
+	GetReplaceByLocation(ctx context.Context, locationID int64) (*Replace, error)
+	GetReservation(ctx context.Context, id int64) (*GetReservationRow, error)
+	GetStocktaking(ctx context.Context, id int64) (*GetStocktakingRow, error)
+	GetUserDepartmentID(ctx context.Context, id int64) (pgtype.Int8, error)
+	GetWriteOff(ctx context.Context, id int64) (*GetWriteOffRow, error)
+	IsEmployeeInDepartment(ctx context.Context, arg *IsEmployeeInDepartmentParams) (bool, error)
+	IsEquipmentInDepartment(ctx context.Context, arg *IsEquipmentInDepartmentParams) (bool, error)
+	IsWriteOffInDepartment(ctx context.Context, arg *IsWriteOffInDepartmentParams) (bool, error)
+	ListAuditLog(ctx context.Context, arg *ListAuditLogParams) ([]*ListAuditLogRow, error)
 	ListCategory(ctx context.Context, arg *ListCategoryParams) ([]*ListCategoryRow, error)
 	ListCompany(ctx context.Context, arg *ListCompanyParams) ([]*ListCompanyRow, error)
+	ListCompanyByTitles(ctx context.Context, titles []string) ([]*ListCompanyByTitlesRow, error)
 	ListContract(ctx context.Context, arg *ListContractParams) ([]*ListContractRow, error)
 	ListDepartment(ctx context.Context, arg *ListDepartmentParams) ([]*ListDepartmentRow, error)
 	ListEmployee(ctx context.Context, arg *ListEmployeeParams) ([]*ListEmployeeRow, error)
 	ListEquipment(ctx context.Context, arg *ListEquipmentParams) ([]*ListEquipmentRow, error)
-	ListEquipmentFromLocation(ctx context.Context, toDepartmentID int64) ([]*ListEquipmentFromLocationRow, error)
+	ListEquipmentFromLocation(ctx context.Context, arg *ListEquipmentFromLocationParams) ([]*ListEquipmentFromLocationRow, error)
+	ListExistingSerialNumbers(ctx context.Context, serialNumbers []string) ([]string, error)
+	ListLocationHistory(ctx context.Context, arg *ListLocationHistoryParams) ([]*ListLocationHistoryRow, error)
+	ListLocationsOfEquipments(ctx context.Context, ids []int64) ([]*Location, error)
 	ListProfile(ctx context.Context, arg *ListProfileParams) ([]*ListProfileRow, error)
+	ListProfileByTitles(ctx context.Context, titles []string) ([]*ListProfileByTitlesRow, error)
+	ListProfileSerialRules(ctx context.Context, ids []int64) ([]*ListProfileSerialRulesRow, error)
+	ListRepairOrders(ctx context.Context, arg *ListRepairOrdersParams) ([]*ListRepairOrdersRow, error)
+	ListRepairOrdersByEquipment(ctx context.Context, equipmentID int64) ([]*ListRepairOrdersByEquipmentRow, error)
+	ListReservations(ctx context.Context, arg *ListReservationsParams) ([]*ListReservationsRow, error)
+	ListSerialNumberDuplicates(ctx context.Context, departmentID int64) ([]*ListSerialNumberDuplicatesRow, error)
+	ListStockLevels(ctx context.Context, lowOnly bool) ([]*ListStockLevelsRow, error)
+	ListStocktakingDiscrepancies(ctx context.Context, stocktakingID int64) ([]*ListStocktakingDiscrepanciesRow, error)
 	ListUser(ctx context.Context) ([]*ListUserRow, error)
-	MoveToLocation(ctx context.Context, arg *MoveToLocationParams) (pgconn.CommandTag, error)
+	ListUserEmailsByRoles(ctx context.Context, roles []int32) ([]*ListUserEmailsByRolesRow, error)
+	ListWriteOffs(ctx context.Context, arg *ListWriteOffsParams) ([]*ListWriteOffsRow, error)
+	LockEquipment(ctx context.Context, id int64) (*LockEquipmentRow, error)
+	LockPendingOutboxEvents(ctx context.Context, batchSize int32) ([]*OutboxEvent, error)
+	LockRepairOrder(ctx context.Context, id int64) (*LockRepairOrderRow, error)
+	LockStocktaking(ctx context.Context, id int64) (*Stocktaking, error)
+	LockWriteOff(ctx context.Context, id int64) (*LockWriteOffRow, error)
+	MarkStockMinimumsLow(ctx context.Context, ids []int64) ([]int64, error)
+	MoveToLocation(ctx context.Context, arg *MoveToLocationParams) (int64, error)
 	ReadCategory(ctx context.Context, id int64) (*Category, error)
 	ReadCompany(ctx context.Context, id int64) (*Company, error)
 	ReadContract(ctx context.Context, id int64) (*Contract, error)
@@ -48,6 +107,13 @@ type Querier interface {
 	ReadEquipment(ctx context.Context, id int64) (*ReadEquipmentRow, error)
 	ReadProfile(ctx context.Context, id int64) (*ReadProfileRow, error)
 	ReadUser(ctx context.Context, id int64) (*ReadUserRow, error)
+	ReopenReservation(ctx context.Context, locationID int64) error
+	ReparentLocationReversals(ctx context.Context, arg *ReparentLocationReversalsParams) error
+	ReparentLocations(ctx context.Context, arg *ReparentLocationsParams) (pgconn.CommandTag, error)
+	ReparentRepairOrders(ctx context.Context, arg *ReparentRepairOrdersParams) error
+	ReparentReservations(ctx context.Context, arg *ReparentReservationsParams) error
+	ReparentStocktakingItems(ctx context.Context, arg *ReparentStocktakingItemsParams) error
+	ReparentStocktakingScans(ctx context.Context, arg *ReparentStocktakingScansParams) error
 	RestoreCategory(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	RestoreCompany(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	RestoreContract(ctx context.Context, id int64) (pgconn.CommandTag, error)
@@ -55,10 +121,16 @@ type Querier interface {
 	RestoreEmployee(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	RestoreEquipment(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	RestoreProfile(ctx context.Context, id int64) (pgconn.CommandTag, error)
+	ReturnRepairOrder(ctx context.Context, arg *ReturnRepairOrderParams) (pgconn.CommandTag, error)
 	SetDepartmentEmployee(ctx context.Context, arg *SetDepartmentEmployeeParams) (pgconn.CommandTag, error)
 	SetEnabledUser(ctx context.Context, arg *SetEnabledUserParams) (pgconn.CommandTag, error)
+	SetEquipmentWrittenOff(ctx context.Context, arg *SetEquipmentWrittenOffParams) (pgconn.CommandTag, error)
 	SetLastLoginAtUser(ctx context.Context, id int64) (pgconn.CommandTag, error)
+	SetOutboxEventsFailed(ctx context.Context, arg *SetOutboxEventsFailedParams) error
+	SetOutboxEventsPublished(ctx context.Context, ids []int64) error
 	SetPasswordHashUser(ctx context.Context, arg *SetPasswordHashUserParams) (pgconn.CommandTag, error)
+	SetStockMinimum(ctx context.Context, arg *SetStockMinimumParams) (int64, error)
+	SetStocktakingApplied(ctx context.Context, id int64) (pgconn.CommandTag, error)
 	UpdateCategory(ctx context.Context, arg *UpdateCategoryParams) (pgconn.CommandTag, error)
 	UpdateCompany(ctx context.Context, arg *UpdateCompanyParams) (pgconn.CommandTag, error)
 	UpdateContract(ctx context.Context, arg *UpdateContractParams) (pgconn.CommandTag, error)
@@ -66,6 +138,7 @@ type Querier interface {
 	UpdateEmployee(ctx context.Context, arg *UpdateEmployeeParams) (pgconn.CommandTag, error)
 	UpdateEquipment(ctx context.Context, arg *UpdateEquipmentParams) (pgconn.CommandTag, error)
 	UpdateProfile(ctx context.Context, arg *UpdateProfileParams) (pgconn.CommandTag, error)
+	UpdateRepairOrder(ctx context.Context, arg *UpdateRepairOrderParams) (pgconn.CommandTag, error)
 	UpdateUser(ctx context.Context, arg *UpdateUserParams) (pgconn.CommandTag, error)
 }
 
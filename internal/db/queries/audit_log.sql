@@ -0,0 +1,27 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_log (user_id, entity, entity_id, action, diff, request_id, client_ip)
+VALUES (@user_id, @entity, @entity_id, @action, @diff, @request_id, @client_ip);
+
+-- name: ListAuditLog :many
+SELECT a.id,
+       a.user_id,
+       u.username,
+       a.entity,
+       a.entity_id,
+       a.action,
+       a.diff,
+       a.request_id,
+       a.client_ip,
+       a.created_at,
+       COUNT(*) OVER () AS total
+FROM audit_log a
+         LEFT JOIN users u ON u.id = a.user_id
+WHERE (@user_id::bigint = 0 OR a.user_id = @user_id)
+  AND (@entity::text = '' OR a.entity = @entity)
+  AND (@entity_id::bigint = 0 OR a.entity_id = @entity_id)
+  AND (@action::text = '' OR a.action = @action)
+  AND (@request_id::text = '' OR a.request_id = @request_id)
+  AND (sqlc.narg(date_from)::timestamptz IS NULL OR a.created_at >= sqlc.narg(date_from))
+  AND (sqlc.narg(date_to)::timestamptz IS NULL OR a.created_at < sqlc.narg(date_to))
+ORDER BY a.id DESC
+LIMIT @pagination_limit OFFSET @pagination_offset;
@@ -36,4 +36,10 @@ ORDER BY CASE WHEN @sort_column::text = 'id' AND @sort_order::text = 'asc' THEN
          CASE WHEN @sort_column = 'id' AND @sort_order = 'desc' THEN id::text END DESC,
          CASE WHEN @sort_column = 'title' AND @sort_order = 'asc' THEN title END,
          CASE WHEN @sort_column = 'title' AND @sort_order = 'desc' THEN title END DESC
-LIMIT @pagination_limit OFFSET @pagination_offset;
\ No newline at end of file
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: ListCompanyByTitles :many
+SELECT id, title
+FROM companies
+WHERE title = ANY (@titles::text[])
+  AND deleted_at IS NULL;
\ No newline at end of file
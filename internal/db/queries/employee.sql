@@ -71,4 +71,4 @@ LIMIT @pagination_limit OFFSET @pagination_offset;
 -- name: SetDepartmentEmployee :execresult
 UPDATE employees
 SET department_id = @department_id
-WHERE id = @id;
+WHERE id = @id;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: CreateEquipmentMerge :one
+INSERT INTO equipment_merges (equipment_id, duplicate_id, merged_by, merged_at, locations)
+VALUES (@equipment_id, @duplicate_id, @merged_by, now(), @locations)
+RETURNING *;
\ No newline at end of file
@@ -7,6 +7,7 @@ RETURNING *;
 SELECT e.id,
        e.serial_number,
        e.deleted_at,
+       e.written_off_at,
        co.id    as company_id,
        co.title as company_title,
        p.id     as profile_id,
@@ -46,17 +47,29 @@ WHERE id = @id
 SELECT e.id,
        e.serial_number,
        e.deleted_at,
+       co.id            as company_id,
+       co.title         as company_title,
        p.id             as profile_id,
        p.title          as profile_title,
        c.id             as category_id,
        c.title          as category_title,
        COUNT(*) OVER () AS total
 FROM equipments e
+         INNER JOIN companies co ON co.id = e.company_id
          INNER JOIN profiles p ON p.id = e.profile_id
          INNER JOIN categories c ON c.id = p.category_id
 WHERE (@with_deleted::bool = true OR e.deleted_at IS NULL)
   AND (@search::text = '' OR (e.serial_number || ' ' || p.title || ' ' || c.title) ILIKE '%' || @search || '%')
   AND (array_length(@ids::bigint[], 1) IS NULL OR e.id = ANY (@ids))
+  AND (@department_id::bigint = 0 OR EXISTS (SELECT 1
+                                             FROM (SELECT to_department_id, to_employee_id
+                                                   FROM locations
+                                                   WHERE equipment_id = e.id
+                                                   ORDER BY move_at DESC, id DESC
+                                                   LIMIT 1) l
+                                                      LEFT JOIN employees em ON em.id = l.to_employee_id
+                                             WHERE l.to_department_id = @department_id
+                                                OR em.department_id = @department_id))
 ORDER BY CASE WHEN @sort_column::text = 'id' AND @sort_order::text = 'asc' THEN e.id::text END,
          CASE WHEN @sort_column = 'id' AND @sort_order = 'desc' THEN e.id::text END DESC,
          CASE WHEN @sort_column = 'serial_number' AND @sort_order = 'asc' THEN e.serial_number END,
@@ -65,4 +78,69 @@ ORDER BY CASE WHEN @sort_column::text = 'id' AND @sort_order::text = 'asc' THEN
          CASE WHEN @sort_column = 'profile_title' AND @sort_order = 'desc' THEN p.title END DESC,
          CASE WHEN @sort_column = 'category_title' AND @sort_order = 'asc' THEN c.title END,
          CASE WHEN @sort_column = 'category_title' AND @sort_order = 'desc' THEN c.title END DESC
-LIMIT @pagination_limit OFFSET @pagination_offset;
\ No newline at end of file
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: LockEquipment :one
+SELECT id, deleted_at, written_off_at
+FROM equipments
+WHERE id = @id
+    FOR UPDATE;
+
+-- name: ListExistingSerialNumbers :many
+SELECT serial_number
+FROM equipments
+WHERE serial_number = ANY (@serial_numbers::text[]);
+
+-- name: GetEquipmentDimensions :one
+SELECT e.profile_id,
+       p.category_id,
+       e.company_id
+FROM equipments e
+         INNER JOIN profiles p ON p.id = e.profile_id
+WHERE e.id = @id;
+
+-- name: GetEquipmentIDBySerialNumber :one
+SELECT id
+FROM equipments
+WHERE canonical_serial_number = @canonical_serial_number
+ORDER BY serial_number = @serial_number DESC, deleted_at IS NOT NULL, id
+LIMIT 1;
+
+-- name: ListSerialNumberDuplicates :many
+WITH normalized AS (SELECT id,
+                           company_id,
+                           profile_id,
+                           serial_number,
+                           canonical_serial_number AS normalized_serial_number
+                    FROM equipments e
+                    WHERE deleted_at IS NULL
+                      AND (@department_id::bigint = 0 OR EXISTS (SELECT 1
+                                                                 FROM (SELECT to_department_id, to_employee_id
+                                                                       FROM locations
+                                                                       WHERE equipment_id = e.id
+                                                                       ORDER BY move_at DESC, id DESC
+                                                                       LIMIT 1) l
+                                                                          LEFT JOIN employees em ON em.id = l.to_employee_id
+                                                                 WHERE l.to_department_id = @department_id
+                                                                    OR em.department_id = @department_id)))
+SELECT n.normalized_serial_number::text AS normalized_serial_number,
+       n.id,
+       n.serial_number,
+       c.id                             AS company_id,
+       c.title                          AS company_title,
+       p.id                             AS profile_id,
+       p.title                          AS profile_title
+FROM normalized n
+         INNER JOIN companies c ON c.id = n.company_id
+         INNER JOIN profiles p ON p.id = n.profile_id
+WHERE n.normalized_serial_number IN (SELECT normalized_serial_number
+                                     FROM normalized
+                                     GROUP BY normalized_serial_number
+                                     HAVING COUNT(*) > 1)
+ORDER BY n.normalized_serial_number, n.id;
+
+-- name: SetEquipmentWrittenOff :execresult
+UPDATE equipments
+SET written_off_at = @written_off_at
+WHERE id = @id
+  AND written_off_at IS NULL;
\ No newline at end of file
@@ -8,59 +8,178 @@ VALUES (@equipment_id,
         @move_at,
         @move_code);
 
--- name: MoveToLocation :execresult
+-- name: MoveToLocation :one
 INSERT INTO locations (equipment_id,
                        user_id,
                        move_at,
                        move_code,
+                       move_type,
+                       price,
                        from_department_id,
                        from_employee_id,
                        from_contract_id,
                        to_department_id,
                        to_employee_id,
-                       to_contract_id)
+                       to_contract_id,
+                       comment)
 VALUES (@equipment_id,
         @user_id,
         @move_at,
         @move_code,
+        @move_type,
+        @price,
         @from_department_id,
         @from_employee_id,
         @from_contract_id,
         @to_department_id,
         @to_employee_id,
-        @to_contract_id);
+        @to_contract_id,
+        @comment)
+RETURNING id;
+
+-- name: GetLastLocation :one
+SELECT *
+FROM locations
+WHERE equipment_id = @equipment_id
+ORDER BY move_at DESC, id DESC
+LIMIT 1;
 
 -- name: ListEquipmentFromLocation :many
-select e.id,
+SELECT e.id,
        e.serial_number,
        e.company_title,
        e.profile_title,
        e.category_title,
-       e.total
-from (SELECT DISTINCT ON (l.equipment_id) eq.id,
+       r.id             AS reservation_id,
+       r.expires_at     AS reservation_expires_at,
+       COUNT(*) OVER () AS total
+FROM (SELECT DISTINCT ON (l.equipment_id) eq.id,
                                           eq.serial_number,
-                                          co.title         AS company_title,
-                                          p.title          AS profile_title,
-                                          ca.title         AS category_title,
+                                          co.title AS company_title,
+                                          p.title  AS profile_title,
+                                          ca.title AS category_title,
                                           l.to_department_id,
                                           l.to_employee_id,
                                           l.to_contract_id,
-                                          COUNT(*) OVER () AS total
+                                          l.move_code
       FROM locations l
                LEFT JOIN equipments eq ON eq.id = l.equipment_id
                LEFT JOIN companies co ON co.id = eq.company_id
                LEFT JOIN profiles p ON p.id = eq.profile_id
                LEFT JOIN categories ca ON ca.id = p.category_id
       WHERE eq.deleted_at IS NULL
+        AND eq.written_off_at IS NULL
       ORDER BY l.equipment_id, l.move_at DESC, l.id DESC) e
-WHERE (
-    @to_department_id::bigint = 0
+         LEFT JOIN reservations r ON r.equipment_id = e.id
+    AND r.status = 'active'
+    AND r.expires_at > now()
+WHERE e.move_code <> 'SentToRepair'
+  AND (
+    (@param::text = 'employee' AND e.to_employee_id = @param_id::bigint)
+        OR (@param = 'contract' AND e.to_contract_id = @param_id)
+        OR (@param NOT IN ('employee', 'contract') AND @param_id = 0
         AND e.to_department_id IS NULL
         AND e.to_employee_id IS NULL
-        AND e.to_contract_id IS NULL
-    )
-   OR (
-    @to_department_id::bigint > 0
-        AND e.to_department_id = @to_department_id
+        AND e.to_contract_id IS NULL)
+        OR (@param NOT IN ('employee', 'contract') AND @param_id > 0
+        AND e.to_department_id = @param_id)
     )
-ORDER BY e.profile_title, e.serial_number;
\ No newline at end of file
+  AND (@search::text = '' OR (e.serial_number || ' ' || e.profile_title || ' ' || e.category_title) ILIKE '%' || @search || '%')
+ORDER BY CASE WHEN @sort_column::text = 'id' AND @sort_order::text = 'asc' THEN e.id::text END,
+         CASE WHEN @sort_column = 'id' AND @sort_order = 'desc' THEN e.id::text END DESC,
+         CASE WHEN @sort_column = 'serial_number' AND @sort_order = 'asc' THEN e.serial_number END,
+         CASE WHEN @sort_column = 'serial_number' AND @sort_order = 'desc' THEN e.serial_number END DESC,
+         CASE WHEN @sort_column = 'company_title' AND @sort_order = 'asc' THEN e.company_title END,
+         CASE WHEN @sort_column = 'company_title' AND @sort_order = 'desc' THEN e.company_title END DESC,
+         CASE WHEN @sort_column = 'profile_title' AND @sort_order = 'asc' THEN e.profile_title END,
+         CASE WHEN @sort_column = 'profile_title' AND @sort_order = 'desc' THEN e.profile_title END DESC,
+         CASE WHEN @sort_column = 'category_title' AND @sort_order = 'asc' THEN e.category_title END,
+         CASE WHEN @sort_column = 'category_title' AND @sort_order = 'desc' THEN e.category_title END DESC
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: GetLocation :one
+SELECT *
+FROM locations
+WHERE id = @id;
+
+-- name: DeleteLocation :execresult
+DELETE
+FROM locations
+WHERE id = @id;
+
+-- name: ListLocationHistory :many
+SELECT l.id,
+       l.move_at,
+       l.move_code,
+       l.move_type,
+       l.price,
+       l.comment,
+       u.id             AS user_id,
+       u.username       AS user_username,
+       l.from_department_id,
+       fd.title         AS from_department_title,
+       l.from_employee_id,
+       fe.last_name     AS from_employee_last_name,
+       fe.first_name    AS from_employee_first_name,
+       fe.middle_name   AS from_employee_middle_name,
+       l.from_contract_id,
+       fc.number        AS from_contract_number,
+       l.to_department_id,
+       td.title         AS to_department_title,
+       l.to_employee_id,
+       te.last_name     AS to_employee_last_name,
+       te.first_name    AS to_employee_first_name,
+       te.middle_name   AS to_employee_middle_name,
+       l.to_contract_id,
+       tc.number        AS to_contract_number,
+       r.id             AS replace_id,
+       r.move_in_id     AS replace_move_in_id,
+       r.move_out_id    AS replace_move_out_id,
+       COUNT(*) OVER () AS total
+FROM locations l
+         INNER JOIN users u ON u.id = l.user_id
+         LEFT JOIN departments fd ON fd.id = l.from_department_id
+         LEFT JOIN employees fe ON fe.id = l.from_employee_id
+         LEFT JOIN contracts fc ON fc.id = l.from_contract_id
+         LEFT JOIN departments td ON td.id = l.to_department_id
+         LEFT JOIN employees te ON te.id = l.to_employee_id
+         LEFT JOIN contracts tc ON tc.id = l.to_contract_id
+         LEFT JOIN replaces r ON r.move_in_id = l.id OR r.move_out_id = l.id
+WHERE l.equipment_id = @equipment_id
+ORDER BY l.move_at, l.id
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: GetCurrentLocation :one
+SELECT l.id,
+       l.move_at,
+       l.move_code,
+       u.id           AS user_id,
+       u.username     AS user_username,
+       l.to_department_id,
+       td.title       AS to_department_title,
+       l.to_employee_id,
+       te.last_name   AS to_employee_last_name,
+       te.first_name  AS to_employee_first_name,
+       te.middle_name AS to_employee_middle_name,
+       l.to_contract_id,
+       tc.number      AS to_contract_number,
+       tc.address     AS to_contract_address
+FROM locations l
+         INNER JOIN users u ON u.id = l.user_id
+         LEFT JOIN departments td ON td.id = l.to_department_id
+         LEFT JOIN employees te ON te.id = l.to_employee_id
+         LEFT JOIN contracts tc ON tc.id = l.to_contract_id
+WHERE l.equipment_id = @equipment_id
+ORDER BY l.move_at DESC, l.id DESC
+LIMIT 1;
+
+-- name: ListLocationsOfEquipments :many
+SELECT *
+FROM locations
+WHERE equipment_id = ANY (@ids::bigint[])
+ORDER BY move_at, id;
+
+-- name: ReparentLocations :execresult
+UPDATE locations
+SET equipment_id = @equipment_id
+WHERE equipment_id = @duplicate_id;
\ No newline at end of file
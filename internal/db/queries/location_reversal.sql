@@ -0,0 +1,19 @@
+-- name: CreateLocationReversal :one
+INSERT INTO location_reversals (location_id,
+                                replace_id,
+                                equipment_id,
+                                reverted_by,
+                                reverted_at,
+                                location)
+VALUES (@location_id,
+        @replace_id,
+        @equipment_id,
+        @reverted_by,
+        now(),
+        (SELECT to_jsonb(l) FROM locations l WHERE l.id = @location_id))
+RETURNING *;
+
+-- name: ReparentLocationReversals :exec
+UPDATE location_reversals
+SET equipment_id = @equipment_id
+WHERE equipment_id = @duplicate_id;
\ No newline at end of file
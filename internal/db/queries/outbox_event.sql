@@ -0,0 +1,22 @@
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox_events (event_type, entity_id, payload)
+VALUES (@event_type, @entity_id, @payload);
+
+-- name: LockPendingOutboxEvents :many
+SELECT *
+FROM outbox_events
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT @batch_size FOR UPDATE SKIP LOCKED;
+
+-- name: SetOutboxEventsFailed :exec
+UPDATE outbox_events
+SET attempts        = attempts + 1,
+    last_error      = @last_error,
+    next_attempt_at = now() + least(power(2, attempts), 300) * interval '1 second'
+WHERE id = ANY (@ids::bigint[]);
+
+-- name: SetOutboxEventsPublished :exec
+UPDATE outbox_events
+SET published_at = now()
+WHERE id = ANY (@ids::bigint[]);
@@ -1,11 +1,12 @@
 -- name: CreateProfile :one
-INSERT INTO profiles (title, category_id)
-VALUES (@title, @category_id)
+INSERT INTO profiles (title, category_id, serial_rule)
+VALUES (@title, @category_id, @serial_rule)
 RETURNING *;
 
 -- name: ReadProfile :one
 SELECT p.id,
        p.title,
+       p.serial_rule,
        p.deleted_at,
        c.id    as category_id,
        c.title as category_title
@@ -16,9 +17,10 @@ WHERE p.id = @id;
 -- name: UpdateProfile :execresult
 UPDATE profiles
 SET title       = @title,
-    category_id = @category_id
+    category_id = @category_id,
+    serial_rule = @serial_rule
 WHERE id = @id
-  AND (title != @title OR category_id != @category_id);
+  AND (title != @title OR category_id != @category_id OR serial_rule != @serial_rule);
 
 -- name: DeleteProfile :execresult
 UPDATE profiles
@@ -35,6 +37,7 @@ WHERE id = @id
 -- name: ListProfile :many
 SELECT p.id,
        p.title,
+       p.serial_rule,
        p.deleted_at,
        c.id             as category_id,
        c.title          as category_title,
@@ -50,4 +53,15 @@ ORDER BY CASE WHEN @sort_column::text = 'id' AND @sort_order::text = 'asc' THEN
          CASE WHEN @sort_column = 'title' AND @sort_order = 'desc' THEN p.title END DESC,
          CASE WHEN @sort_column = 'category_title' AND @sort_order = 'asc' THEN c.title END,
          CASE WHEN @sort_column = 'category_title' AND @sort_order = 'desc' THEN c.title END DESC
-LIMIT @pagination_limit OFFSET @pagination_offset;
\ No newline at end of file
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: ListProfileByTitles :many
+SELECT id, title
+FROM profiles
+WHERE title = ANY (@titles::text[])
+  AND deleted_at IS NULL;
+
+-- name: ListProfileSerialRules :many
+SELECT id, serial_rule
+FROM profiles
+WHERE id = ANY (@ids::bigint[]);
\ No newline at end of file
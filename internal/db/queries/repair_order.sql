@@ -0,0 +1,121 @@
+-- name: CreateRepairOrder :one
+INSERT INTO repair_orders (equipment_id, status, vendor, notes, sent_by, sent_at, send_location_id, updated_at)
+VALUES (@equipment_id, 'sent', @vendor, @notes, @sent_by, @sent_at, @send_location_id, now())
+RETURNING id;
+
+-- name: GetRepairOrder :one
+SELECT r.id,
+       r.equipment_id,
+       e.serial_number,
+       r.status,
+       r.vendor,
+       r.cost,
+       r.notes,
+       r.sent_by,
+       su.username           AS sent_by_username,
+       r.sent_at,
+       r.send_location_id,
+       sl.from_department_id AS origin_department_id,
+       sl.from_employee_id   AS origin_employee_id,
+       sl.from_contract_id   AS origin_contract_id,
+       r.returned_by,
+       ru.username           AS returned_by_username,
+       r.returned_at,
+       r.return_location_id,
+       r.updated_at
+FROM repair_orders r
+         INNER JOIN equipments e ON e.id = r.equipment_id
+         INNER JOIN users su ON su.id = r.sent_by
+         INNER JOIN locations sl ON sl.id = r.send_location_id
+         LEFT JOIN users ru ON ru.id = r.returned_by
+WHERE r.id = @id;
+
+-- name: ListRepairOrders :many
+SELECT r.id,
+       r.equipment_id,
+       e.serial_number,
+       r.status,
+       r.vendor,
+       r.cost,
+       r.notes,
+       r.sent_by,
+       su.username           AS sent_by_username,
+       r.sent_at,
+       r.send_location_id,
+       sl.from_department_id AS origin_department_id,
+       sl.from_employee_id   AS origin_employee_id,
+       sl.from_contract_id   AS origin_contract_id,
+       r.returned_by,
+       ru.username           AS returned_by_username,
+       r.returned_at,
+       r.return_location_id,
+       r.updated_at,
+       COUNT(*) OVER ()      AS total
+FROM repair_orders r
+         INNER JOIN equipments e ON e.id = r.equipment_id
+         INNER JOIN users su ON su.id = r.sent_by
+         INNER JOIN locations sl ON sl.id = r.send_location_id
+         LEFT JOIN users ru ON ru.id = r.returned_by
+         LEFT JOIN employees oe ON oe.id = sl.from_employee_id
+WHERE (@status::text = '' OR r.status = @status)
+  AND (@department_id::bigint = 0 OR sl.from_department_id = @department_id OR oe.department_id = @department_id)
+ORDER BY r.id DESC
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: ListRepairOrdersByEquipment :many
+SELECT r.id,
+       r.equipment_id,
+       e.serial_number,
+       r.status,
+       r.vendor,
+       r.cost,
+       r.notes,
+       r.sent_by,
+       su.username           AS sent_by_username,
+       r.sent_at,
+       r.send_location_id,
+       sl.from_department_id AS origin_department_id,
+       sl.from_employee_id   AS origin_employee_id,
+       sl.from_contract_id   AS origin_contract_id,
+       r.returned_by,
+       ru.username           AS returned_by_username,
+       r.returned_at,
+       r.return_location_id,
+       r.updated_at
+FROM repair_orders r
+         INNER JOIN equipments e ON e.id = r.equipment_id
+         INNER JOIN users su ON su.id = r.sent_by
+         INNER JOIN locations sl ON sl.id = r.send_location_id
+         LEFT JOIN users ru ON ru.id = r.returned_by
+WHERE r.equipment_id = @equipment_id
+ORDER BY r.sent_at DESC, r.id DESC;
+
+-- name: LockRepairOrder :one
+SELECT id, equipment_id, status
+FROM repair_orders
+WHERE id = @id
+    FOR UPDATE;
+
+-- name: ReparentRepairOrders :exec
+UPDATE repair_orders
+SET equipment_id = @equipment_id
+WHERE equipment_id = @duplicate_id;
+
+-- name: ReturnRepairOrder :execresult
+UPDATE repair_orders
+SET status             = 'returned',
+    returned_by        = @returned_by::bigint,
+    returned_at        = @returned_at,
+    return_location_id = @return_location_id::bigint,
+    updated_at         = now()
+WHERE id = @id;
+
+-- name: UpdateRepairOrder :execresult
+UPDATE repair_orders
+SET status     = @status,
+    vendor     = @vendor,
+    cost       = @cost,
+    notes      = @notes,
+    updated_at = now()
+WHERE id = @id
+  AND status = @current_status;
\ No newline at end of file
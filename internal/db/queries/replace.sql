@@ -0,0 +1,18 @@
+-- name: CreateReplace :one
+INSERT INTO replaces (move_in_id, move_out_id)
+VALUES (@move_in_id, @move_out_id)
+RETURNING *;
+
+-- name: GetReplaceByLocation :one
+SELECT *
+FROM replaces
+WHERE move_in_id = @location_id
+   OR move_out_id = @location_id;
+
+-- name: CountReplacesBetween :one
+SELECT COUNT(*)
+FROM replaces r
+         INNER JOIN locations li ON li.id = r.move_in_id
+         INNER JOIN locations lo ON lo.id = r.move_out_id
+WHERE (li.equipment_id = @equipment_id AND lo.equipment_id = @duplicate_id)
+   OR (li.equipment_id = @duplicate_id AND lo.equipment_id = @equipment_id);
\ No newline at end of file
@@ -0,0 +1,70 @@
+-- name: DepartmentBalanceReport :many
+WITH opening AS (SELECT DISTINCT ON (equipment_id) equipment_id,
+                                                   to_department_id
+                 FROM locations
+                 WHERE move_at < @date_from::timestamptz
+                 ORDER BY equipment_id, move_at DESC, id DESC),
+     closing AS (SELECT DISTINCT ON (equipment_id) equipment_id,
+                                                   to_department_id
+                 FROM locations
+                 WHERE move_at < @date_to::timestamptz
+                 ORDER BY equipment_id, move_at DESC, id DESC),
+     facts AS (SELECT equipment_id,
+                      'opening' AS kind
+               FROM opening
+               WHERE to_department_id = @department_id::bigint
+               UNION ALL
+               SELECT equipment_id,
+                      'closing'
+               FROM closing
+               WHERE to_department_id = @department_id
+               UNION ALL
+               SELECT equipment_id,
+                      CASE
+                          WHEN move_code = 'ReturnedFromRepair' THEN 'from_repair'
+                          WHEN from_contract_id IS NOT NULL THEN 'from_contract'
+                          WHEN from_department_id IS NOT NULL THEN 'from_department'
+                          WHEN from_employee_id IS NOT NULL THEN 'from_employee'
+                          ELSE 'from_storage'
+                          END
+               FROM locations
+               WHERE move_at >= @date_from
+                 AND move_at < @date_to
+                 AND to_department_id = @department_id
+                 AND from_department_id IS DISTINCT FROM @department_id
+               UNION ALL
+               SELECT equipment_id,
+                      CASE
+                          WHEN move_code = 'WrittenOff' THEN 'written_off'
+                          WHEN move_code = 'SentToRepair' THEN 'to_repair'
+                          WHEN to_contract_id IS NOT NULL THEN 'to_contract'
+                          WHEN to_department_id IS NOT NULL THEN 'to_department'
+                          WHEN to_employee_id IS NOT NULL THEN 'to_employee'
+                          ELSE 'to_storage'
+                          END
+               FROM locations
+               WHERE move_at >= @date_from
+                 AND move_at < @date_to
+                 AND from_department_id = @department_id
+                 AND to_department_id IS DISTINCT FROM @department_id)
+SELECT c.id                                               AS category_id,
+       c.title                                            AS category_title,
+       COUNT(*) FILTER (WHERE f.kind = 'opening')         AS opening,
+       COUNT(*) FILTER (WHERE f.kind = 'from_storage')    AS from_storage,
+       COUNT(*) FILTER (WHERE f.kind = 'from_contract')   AS from_contract,
+       COUNT(*) FILTER (WHERE f.kind = 'from_department') AS from_department,
+       COUNT(*) FILTER (WHERE f.kind = 'from_employee')   AS from_employee,
+       COUNT(*) FILTER (WHERE f.kind = 'from_repair')     AS from_repair,
+       COUNT(*) FILTER (WHERE f.kind = 'to_storage')      AS to_storage,
+       COUNT(*) FILTER (WHERE f.kind = 'to_contract')     AS to_contract,
+       COUNT(*) FILTER (WHERE f.kind = 'to_department')   AS to_department,
+       COUNT(*) FILTER (WHERE f.kind = 'to_employee')     AS to_employee,
+       COUNT(*) FILTER (WHERE f.kind = 'to_repair')       AS to_repair,
+       COUNT(*) FILTER (WHERE f.kind = 'written_off')     AS written_off,
+       COUNT(*) FILTER (WHERE f.kind = 'closing')         AS closing
+FROM facts f
+         INNER JOIN equipments e ON e.id = f.equipment_id
+         INNER JOIN profiles p ON p.id = e.profile_id
+         INNER JOIN categories c ON c.id = p.category_id
+GROUP BY c.id, c.title
+ORDER BY c.title;
\ No newline at end of file
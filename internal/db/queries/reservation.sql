@@ -0,0 +1,149 @@
+-- name: CancelReservation :execresult
+UPDATE reservations
+SET status    = 'cancelled',
+    closed_by = @closed_by::bigint,
+    closed_at = now()
+WHERE id = @id
+  AND status = 'active'
+  AND expires_at > now();
+
+-- name: ConsumeReservation :exec
+UPDATE reservations
+SET status      = 'consumed',
+    closed_by   = @closed_by::bigint,
+    closed_at   = now(),
+    location_id = @location_id::bigint
+WHERE id = @id;
+
+-- name: CountReservedBetween :one
+SELECT COUNT(DISTINCT equipment_id)
+FROM reservations
+WHERE (equipment_id = @equipment_id OR equipment_id = @duplicate_id)
+  AND status = 'active';
+
+-- name: CreateReservation :one
+INSERT INTO reservations (equipment_id,
+                          to_department_id,
+                          to_employee_id,
+                          to_contract_id,
+                          expires_at,
+                          comment,
+                          status,
+                          reserved_by,
+                          reserved_at)
+VALUES (@equipment_id,
+        @to_department_id,
+        @to_employee_id,
+        @to_contract_id,
+        @expires_at,
+        @comment,
+        'active',
+        @reserved_by,
+        now())
+RETURNING id;
+
+-- name: ExpireReservations :exec
+UPDATE reservations
+SET status    = 'expired',
+    closed_at = expires_at
+WHERE equipment_id = @equipment_id
+  AND status = 'active'
+  AND expires_at <= now();
+
+-- name: GetActiveReservation :one
+SELECT *
+FROM reservations
+WHERE equipment_id = @equipment_id
+  AND status = 'active'
+  AND expires_at > now()
+    FOR UPDATE;
+
+-- name: GetReservation :one
+SELECT r.id,
+       r.equipment_id,
+       e.serial_number,
+       r.to_department_id,
+       td.title       AS to_department_title,
+       r.to_employee_id,
+       te.last_name   AS to_employee_last_name,
+       te.first_name  AS to_employee_first_name,
+       te.middle_name AS to_employee_middle_name,
+       r.to_contract_id,
+       tc.number      AS to_contract_number,
+       r.expires_at,
+       r.comment,
+       CASE
+           WHEN r.status = 'active' AND r.expires_at <= now() THEN 'expired'
+           ELSE r.status
+           END        AS status,
+       r.reserved_by,
+       ru.username    AS reserved_by_username,
+       r.reserved_at,
+       r.closed_by,
+       cu.username    AS closed_by_username,
+       r.closed_at,
+       r.location_id
+FROM reservations r
+         INNER JOIN equipments e ON e.id = r.equipment_id
+         INNER JOIN users ru ON ru.id = r.reserved_by
+         LEFT JOIN users cu ON cu.id = r.closed_by
+         LEFT JOIN departments td ON td.id = r.to_department_id
+         LEFT JOIN employees te ON te.id = r.to_employee_id
+         LEFT JOIN contracts tc ON tc.id = r.to_contract_id
+WHERE r.id = @id;
+
+-- name: ListReservations :many
+SELECT r.id,
+       r.equipment_id,
+       e.serial_number,
+       r.to_department_id,
+       td.title       AS to_department_title,
+       r.to_employee_id,
+       te.last_name   AS to_employee_last_name,
+       te.first_name  AS to_employee_first_name,
+       te.middle_name AS to_employee_middle_name,
+       r.to_contract_id,
+       tc.number      AS to_contract_number,
+       r.expires_at,
+       r.comment,
+       CASE
+           WHEN r.status = 'active' AND r.expires_at <= now() THEN 'expired'
+           ELSE r.status
+           END        AS status,
+       r.reserved_by,
+       ru.username    AS reserved_by_username,
+       r.reserved_at,
+       r.closed_by,
+       cu.username    AS closed_by_username,
+       r.closed_at,
+       r.location_id,
+       COUNT(*) OVER () AS total
+FROM reservations r
+         INNER JOIN equipments e ON e.id = r.equipment_id
+         INNER JOIN users ru ON ru.id = r.reserved_by
+         LEFT JOIN users cu ON cu.id = r.closed_by
+         LEFT JOIN departments td ON td.id = r.to_department_id
+         LEFT JOIN employees te ON te.id = r.to_employee_id
+         LEFT JOIN contracts tc ON tc.id = r.to_contract_id
+WHERE (@status::text = ''
+    OR CASE
+           WHEN r.status = 'active' AND r.expires_at <= now() THEN 'expired'
+           ELSE r.status
+           END = @status)
+  AND (@department_id::bigint = 0 OR r.to_department_id = @department_id OR te.department_id = @department_id)
+ORDER BY r.id DESC
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: ReopenReservation :exec
+UPDATE reservations
+SET status      = 'active',
+    closed_by   = NULL,
+    closed_at   = NULL,
+    location_id = NULL
+WHERE location_id = @location_id::bigint
+  AND status = 'consumed';
+
+-- name: ReparentReservations :exec
+UPDATE reservations
+SET equipment_id = @equipment_id
+WHERE equipment_id = @duplicate_id;
\ No newline at end of file
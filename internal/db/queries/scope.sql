@@ -0,0 +1,37 @@
+-- name: GetUserDepartmentID :one
+SELECT e.department_id
+FROM users u
+         LEFT JOIN employees e ON e.id = u.employee_id
+WHERE u.id = @id;
+
+-- name: IsEmployeeInDepartment :one
+SELECT EXISTS (SELECT 1
+               FROM employees
+               WHERE id = @id
+                 AND department_id = @department_id::bigint);
+
+-- name: IsEquipmentInDepartment :one
+SELECT EXISTS (SELECT 1
+               FROM (SELECT to_department_id, to_employee_id
+                     FROM locations
+                     WHERE equipment_id = @equipment_id
+                     ORDER BY move_at DESC, id DESC
+                     LIMIT 1) l
+                        LEFT JOIN employees e ON e.id = l.to_employee_id
+               WHERE l.to_department_id = @department_id::bigint
+                  OR e.department_id = @department_id);
+
+-- name: IsWriteOffInDepartment :one
+SELECT EXISTS (SELECT 1
+               FROM write_offs w
+                        INNER JOIN LATERAL (SELECT CASE WHEN id = w.location_id THEN from_department_id ELSE to_department_id END AS department_id,
+                                                   CASE WHEN id = w.location_id THEN from_employee_id ELSE to_employee_id END   AS employee_id
+                                            FROM locations
+                                            WHERE id = w.location_id
+                                               OR (w.location_id IS NULL AND equipment_id = w.equipment_id)
+                                            ORDER BY move_at DESC, id DESC
+                                            LIMIT 1) l ON true
+                        LEFT JOIN employees e ON e.id = l.employee_id
+               WHERE w.id = @id
+                 AND (l.department_id = @department_id::bigint
+                   OR e.department_id = @department_id));
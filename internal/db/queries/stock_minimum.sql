@@ -0,0 +1,65 @@
+-- name: ClearStockMinimumsLow :exec
+UPDATE stock_minimums
+SET low_since = NULL
+WHERE low_since IS NOT NULL
+  AND NOT (id = ANY (@ids::bigint[]));
+
+-- name: DeleteStockMinimum :execresult
+DELETE
+FROM stock_minimums
+WHERE id = @id;
+
+-- name: ListStockLevels :many
+WITH holdings AS (SELECT e.profile_id,
+                         l.to_department_id AS department_id,
+                         count(*)           AS quantity
+                  FROM equipments e
+                           INNER JOIN LATERAL (SELECT to_department_id,
+                                                      to_employee_id,
+                                                      to_contract_id,
+                                                      move_code
+                                               FROM locations
+                                               WHERE equipment_id = e.id
+                                               ORDER BY move_at DESC, id DESC
+                                               LIMIT 1) l ON true
+                  WHERE e.deleted_at IS NULL
+                    AND e.written_off_at IS NULL
+                    AND l.move_code <> 'SentToRepair'
+                    AND l.to_contract_id IS NULL
+                    AND (l.to_department_id IS NOT NULL
+                      OR (l.to_employee_id IS NULL
+                          AND NOT EXISTS (SELECT 1
+                                          FROM reservations r
+                                          WHERE r.equipment_id = e.id
+                                            AND r.status = 'active'
+                                            AND r.expires_at > now())))
+                  GROUP BY e.profile_id, l.to_department_id)
+SELECT s.id,
+       s.profile_id,
+       p.title                         AS profile_title,
+       s.department_id,
+       d.title                         AS department_title,
+       s.min_quantity,
+       coalesce(h.quantity, 0)::bigint AS quantity,
+       s.low_since
+FROM stock_minimums s
+         INNER JOIN profiles p ON p.id = s.profile_id
+         LEFT JOIN departments d ON d.id = s.department_id
+         LEFT JOIN holdings h ON h.profile_id = s.profile_id
+    AND h.department_id IS NOT DISTINCT FROM s.department_id
+WHERE (NOT @low_only::boolean OR coalesce(h.quantity, 0) < s.min_quantity)
+ORDER BY p.title, d.title NULLS FIRST;
+
+-- name: MarkStockMinimumsLow :many
+UPDATE stock_minimums
+SET low_since = now()
+WHERE id = ANY (@ids::bigint[])
+  AND low_since IS NULL
+RETURNING id;
+
+-- name: SetStockMinimum :one
+INSERT INTO stock_minimums (profile_id, department_id, min_quantity)
+VALUES (@profile_id, @department_id, @min_quantity)
+ON CONFLICT (profile_id, department_id) DO UPDATE
+    SET min_quantity = excluded.min_quantity
+RETURNING id;
@@ -0,0 +1,108 @@
+-- name: CreateStocktaking :one
+INSERT INTO stocktakings (department_id,
+                          employee_id,
+                          opened_by,
+                          opened_at)
+VALUES (@department_id,
+        @employee_id,
+        @opened_by,
+        now())
+RETURNING *;
+
+-- name: CreateStocktakingItems :execresult
+INSERT INTO stocktaking_items (stocktaking_id, equipment_id)
+SELECT @stocktaking_id, unnest(@equipment_ids::bigint[]);
+
+-- name: GetStocktaking :one
+SELECT st.*,
+       (SELECT COUNT(*) FROM stocktaking_items i WHERE i.stocktaking_id = st.id) AS expected,
+       (SELECT COUNT(*) FROM stocktaking_scans s WHERE s.stocktaking_id = st.id) AS scanned
+FROM stocktakings st
+WHERE st.id = @id;
+
+-- name: LockStocktaking :one
+SELECT *
+FROM stocktakings
+WHERE id = @id
+    FOR UPDATE;
+
+-- name: CreateStocktakingScans :execresult
+INSERT INTO stocktaking_scans (stocktaking_id,
+                               serial_number,
+                               equipment_id,
+                               scanned_by,
+                               scanned_at)
+SELECT @stocktaking_id, s.serial_number, e.id, @scanned_by, now()
+FROM unnest(@serial_numbers::text[], @canonical_serial_numbers::text[]) AS s(serial_number, canonical_serial_number)
+         LEFT JOIN LATERAL (SELECT eq.id
+                            FROM equipments eq
+                            WHERE eq.canonical_serial_number = s.canonical_serial_number
+                            ORDER BY eq.serial_number = s.serial_number DESC, eq.deleted_at IS NOT NULL, eq.id
+                            LIMIT 1) e ON true
+ON CONFLICT DO NOTHING;
+
+-- name: CloseStocktaking :execresult
+UPDATE stocktakings
+SET closed_by = @closed_by::bigint,
+    closed_at = now()
+WHERE id = @id
+  AND closed_at IS NULL;
+
+-- name: SetStocktakingApplied :execresult
+UPDATE stocktakings
+SET applied_at = now()
+WHERE id = @id
+  AND closed_at IS NOT NULL
+  AND applied_at IS NULL;
+
+-- name: ListStocktakingDiscrepancies :many
+SELECT d.status,
+       d.equipment_id,
+       d.serial_number,
+       l.to_department_id,
+       l.to_employee_id,
+       l.to_contract_id
+FROM (SELECT 'missing'::text AS status,
+             i.equipment_id,
+             e.serial_number
+      FROM stocktaking_items i
+               JOIN equipments e ON e.id = i.equipment_id
+      WHERE i.stocktaking_id = @stocktaking_id
+        AND NOT EXISTS (SELECT 1
+                        FROM stocktaking_scans s
+                        WHERE s.stocktaking_id = i.stocktaking_id
+                          AND s.equipment_id = i.equipment_id)
+      UNION ALL
+      SELECT CASE WHEN s.equipment_id IS NULL THEN 'unexpected' ELSE 'found_elsewhere' END,
+             s.equipment_id,
+             s.serial_number
+      FROM stocktaking_scans s
+      WHERE s.stocktaking_id = @stocktaking_id
+        AND NOT EXISTS (SELECT 1
+                        FROM stocktaking_items i
+                        WHERE i.stocktaking_id = s.stocktaking_id
+                          AND i.equipment_id = s.equipment_id)) d
+         LEFT JOIN LATERAL (SELECT to_department_id,
+                                   to_employee_id,
+                                   to_contract_id
+                            FROM locations
+                            WHERE equipment_id = d.equipment_id
+                            ORDER BY move_at DESC, id DESC
+                            LIMIT 1) l ON TRUE
+ORDER BY d.status, d.serial_number;
+
+-- name: ReparentStocktakingItems :exec
+WITH moved AS (
+    DELETE FROM stocktaking_items
+        WHERE equipment_id = @duplicate_id
+        RETURNING stocktaking_id)
+INSERT
+INTO stocktaking_items (stocktaking_id, equipment_id)
+SELECT stocktaking_id, @equipment_id
+FROM moved
+ON CONFLICT DO NOTHING;
+
+-- name: ReparentStocktakingScans :exec
+UPDATE stocktaking_scans
+SET equipment_id = @equipment_id
+WHERE equipment_id = @duplicate_id;
\ No newline at end of file
@@ -78,4 +78,12 @@ WHERE id = @id;
 -- name: GetByUsernameUser :one
 SELECT id, username, password_hash, email, role, enabled, last_login_at
 FROM users
-WHERE username = @id;
\ No newline at end of file
+WHERE username = @id;
+
+-- name: ListUserEmailsByRoles :many
+SELECT username, email
+FROM users
+WHERE enabled
+  AND role = ANY (@roles::int[])
+  AND email <> ''
+ORDER BY id;
@@ -0,0 +1,71 @@
+-- name: CreateWriteOff :one
+INSERT INTO write_offs (equipment_id, reason, comment, status, requested_by, requested_at)
+VALUES (@equipment_id, @reason, @comment, 'pending', @requested_by, now())
+RETURNING id;
+
+-- name: DecideWriteOff :execresult
+UPDATE write_offs
+SET status      = @status,
+    decided_by  = @decided_by::bigint,
+    decided_at  = now(),
+    location_id = @location_id
+WHERE id = @id
+  AND status = 'pending';
+
+-- name: GetWriteOff :one
+SELECT w.id,
+       w.equipment_id,
+       e.serial_number,
+       w.reason,
+       w.comment,
+       w.status,
+       w.requested_by,
+       ru.username AS requested_by_username,
+       w.requested_at,
+       w.decided_by,
+       du.username AS decided_by_username,
+       w.decided_at,
+       w.location_id
+FROM write_offs w
+         INNER JOIN equipments e ON e.id = w.equipment_id
+         INNER JOIN users ru ON ru.id = w.requested_by
+         LEFT JOIN users du ON du.id = w.decided_by
+WHERE w.id = @id;
+
+-- name: ListWriteOffs :many
+SELECT w.id,
+       w.equipment_id,
+       e.serial_number,
+       w.reason,
+       w.comment,
+       w.status,
+       w.requested_by,
+       ru.username AS requested_by_username,
+       w.requested_at,
+       w.decided_by,
+       du.username AS decided_by_username,
+       w.decided_at,
+       w.location_id,
+       COUNT(*) OVER () AS total
+FROM write_offs w
+         INNER JOIN equipments e ON e.id = w.equipment_id
+         INNER JOIN users ru ON ru.id = w.requested_by
+         LEFT JOIN users du ON du.id = w.decided_by
+         LEFT JOIN LATERAL (SELECT CASE WHEN id = w.location_id THEN from_department_id ELSE to_department_id END AS department_id,
+                                   CASE WHEN id = w.location_id THEN from_employee_id ELSE to_employee_id END   AS employee_id
+                            FROM locations
+                            WHERE id = w.location_id
+                               OR (w.location_id IS NULL AND equipment_id = w.equipment_id)
+                            ORDER BY move_at DESC, id DESC
+                            LIMIT 1) hl ON true
+         LEFT JOIN employees he ON he.id = hl.employee_id
+WHERE (@status::text = '' OR w.status = @status)
+  AND (@department_id::bigint = 0 OR hl.department_id = @department_id OR he.department_id = @department_id)
+ORDER BY w.id DESC
+LIMIT @pagination_limit OFFSET @pagination_offset;
+
+-- name: LockWriteOff :one
+SELECT id, equipment_id, status
+FROM write_offs
+WHERE id = @id
+    FOR UPDATE;
\ No newline at end of file
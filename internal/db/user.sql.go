@@ -172,6 +172,40 @@ func (q *Queries) ListUser(ctx context.Context) ([]*ListUserRow, error) {
 	return items, nil
 }
 
+const listUserEmailsByRoles = `-- name: ListUserEmailsByRoles :many
+SELECT username, email
+FROM users
+WHERE enabled
+  AND role = ANY ($1::int[])
+  AND email <> ''
+ORDER BY id
+`
+
+type ListUserEmailsByRolesRow struct {
+	Username string `db:"username" json:"username"`
+	Email    string `db:"email" json:"email"`
+}
+
+func (q *Queries) ListUserEmailsByRoles(ctx context.Context, roles []int32) ([]*ListUserEmailsByRolesRow, error) {
+	rows, err := q.db.Query(ctx, listUserEmailsByRoles, roles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListUserEmailsByRolesRow
+	for rows.Next() {
+		var i ListUserEmailsByRolesRow
+		if err := rows.Scan(&i.Username, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readUser = `-- name: ReadUser :one
 SELECT u.id,
        u.username,
@@ -0,0 +1,13 @@
+package dto
+
+// AuditRequest filters the audit log, an empty field does not filter.
+// Dates are RFC 3339, DateTo is exclusive.
+type AuditRequest struct {
+	UserID    int64  `form:"user_id"`
+	Entity    string `form:"entity"`
+	EntityID  int64  `form:"entity_id"`
+	Action    string `form:"action"`
+	RequestID string `form:"request_id"`
+	DateFrom  string `form:"date_from"`
+	DateTo    string `form:"date_to"`
+}
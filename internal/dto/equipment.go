@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/oatsmoke/warehouse_backend/internal/model"
+
 type Equipment struct {
 	CompanyID    int64  `json:"company_id,omitempty" binding:"required"`
 	ProfileID    int64  `json:"profile_id,omitempty" binding:"required"`
@@ -11,6 +13,64 @@ type CreateEquipmentRequest struct {
 	CompanyID     int64    `json:"company_id,omitempty" binding:"required"`
 	ProfileID     int64    `json:"profile_id,omitempty" binding:"required"`
 	SerialNumbers []string `json:"serial_numbers,omitempty" binding:"required"`
-	Param         string   `json:"param,omitempty" binding:"required"`
 	ParamID       int64    `json:"param_id,omitempty"`
+	AllOrNothing  bool     `json:"all_or_nothing,omitempty"`
+}
+
+type ImportEquipmentRequest struct {
+	Date    string `form:"date" binding:"required"`
+	ParamID int64  `form:"param_id"`
+	DryRun  bool   `form:"dry_run"`
+}
+
+type ImportRowResult struct {
+	Row          int      `json:"row"`
+	SerialNumber string   `json:"serial_number,omitempty"`
+	Company      string   `json:"company,omitempty"`
+	Profile      string   `json:"profile,omitempty"`
+	ID           int64    `json:"id,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+type ImportEquipmentResponse struct {
+	DryRun  bool               `json:"dry_run"`
+	Total   int                `json:"total"`
+	Valid   int                `json:"valid"`
+	Created int                `json:"created"`
+	Rows    []*ImportRowResult `json:"rows"`
+}
+
+// Reasons why a serial number from a batch was not created.
+const (
+	ReasonEmptySerialNumber     = "empty_serial_number"
+	ReasonDuplicateSerialNumber = "duplicate_serial_number"
+	ReasonAlreadyExists         = "already_exists"
+	ReasonCreateFailed          = "create_failed"
+	ReasonMoveFailed            = "move_failed"
+	ReasonIllegalMove           = "illegal_move"
+)
+
+type CreatedEquipment struct {
+	ID           int64  `json:"id"`
+	SerialNumber string `json:"serial_number"`
+}
+
+type FailedEquipment struct {
+	ID           int64  `json:"id,omitempty"`
+	SerialNumber string `json:"serial_number"`
+	Reason       string `json:"reason"`
+}
+
+type CreateEquipmentResponse struct {
+	Created []*CreatedEquipment `json:"created"`
+	Failed  []*FailedEquipment  `json:"failed"`
+}
+
+type LookupEquipmentResponse struct {
+	Equipment *model.Equipment `json:"equipment"`
+	Location  *model.Location  `json:"location"`
+}
+
+type MergeEquipmentRequest struct {
+	DuplicateID int64 `json:"duplicate_id,omitempty" binding:"required"`
 }
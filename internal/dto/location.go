@@ -0,0 +1,29 @@
+package dto
+
+type MoveEquipmentRequest struct {
+	Date           string  `json:"date,omitempty" binding:"required"`
+	EquipmentIDs   []int64 `json:"equipment_ids,omitempty" binding:"required,min=1"`
+	ToDepartmentID int64   `json:"to_department_id,omitempty"`
+	ToEmployeeID   int64   `json:"to_employee_id,omitempty"`
+	ToContractID   int64   `json:"to_contract_id,omitempty"`
+	MoveType       string  `json:"move_type,omitempty"`
+	Price          string  `json:"price,omitempty"`
+	Comment        string  `json:"comment,omitempty"`
+}
+
+type ReplaceEquipmentRequest struct {
+	Date           string `json:"date,omitempty" binding:"required"`
+	ContractID     int64  `json:"contract_id,omitempty" binding:"required"`
+	OutEquipmentID int64  `json:"out_equipment_id,omitempty" binding:"required"`
+	InEquipmentID  int64  `json:"in_equipment_id,omitempty" binding:"required"`
+	MoveType       string `json:"move_type,omitempty"`
+	Price          string `json:"price,omitempty"`
+	Comment        string `json:"comment,omitempty"`
+}
+
+type DepartmentBalanceRequest struct {
+	DepartmentID int64  `form:"department_id" binding:"required"`
+	DateFrom     string `form:"date_from" binding:"required"`
+	DateTo       string `form:"date_to" binding:"required"`
+	Format       string `form:"format"`
+}
@@ -1,6 +1,7 @@
 package dto
 
 type Profile struct {
-	Title      string `json:"title,omitempty" binding:"required"`
-	CategoryID int64  `json:"category_id,omitempty" binding:"required"`
+	Title      string   `json:"title,omitempty" binding:"required"`
+	CategoryID int64    `json:"category_id,omitempty" binding:"required"`
+	SerialRule []string `json:"serial_rule,omitempty"`
 }
@@ -1,5 +1,11 @@
 package dto
 
+// Formats a list can be exported in, an empty format asks for json.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
 type QueryParams struct {
 	WithDeleted      bool
 	Search           string
@@ -10,4 +16,8 @@ type QueryParams struct {
 	PaginationOffset int32
 	Param            string
 	ParamID          int64
+	Format           string
+	// ScopeDepartmentID limits a list to the equipment held by the
+	// department, it is set by the services and never parsed.
+	ScopeDepartmentID int64
 }
@@ -0,0 +1,19 @@
+package dto
+
+type SendRepairRequest struct {
+	EquipmentID int64  `json:"equipment_id,omitempty" binding:"required"`
+	Vendor      string `json:"vendor,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+	Date        string `json:"date,omitempty" binding:"required"`
+}
+
+type UpdateRepairRequest struct {
+	Status string `json:"status,omitempty" binding:"required"`
+	Vendor string `json:"vendor,omitempty"`
+	Cost   string `json:"cost,omitempty"`
+	Notes  string `json:"notes,omitempty"`
+}
+
+type ReturnRepairRequest struct {
+	Date string `json:"date,omitempty" binding:"required"`
+}
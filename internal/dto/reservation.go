@@ -0,0 +1,10 @@
+package dto
+
+type ReservationRequest struct {
+	EquipmentID    int64  `json:"equipment_id,omitempty" binding:"required"`
+	ToDepartmentID int64  `json:"to_department_id,omitempty"`
+	ToEmployeeID   int64  `json:"to_employee_id,omitempty"`
+	ToContractID   int64  `json:"to_contract_id,omitempty"`
+	ExpiresAt      string `json:"expires_at,omitempty" binding:"required"`
+	Comment        string `json:"comment,omitempty"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type StockMinimumRequest struct {
+	ProfileID    int64 `json:"profile_id,omitempty" binding:"required"`
+	DepartmentID int64 `json:"department_id,omitempty"`
+	MinQuantity  int32 `json:"min_quantity,omitempty" binding:"required,min=1"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+type OpenStocktakingRequest struct {
+	DepartmentID int64 `json:"department_id,omitempty"`
+	EmployeeID   int64 `json:"employee_id,omitempty"`
+}
+
+type ScanStocktakingRequest struct {
+	SerialNumbers []string `json:"serial_numbers,omitempty" binding:"required,min=1"`
+}
+
+type ApplyStocktakingRequest struct {
+	Date    string `json:"date,omitempty" binding:"required"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Reasons why an item found elsewhere was not moved by applying a session,
+// next to ReasonIllegalMove.
+const (
+	ReasonEquipmentDeleted    = "equipment_deleted"
+	ReasonEquipmentWrittenOff = "equipment_written_off"
+	ReasonEquipmentInRepair   = "equipment_in_repair"
+	ReasonEquipmentReserved   = "equipment_reserved"
+	ReasonLocationChanged     = "location_changed"
+)
+
+type ApplyStocktakingResponse struct {
+	Locations []int64            `json:"locations"`
+	Skipped   []*FailedEquipment `json:"skipped"`
+}
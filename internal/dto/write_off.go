@@ -0,0 +1,11 @@
+package dto
+
+type WriteOffRequest struct {
+	EquipmentID int64  `json:"equipment_id,omitempty" binding:"required"`
+	Reason      string `json:"reason,omitempty" binding:"required"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+type ApproveWriteOffRequest struct {
+	Date string `json:"date,omitempty" binding:"required"`
+}
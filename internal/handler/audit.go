@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/generate"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/request_info"
+	"github.com/oatsmoke/warehouse_backend/internal/service"
+)
+
+// maxRequestIDLength is the size of audit_log.request_id.
+const maxRequestIDLength = 64
+
+type AuditHandler struct {
+	auditService service.Audit
+}
+
+func NewAuditHandler(auditService service.Audit) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// RequestInfo keeps the request id sent by the client, or generates one,
+// and the client ip on the context for the audit log.
+func (h *AuditHandler) RequestInfo(ctx *gin.Context) {
+	requestID := ctx.GetHeader(request_info.HeaderRequestID)
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		requestID = generate.UUID()
+	}
+
+	ctx.Set(request_info.RequestIDKey, requestID)
+	ctx.Set(request_info.ClientIPKey, ctx.ClientIP())
+	ctx.Header(request_info.HeaderRequestID, requestID)
+	ctx.Next()
+}
+
+func (h *AuditHandler) List(ctx *gin.Context) {
+	var req *dto.AuditRequest
+	if err := ctx.BindQuery(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.auditService.List(ctx, req, list_filter.ParseQueryParams(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrInvalidDateRange):
+			logger.ResponseErr(ctx, logger.ErrInvalidDateRange.Error(), err, http.StatusBadRequest)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
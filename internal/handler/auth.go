@@ -2,12 +2,15 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/jwt_auth"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/request_info"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
 	"github.com/oatsmoke/warehouse_backend/internal/service"
 )
@@ -89,53 +92,69 @@ func (h *AuthHandler) UserIdentity(ctx *gin.Context) {
 		setCookie(ctx, token.Access, token.Refresh)
 	}
 
-	ctx.Set("userId", token.UserID)
-	ctx.Set("userRole", token.UserRole)
+	ctx.Set(request_info.UserIDKey, token.UserID)
+	ctx.Set(request_info.UserRoleKey, token.UserRole)
 }
 
-func (h *AuthHandler) RootAccess(ctx *gin.Context) {
-	if ok, err := checkRole(ctx, role.RootRole); err != nil || !ok {
-		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
-		return
+// Require lets the request through when the role of the user has the
+// permission.
+func (h *AuthHandler) Require(permission role.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ok, err := checkPermission(ctx, permission); err != nil || !ok {
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, permissionErr(err, permission), http.StatusForbidden)
+			return
+		}
 	}
 }
 
-func (h *AuthHandler) AdminAccess(ctx *gin.Context) {
-	if ok, err := checkRole(ctx, role.AdminRole); err != nil || !ok {
-		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
-		return
+// RequireOrSelf is Require that also lets a user through to the route of
+// their own id.
+func (h *AuthHandler) RequireOrSelf(permission role.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if userId, err := getUserId(ctx); err == nil && ctx.Param("id") == strconv.FormatInt(userId, 10) {
+			return
+		}
+
+		if ok, err := checkPermission(ctx, permission); err != nil || !ok {
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, permissionErr(err, permission), http.StatusForbidden)
+			return
+		}
 	}
 }
 
-func (h *AuthHandler) GoverningAccess(ctx *gin.Context) {
-	if ok, err := checkRole(ctx, role.GoverningRole); err != nil || !ok {
-		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
-		return
+func getUserId(ctx *gin.Context) (int64, error) {
+	userId, ok := ctx.Get(request_info.UserIDKey)
+	if !ok {
+		return 0, logger.Error(logger.MsgFailedToGet, logger.ErrUserIdNotFound)
 	}
+
+	return userId.(int64), nil
 }
 
-func (h *AuthHandler) EmployeeAccess(ctx *gin.Context) {
-	if ok, err := checkRole(ctx, role.EmployeeRole); err != nil || !ok {
-		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
-		return
+func getUserRole(ctx *gin.Context) (role.Role, error) {
+	userRole, ok := ctx.Get(request_info.UserRoleKey)
+	if !ok {
+		return 0, logger.Error(logger.MsgFailedToGet, logger.ErrUserRoleNotFound)
 	}
+
+	return userRole.(role.Role), nil
 }
 
-func getUserId(ctx *gin.Context) (int64, error) {
-	userId, ok := ctx.Get("userId")
-	if !ok {
-		return 0, logger.Error(logger.MsgFailedToGet, logger.ErrUserIdNotFound)
+func checkPermission(ctx *gin.Context, permission role.Permission) (bool, error) {
+	userRole, err := getUserRole(ctx)
+	if err != nil {
+		return false, err
 	}
 
-	return userId.(int64), nil
+	return userRole.Can(permission), nil
 }
 
-func checkRole(ctx *gin.Context, access role.Role) (bool, error) {
-	if userRole, ok := ctx.Get("userRole"); !ok {
-		return false, logger.Error(logger.MsgFailedToGet, logger.ErrUserRoleNotFound)
-	} else {
-		return userRole.(role.Role).CanAccess(access), nil
+func permissionErr(err error, permission role.Permission) error {
+	if err != nil {
+		return err
 	}
+
+	return fmt.Errorf("%w: %s", logger.ErrPermissionDenied, permission)
 }
 
 func setCookie(ctx *gin.Context, access, refresh string) {
@@ -122,6 +122,9 @@ func (h *ContractHandler) Restore(ctx *gin.Context) {
 
 func (h *ContractHandler) List(ctx *gin.Context) {
 	req := list_filter.ParseQueryParams(ctx)
+	if !exportFormat(ctx, req.Format) {
+		return
+	}
 
 	res, err := h.contractService.List(ctx, req)
 	if err != nil {
@@ -129,5 +132,10 @@ func (h *ContractHandler) List(ctx *gin.Context) {
 		return
 	}
 
+	if req.Format != "" {
+		writeListExport(ctx, req, "contracts", contractColumns, res, h.contractService.List)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, res)
 }
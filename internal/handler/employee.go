@@ -127,6 +127,9 @@ func (h *EmployeeHandler) Restore(ctx *gin.Context) {
 
 func (h *EmployeeHandler) List(ctx *gin.Context) {
 	req := list_filter.ParseQueryParams(ctx)
+	if !exportFormat(ctx, req.Format) {
+		return
+	}
 
 	res, err := h.serviceEmployee.List(ctx, req)
 	if err != nil {
@@ -134,6 +137,11 @@ func (h *EmployeeHandler) List(ctx *gin.Context) {
 		return
 	}
 
+	if req.Format != "" {
+		writeListExport(ctx, req, "employees", employeeColumns, res, h.serviceEmployee.List)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, res)
 }
 
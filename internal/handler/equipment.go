@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/export"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
@@ -36,33 +37,29 @@ func (h *EquipmentHandler) Create(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.equipmentService.Create(ctx, userId, req); err != nil {
-		if errors.Is(err, logger.ErrAlreadyExists) {
+	res, err := h.equipmentService.Create(ctx, userId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrInvalidRows):
+			ctx.JSON(http.StatusUnprocessableEntity, res)
+		case errors.Is(err, logger.ErrInvalidDestination):
+			logger.ResponseErr(ctx, err.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrAlreadyExists):
 			logger.ResponseErr(ctx, logger.ErrAlreadyExists.Error(), err, http.StatusConflict)
-			return
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToInsert, err, http.StatusInternalServerError)
 		}
-		logger.ResponseErr(ctx, logger.MsgFailedToInsert, err, http.StatusInternalServerError)
-		return
-	}
-
-	//if err := h.LocationService.AddToStorage(ctx, request.Location.Date, id, userId, request.Location.Company.ID); err != nil {
-	//	logger.ResponseErr(ctx, err, http.StatusInternalServerError)
-	//	return
-	//}
-	//
-	//if request.RequestLocation != nil {
-	//	request.RequestLocation[0].EquipmentId = id
-	//	if request.RequestLocation[0].ToDepartment != 0 ||
-	//		request.RequestLocation[0].ToEmployee != 0 ||
-	//		request.RequestLocation[0].ToContract != 0 {
-	//		if err := h.LocationService.TransferTo(ctx, userId, request.RequestLocation); err != nil {
-	//			logger.ResponseErr(ctx, err, http.StatusInternalServerError)
-	//			return
-	//		}
-	//	}
-	//}
-
-	ctx.JSON(http.StatusCreated, "")
+		return
+	}
+
+	if len(res.Failed) != 0 {
+		ctx.JSON(http.StatusMultiStatus, res)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
 }
 
 func (h *EquipmentHandler) Read(ctx *gin.Context) {
@@ -74,13 +71,88 @@ func (h *EquipmentHandler) Read(ctx *gin.Context) {
 
 	res, err := h.equipmentService.Read(ctx, id)
 	if err != nil {
-		logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Lookup finds equipment by a scanned code: ?code=<serial number or label payload>.
+func (h *EquipmentHandler) Lookup(ctx *gin.Context) {
+	res, err := h.equipmentService.Lookup(ctx, ctx.Query("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrEmptySerialNumber):
+			logger.ResponseErr(ctx, logger.ErrEmptySerialNumber.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrEquipmentNotFound):
+			logger.ResponseErr(ctx, logger.ErrEquipmentNotFound.Error(), err, http.StatusNotFound)
+		case errors.Is(err, logger.ErrLabelMismatch):
+			logger.ResponseErr(ctx, logger.ErrLabelMismatch.Error(), err, http.StatusConflict)
+		default:
+			getErrResponse(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Duplicates reports equipment with colliding normalised serial numbers.
+func (h *EquipmentHandler) Duplicates(ctx *gin.Context) {
+	res, err := h.equipmentService.Duplicates(ctx)
+	if err != nil {
+		getErrResponse(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, res)
 }
 
+// Merge folds the duplicate from the body into the equipment of the path.
+func (h *EquipmentHandler) Merge(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	var req *dto.MergeEquipmentRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.equipmentService.Merge(ctx, userId, id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrMergeSameEquipment):
+			logger.ResponseErr(ctx, logger.ErrMergeSameEquipment.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrEquipmentNotFound):
+			logger.ResponseErr(ctx, logger.ErrEquipmentNotFound.Error(), err, http.StatusNotFound)
+		case errors.Is(err, logger.ErrEquipmentDeleted):
+			logger.ResponseErr(ctx, logger.ErrEquipmentDeleted.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrTimelineConflict):
+			logger.ResponseErr(ctx, logger.ErrTimelineConflict.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrEquipmentWrittenOff):
+			logger.ResponseErr(ctx, logger.ErrEquipmentWrittenOff.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrMergeReserved):
+			logger.ResponseErr(ctx, logger.ErrMergeReserved.Error(), err, http.StatusConflict)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToUpdate, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
 func (h *EquipmentHandler) Update(ctx *gin.Context) {
 	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -106,7 +178,14 @@ func (h *EquipmentHandler) Update(ctx *gin.Context) {
 	}
 
 	if err := h.equipmentService.Update(ctx, equipment); err != nil {
-		logger.ResponseErr(ctx, logger.MsgFailedToUpdate, err, http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, logger.ErrEmptySerialNumber):
+			logger.ResponseErr(ctx, logger.ErrEmptySerialNumber.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToUpdate, err, http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -121,6 +200,10 @@ func (h *EquipmentHandler) Delete(ctx *gin.Context) {
 	}
 
 	if err := h.equipmentService.Delete(ctx, id); err != nil {
+		if errors.Is(err, logger.ErrOutOfScope) {
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+			return
+		}
 		logger.ResponseErr(ctx, logger.MsgFailedToDelete, err, http.StatusInternalServerError)
 		return
 	}
@@ -136,6 +219,10 @@ func (h *EquipmentHandler) Restore(ctx *gin.Context) {
 	}
 
 	if err := h.equipmentService.Restore(ctx, id); err != nil {
+		if errors.Is(err, logger.ErrOutOfScope) {
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+			return
+		}
 		logger.ResponseErr(ctx, logger.MsgFailedToRestore, err, http.StatusInternalServerError)
 		return
 	}
@@ -145,12 +232,77 @@ func (h *EquipmentHandler) Restore(ctx *gin.Context) {
 
 func (h *EquipmentHandler) List(ctx *gin.Context) {
 	req := list_filter.ParseQueryParams(ctx)
+	if !exportFormat(ctx, req.Format) {
+		return
+	}
 
 	res, err := h.equipmentService.List(ctx, req)
 	if err != nil {
-		logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+		getErrResponse(ctx, err)
+		return
+	}
+
+	if req.Format != "" {
+		writeListExport(ctx, req, "equipments", equipmentColumns, res, h.equipmentService.List)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (h *EquipmentHandler) Import(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	var req *dto.ImportEquipmentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	rows, err := export.Read(file, export.FormatFromFilename(fh.Filename))
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.equipmentService.Import(ctx, userId, req, rows)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrInvalidRows):
+			ctx.JSON(http.StatusUnprocessableEntity, res)
+		case errors.Is(err, logger.ErrMissingColumn), errors.Is(err, logger.ErrInvalidDestination):
+			logger.ResponseErr(ctx, err.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrAlreadyExists):
+			logger.ResponseErr(ctx, logger.ErrAlreadyExists.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToInsert, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if req.DryRun {
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/export"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+var equipmentColumns = []export.Column[*model.Equipment]{
+	export.NewColumn("ID", func(e *model.Equipment) any { return e.ID }),
+	export.NewColumn("Серийный номер", func(e *model.Equipment) any { return e.SerialNumber }),
+	export.NewColumn("Компания", func(e *model.Equipment) any { return companyTitle(e.Company) }),
+	export.NewColumn("Профиль", func(e *model.Equipment) any { return profileTitle(e.Profile) }),
+	export.NewColumn("Категория", func(e *model.Equipment) any { return categoryTitle(e.Profile) }),
+}
+
+var employeeColumns = []export.Column[*model.Employee]{
+	export.NewColumn("ID", func(e *model.Employee) any { return e.ID }),
+	export.NewColumn("Фамилия", func(e *model.Employee) any { return e.LastName }),
+	export.NewColumn("Имя", func(e *model.Employee) any { return e.FirstName }),
+	export.NewColumn("Отчество", func(e *model.Employee) any { return e.MiddleName }),
+	export.NewColumn("Телефон", func(e *model.Employee) any { return e.Phone }),
+	export.NewColumn("Отдел", func(e *model.Employee) any { return departmentTitle(e.Department) }),
+}
+
+var contractColumns = []export.Column[*model.Contract]{
+	export.NewColumn("ID", func(c *model.Contract) any { return c.ID }),
+	export.NewColumn("Номер", func(c *model.Contract) any { return c.Number }),
+	export.NewColumn("Адрес", func(c *model.Contract) any { return c.Address }),
+}
+
+var departmentBalanceColumns = []export.Column[*model.DepartmentBalanceRow]{
+	export.NewColumn("Категория", func(r *model.DepartmentBalanceRow) any { return r.Category.Title }),
+	export.NewColumn("Остаток на начало", func(r *model.DepartmentBalanceRow) any { return r.Opening }),
+	export.NewColumn("Приход со склада", func(r *model.DepartmentBalanceRow) any { return r.FromStorage }),
+	export.NewColumn("Приход с договоров", func(r *model.DepartmentBalanceRow) any { return r.FromContract }),
+	export.NewColumn("Приход из отделов", func(r *model.DepartmentBalanceRow) any { return r.FromDepartment }),
+	export.NewColumn("Приход от сотрудников", func(r *model.DepartmentBalanceRow) any { return r.FromEmployee }),
+	export.NewColumn("Приход из ремонта", func(r *model.DepartmentBalanceRow) any { return r.FromRepair }),
+	export.NewColumn("Расход на склад", func(r *model.DepartmentBalanceRow) any { return r.ToStorage }),
+	export.NewColumn("Расход на договоры", func(r *model.DepartmentBalanceRow) any { return r.ToContract }),
+	export.NewColumn("Расход в отделы", func(r *model.DepartmentBalanceRow) any { return r.ToDepartment }),
+	export.NewColumn("Расход сотрудникам", func(r *model.DepartmentBalanceRow) any { return r.ToEmployee }),
+	export.NewColumn("Расход в ремонт", func(r *model.DepartmentBalanceRow) any { return r.ToRepair }),
+	export.NewColumn("Списано", func(r *model.DepartmentBalanceRow) any { return r.WrittenOff }),
+	export.NewColumn("Остаток на конец", func(r *model.DepartmentBalanceRow) any { return r.Closing }),
+}
+
+const totalTitle = "Итого"
+
+// exportFormat refuses an unknown export format before the list is read,
+// an empty format asks for json.
+func exportFormat(ctx *gin.Context, format string) bool {
+	if format != "" && !export.IsValid(format) {
+		logger.ResponseErr(ctx, logger.ErrUnsupportedFormat.Error(), logger.ErrUnsupportedFormat, http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+func writeExport[T any](ctx *gin.Context, format, name string, columns []export.Column[T], rows []T) {
+	if err := export.Write(ctx, format, name, columns, rows); err != nil {
+		exportErrResponse(ctx, err)
+	}
+}
+
+// writeListExport exports the whole filtered list: res is its first page,
+// the next pages are read with list as the file is written.
+func writeListExport[T any](
+	ctx *gin.Context,
+	req *dto.QueryParams,
+	name string,
+	columns []export.Column[T],
+	res *dto.ListResponse[[]T],
+	list func(context.Context, *dto.QueryParams) (*dto.ListResponse[[]T], error),
+) {
+	last := len(res.List) < int(req.PaginationLimit)
+	next := func() ([]T, error) {
+		if last {
+			return nil, nil
+		}
+
+		req.PaginationOffset += req.PaginationLimit
+		page, err := list(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		last = len(page.List) < int(req.PaginationLimit)
+		return page.List, nil
+	}
+
+	if err := export.Stream(ctx, req.Format, name, columns, res.List, next); err != nil {
+		exportErrResponse(ctx, err)
+	}
+}
+
+// exportErrResponse answers a failed export, a file already being sent can
+// only be cut off.
+func exportErrResponse(ctx *gin.Context, err error) {
+	switch {
+	case ctx.Writer.Written():
+		ctx.Abort()
+	case errors.Is(err, logger.ErrOutOfScope):
+		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+	default:
+		logger.ResponseErr(ctx, logger.MsgFailedToExport, err, http.StatusInternalServerError)
+	}
+}
+
+func companyTitle(c *model.Company) string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Title
+}
+
+func departmentTitle(d *model.Department) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.Title
+}
+
+func profileTitle(p *model.Profile) string {
+	if p == nil {
+		return ""
+	}
+
+	return p.Title
+}
+
+func categoryTitle(p *model.Profile) string {
+	if p == nil || p.Category == nil {
+		return ""
+	}
+
+	return p.Category.Title
+}
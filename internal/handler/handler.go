@@ -6,43 +6,57 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/env"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/request_info"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
 	"github.com/oatsmoke/warehouse_backend/internal/service"
 )
 
 type Handler struct {
-	Auth       *AuthHandler
-	User       *UserHandler
-	Category   *CategoryHandler
-	Company    *CompanyHandler
-	Contract   *ContractHandler
-	Department *DepartmentHandler
-	Employee   *EmployeeHandler
-	Equipment  *EquipmentHandler
-	Location   *LocationHandler
-	Profile    *ProfileHandler
+	Auth         *AuthHandler
+	User         *UserHandler
+	Category     *CategoryHandler
+	Company      *CompanyHandler
+	Contract     *ContractHandler
+	Department   *DepartmentHandler
+	Employee     *EmployeeHandler
+	Equipment    *EquipmentHandler
+	Location     *LocationHandler
+	Profile      *ProfileHandler
+	Stocktaking  *StocktakingHandler
+	WriteOff     *WriteOffHandler
+	RepairOrder  *RepairOrderHandler
+	Reservation  *ReservationHandler
+	StockMinimum *StockMinimumHandler
+	Audit        *AuditHandler
+	hub          *websocket.Hub
 }
 
-func New(service *service.Service) *Handler {
+func New(service *service.Service, hub *websocket.Hub) *Handler {
 	return &Handler{
-		Auth:       NewAuthHandler(service.Auth, service.User),
-		User:       NewUserHandler(service.User),
-		Category:   NewCategoryHandler(service.Category),
-		Company:    NewCompanyHandler(service.Company),
-		Contract:   NewContractHandler(service.Contract),
-		Department: NewDepartmentHandler(service.Department),
-		Employee:   NewEmployeeHandler(service.Employee),
-		Equipment:  NewEquipmentHandler(service.Equipment),
-		Location:   NewLocationHandler(service.Location),
-		Profile:    NewProfileHandler(service.Profile),
+		Auth:         NewAuthHandler(service.Auth, service.User),
+		User:         NewUserHandler(service.User),
+		Category:     NewCategoryHandler(service.Category),
+		Company:      NewCompanyHandler(service.Company),
+		Contract:     NewContractHandler(service.Contract),
+		Department:   NewDepartmentHandler(service.Department),
+		Employee:     NewEmployeeHandler(service.Employee),
+		Equipment:    NewEquipmentHandler(service.Equipment),
+		Location:     NewLocationHandler(service.Location),
+		Profile:      NewProfileHandler(service.Profile),
+		Stocktaking:  NewStocktakingHandler(service.Stocktaking),
+		WriteOff:     NewWriteOffHandler(service.WriteOff),
+		RepairOrder:  NewRepairOrderHandler(service.RepairOrder),
+		Reservation:  NewReservationHandler(service.Reservation),
+		StockMinimum: NewStockMinimumHandler(service.StockMinimum),
+		Audit:        NewAuditHandler(service.Audit),
+		hub:          hub,
 	}
 }
 
 func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
-	hub := websocket.NewHub()
-	go hub.Run()
 
 	if err := router.SetTrustedProxies(nil); err != nil {
 		return nil
@@ -51,10 +65,10 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{env.GetClientUrl()},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", request_info.HeaderRequestID},
+		ExposeHeaders:    []string{"Content-Length", request_info.HeaderRequestID},
 		AllowCredentials: true,
-	}))
+	}), h.Audit.RequestInfo)
 
 	auth := router.Group("/auth")
 	{
@@ -64,7 +78,12 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	api := router.Group("/api", h.Auth.UserIdentity)
 	{
 		api.GET("/ws", func(ctx *gin.Context) {
-			websocket.NewClient(ctx.Writer, ctx.Request, hub)
+			authorize, err := h.Location.LocationService.Topics(ctx)
+			if err != nil {
+				logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+				return
+			}
+			websocket.NewClient(ctx.Writer, ctx.Request, h.hub, authorize)
 		})
 		api.GET("/roles", func(ctx *gin.Context) {
 			ctx.JSON(http.StatusOK, role.AllRole())
@@ -73,25 +92,26 @@ func (h *Handler) InitRoutes() *gin.Engine {
 
 		user := api.Group("/users")
 		{
-			user.POST("", h.User.Create)
-			user.GET("/:id", h.User.Read)
-			user.PUT("/:id", h.User.Update)
-			user.DELETE("/:id", h.User.Delete)
-			user.GET("", h.User.List)
-			user.PUT("/:id/set_password", h.User.SetPassword)
-			user.PUT("/:id/reset_password", h.User.ResetPassword)
-			user.PUT("/:id/set_enabled", h.User.SetEnabled)
+			user.POST("", h.Auth.Require(role.UsersManage), h.User.Create)
+			user.GET("/:id", h.Auth.Require(role.UsersManage), h.User.Read)
+			user.PUT("/:id", h.Auth.Require(role.UsersManage), h.User.Update)
+			user.DELETE("/:id", h.Auth.Require(role.UsersManage), h.User.Delete)
+			user.GET("", h.Auth.Require(role.UsersManage), h.User.List)
+			user.PUT("/:id/set_password", h.Auth.RequireOrSelf(role.UsersManage), h.User.SetPassword)
+			user.PUT("/:id/reset_password", h.Auth.Require(role.UsersManage), h.User.ResetPassword)
+			user.PUT("/:id/set_enabled", h.Auth.Require(role.UsersManage), h.User.SetEnabled)
 		}
 
 		employee := api.Group("/employees")
 		{
-			employee.POST("", h.Employee.Create)
-			employee.GET("/:id", h.Employee.Read)
-			employee.PUT("/:id", h.Employee.Update)
-			employee.DELETE("/:id", h.Employee.Delete)
-			employee.PUT("/:id/restore", h.Employee.Restore)
-			employee.GET("", h.Employee.List)
-			employee.PUT("/:id/set_department", h.Employee.SetDepartment)
+			employee.POST("", h.Auth.Require(role.DirectoryManage), h.Employee.Create)
+			employee.GET("/:id", h.Auth.Require(role.DirectoryView), h.Employee.Read)
+			employee.PUT("/:id", h.Auth.Require(role.DirectoryManage), h.Employee.Update)
+			employee.DELETE("/:id", h.Auth.Require(role.DirectoryManage), h.Employee.Delete)
+			employee.PUT("/:id/restore", h.Auth.Require(role.DirectoryManage), h.Employee.Restore)
+			employee.GET("", h.Auth.Require(role.DirectoryView), h.Employee.List)
+			employee.PUT("/:id/set_department", h.Auth.Require(role.DirectoryManage), h.Employee.SetDepartment)
+			employee.GET("/:id/equipments", h.Auth.Require(role.EquipmentView), h.Location.ListByEmployee)
 			//employee.POST("/getAllShort", h.Employee.GetAllShort)
 			//employee.POST("/getAllButAuth", h.Employee.GetAllButAuth)
 			//employee.POST("/getAllButOne", h.Employee.GetAllButOne)
@@ -107,76 +127,136 @@ func (h *Handler) InitRoutes() *gin.Engine {
 
 		department := api.Group("/departments")
 		{
-			department.POST("", h.Department.Create)
-			department.GET("/:id", h.Department.Read)
-			department.PUT("/:id", h.Department.Update)
-			department.DELETE("/:id", h.Department.Delete)
-			department.PUT("/:id/restore", h.Department.Restore)
-			department.GET("", h.Department.List)
+			department.POST("", h.Auth.Require(role.DirectoryManage), h.Department.Create)
+			department.GET("/:id", h.Auth.Require(role.DirectoryView), h.Department.Read)
+			department.PUT("/:id", h.Auth.Require(role.DirectoryManage), h.Department.Update)
+			department.DELETE("/:id", h.Auth.Require(role.DirectoryManage), h.Department.Delete)
+			department.PUT("/:id/restore", h.Auth.Require(role.DirectoryManage), h.Department.Restore)
+			department.GET("", h.Auth.Require(role.DirectoryView), h.Department.List)
 			//department.POST("/getAllButOne", h.Department.GetAllButOne)
 		}
 
 		contract := api.Group("/contracts")
 		{
-			contract.POST("", h.Contract.Create)
-			contract.GET("/:id", h.Contract.Read)
-			contract.PUT("/:id", h.Contract.Update)
-			contract.DELETE("/:id", h.Contract.Delete)
-			contract.PUT("/:id/restore", h.Contract.Restore)
-			contract.GET("", h.Contract.List)
+			contract.POST("", h.Auth.Require(role.DirectoryManage), h.Contract.Create)
+			contract.GET("/:id", h.Auth.Require(role.DirectoryView), h.Contract.Read)
+			contract.PUT("/:id", h.Auth.Require(role.DirectoryManage), h.Contract.Update)
+			contract.DELETE("/:id", h.Auth.Require(role.DirectoryManage), h.Contract.Delete)
+			contract.PUT("/:id/restore", h.Auth.Require(role.DirectoryManage), h.Contract.Restore)
+			contract.GET("", h.Auth.Require(role.DirectoryView), h.Contract.List)
+			contract.GET("/:id/equipments", h.Auth.Require(role.EquipmentView), h.Location.ListByContract)
 		}
 
 		company := api.Group("/companies")
 		{
-			company.POST("", h.Auth.AdminAccess, h.Company.Create)
-			company.GET("/:id", h.Auth.AdminAccess, h.Company.Read)
-			company.PUT("/:id", h.Auth.AdminAccess, h.Company.Update)
-			company.DELETE("/:id", h.Auth.AdminAccess, h.Company.Delete)
-			company.PUT("/:id/restore", h.Auth.AdminAccess, h.Company.Restore)
-			company.GET("", h.Company.List)
+			company.POST("", h.Auth.Require(role.CompaniesManage), h.Company.Create)
+			company.GET("/:id", h.Auth.Require(role.CompaniesManage), h.Company.Read)
+			company.PUT("/:id", h.Auth.Require(role.CompaniesManage), h.Company.Update)
+			company.DELETE("/:id", h.Auth.Require(role.CompaniesManage), h.Company.Delete)
+			company.PUT("/:id/restore", h.Auth.Require(role.CompaniesManage), h.Company.Restore)
+			company.GET("", h.Auth.Require(role.DirectoryView), h.Company.List)
 		}
 
 		category := api.Group("/categories")
 		{
-			category.POST("", h.Category.Create)
-			category.GET("/:id", h.Category.Read)
-			category.PUT("/:id", h.Category.Update)
-			category.DELETE("/:id", h.Category.Delete)
-			category.PUT("/:id/restore", h.Category.Restore)
-			category.GET("", h.Category.List)
+			category.POST("", h.Auth.Require(role.DirectoryManage), h.Category.Create)
+			category.GET("/:id", h.Auth.Require(role.DirectoryView), h.Category.Read)
+			category.PUT("/:id", h.Auth.Require(role.DirectoryManage), h.Category.Update)
+			category.DELETE("/:id", h.Auth.Require(role.DirectoryManage), h.Category.Delete)
+			category.PUT("/:id/restore", h.Auth.Require(role.DirectoryManage), h.Category.Restore)
+			category.GET("", h.Auth.Require(role.DirectoryView), h.Category.List)
 		}
 
 		profile := api.Group("/profiles")
 		{
-			profile.POST("", h.Profile.Create)
-			profile.GET("/:id", h.Profile.Read)
-			profile.PUT("/:id", h.Profile.Update)
-			profile.DELETE("/:id", h.Profile.Delete)
-			profile.PUT("/:id/restore", h.Profile.Restore)
-			profile.GET("", h.Profile.List)
+			profile.POST("", h.Auth.Require(role.DirectoryManage), h.Profile.Create)
+			profile.GET("/:id", h.Auth.Require(role.DirectoryView), h.Profile.Read)
+			profile.PUT("/:id", h.Auth.Require(role.DirectoryManage), h.Profile.Update)
+			profile.DELETE("/:id", h.Auth.Require(role.DirectoryManage), h.Profile.Delete)
+			profile.PUT("/:id/restore", h.Auth.Require(role.DirectoryManage), h.Profile.Restore)
+			profile.GET("", h.Auth.Require(role.DirectoryView), h.Profile.List)
 		}
 
 		equipment := api.Group("/equipments")
 		{
-			equipment.POST("", h.Equipment.Create)
-			equipment.GET("/:id", h.Equipment.Read)
-			equipment.PUT("/:id", h.Equipment.Update)
-			equipment.DELETE("/:id", h.Equipment.Delete)
-			equipment.PUT("/:id/restore", h.Equipment.Restore)
-			equipment.GET("", h.Equipment.List)
+			equipment.POST("", h.Auth.Require(role.EquipmentManage), h.Equipment.Create)
+			equipment.POST("/import", h.Auth.Require(role.EquipmentManage), h.Equipment.Import)
+			equipment.GET("/labels", h.Auth.Require(role.EquipmentView), h.Equipment.Labels)
+			equipment.GET("/lookup", h.Auth.Require(role.EquipmentView), h.Equipment.Lookup)
+			equipment.GET("/duplicates", h.Auth.Require(role.EquipmentView), h.Equipment.Duplicates)
+			equipment.GET("/:id/label", h.Auth.Require(role.EquipmentView), h.Equipment.Label)
+			equipment.GET("/:id", h.Auth.Require(role.EquipmentView), h.Equipment.Read)
+			equipment.PUT("/:id", h.Auth.Require(role.EquipmentManage), h.Equipment.Update)
+			equipment.DELETE("/:id", h.Auth.Require(role.EquipmentManage), h.Equipment.Delete)
+			equipment.PUT("/:id/restore", h.Auth.Require(role.EquipmentManage), h.Equipment.Restore)
+			equipment.POST("/:id/merge", h.Auth.Require(role.EquipmentMerge), h.Equipment.Merge)
+			equipment.POST("/:id/undo_move", h.Auth.Require(role.EquipmentMove), h.Location.Undo)
+			equipment.GET("/:id/history", h.Auth.Require(role.EquipmentView), h.Location.History)
+			equipment.GET("", h.Auth.Require(role.EquipmentView), h.Equipment.List)
 		}
 
 		location := api.Group("/locations")
 		{
-			location.GET("", h.Location.List)
-			//location.POST("/transferTo", h.Location.TransferTo)
-			//location.POST("/delete", h.Location.Delete)
+			location.GET("", h.Auth.Require(role.EquipmentView), h.Location.List)
+			location.POST("/moves", h.Auth.Require(role.EquipmentMove), h.Location.Move)
+			location.POST("/replace", h.Auth.Require(role.EquipmentMove), h.Location.Replace)
 			//location.POST("/getById", h.Location.GetById)
 			//location.POST("/getByIds", h.Location.GetByIds)
-			//location.POST("/getHistory", h.Location.GetHistory)
-			//location.POST("/getByLocation", h.Location.GetByLocation)
-			//location.POST("/reportByCategory", h.Location.ReportByCategory)
 		}
+
+		stocktaking := api.Group("/stocktakings")
+		{
+			stocktaking.POST("", h.Auth.Require(role.StocktakingManage), h.Stocktaking.Open)
+			stocktaking.GET("/:id", h.Auth.Require(role.EquipmentView), h.Stocktaking.Read)
+			stocktaking.POST("/:id/scans", h.Auth.Require(role.StocktakingManage), h.Stocktaking.Scan)
+			stocktaking.POST("/:id/close", h.Auth.Require(role.StocktakingManage), h.Stocktaking.Close)
+			stocktaking.POST("/:id/apply", h.Auth.Require(role.StocktakingManage), h.Stocktaking.Apply)
+		}
+
+		writeOff := api.Group("/write-offs")
+		{
+			writeOff.POST("", h.Auth.Require(role.EquipmentWriteOff), h.WriteOff.Create)
+			writeOff.GET("/:id", h.Auth.Require(role.EquipmentView), h.WriteOff.Read)
+			writeOff.GET("", h.Auth.Require(role.EquipmentView), h.WriteOff.List)
+			writeOff.POST("/:id/approve", h.Auth.Require(role.WriteOffApprove), h.WriteOff.Approve)
+			writeOff.POST("/:id/reject", h.Auth.Require(role.WriteOffApprove), h.WriteOff.Reject)
+		}
+
+		repair := api.Group("/repairs")
+		{
+			repair.POST("", h.Auth.Require(role.EquipmentMove), h.RepairOrder.Send)
+			repair.GET("/:id", h.Auth.Require(role.EquipmentView), h.RepairOrder.Read)
+			repair.PUT("/:id", h.Auth.Require(role.EquipmentMove), h.RepairOrder.Update)
+			repair.POST("/:id/return", h.Auth.Require(role.EquipmentMove), h.RepairOrder.Return)
+			repair.GET("", h.Auth.Require(role.EquipmentView), h.RepairOrder.List)
+		}
+
+		reservation := api.Group("/reservations")
+		{
+			reservation.POST("", h.Auth.Require(role.EquipmentMove), h.Reservation.Create)
+			reservation.GET("/:id", h.Auth.Require(role.EquipmentView), h.Reservation.Read)
+			reservation.POST("/:id/cancel", h.Auth.Require(role.EquipmentMove), h.Reservation.Cancel)
+			reservation.GET("", h.Auth.Require(role.EquipmentView), h.Reservation.List)
+		}
+
+		stockMinimum := api.Group("/stock-minimums")
+		{
+			stockMinimum.PUT("", h.Auth.Require(role.StockManage), h.StockMinimum.Set)
+			stockMinimum.DELETE("/:id", h.Auth.Require(role.StockManage), h.StockMinimum.Delete)
+			stockMinimum.GET("", h.Auth.Require(role.EquipmentView), h.StockMinimum.List)
+		}
+
+		alert := api.Group("/alerts")
+		{
+			alert.GET("/low-stock", h.Auth.Require(role.ReportsView), h.StockMinimum.LowStock)
+		}
+
+		report := api.Group("/reports")
+		{
+			report.GET("/department-balance", h.Auth.Require(role.ReportsView), h.Location.DepartmentBalance)
+		}
+
+		api.GET("/audit", h.Auth.Require(role.AuditView), h.Audit.List)
 	}
 
 	return router
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/label"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func (h *EquipmentHandler) Label(ctx *gin.Context) {
+	format, code, ok := labelParams(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.equipmentService.Read(ctx, id)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	writeLabels(ctx, format, code, "label_"+strconv.FormatInt(id, 10), []*model.Equipment{res})
+}
+
+// Labels renders labels for the filtered list, e.g. ?ids=1&ids=2 for the
+// equipment just created by a batch. The whole catalog is never printed,
+// the list needs ids or a filter and at most label.MaxLabels items.
+func (h *EquipmentHandler) Labels(ctx *gin.Context) {
+	format, code, ok := labelParams(ctx)
+	if !ok {
+		return
+	}
+
+	req := list_filter.ParseQueryParams(ctx)
+	if len(req.IDs) == 0 && req.Search == "" {
+		logger.ResponseErr(ctx, logger.ErrNoLabelFilter.Error(), logger.ErrNoLabelFilter, http.StatusBadRequest)
+		return
+	}
+	req.PaginationLimit = label.MaxLabels
+	req.PaginationOffset = 0
+
+	res, err := h.equipmentService.List(ctx, req)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	if res.Total > int64(len(res.List)) {
+		logger.ResponseErr(ctx, logger.ErrExportTooLarge.Error(), logger.ErrExportTooLarge, http.StatusBadRequest)
+		return
+	}
+
+	writeLabels(ctx, format, code, "labels", res.List)
+}
+
+func labelParams(ctx *gin.Context) (format, code string, ok bool) {
+	format = ctx.DefaultQuery("format", label.FormatPDF)
+	code = ctx.DefaultQuery("code", label.CodeQR)
+
+	if !label.IsValid(format, code) {
+		logger.ResponseErr(ctx, logger.ErrUnsupportedFormat.Error(), logger.ErrUnsupportedFormat, http.StatusBadRequest)
+		return "", "", false
+	}
+
+	return format, code, true
+}
+
+func writeLabels(ctx *gin.Context, format, code, name string, equipments []*model.Equipment) {
+	labels := make([]*label.Label, len(equipments))
+	for i, e := range equipments {
+		labels[i] = &label.Label{
+			ID:           e.ID,
+			SerialNumber: e.SerialNumber,
+			Profile:      profileTitle(e.Profile),
+			Company:      companyTitle(e.Company),
+		}
+	}
+
+	if err := label.Write(ctx, format, code, name, labels); err != nil {
+		if errors.Is(err, logger.ErrUnencodableLabel) {
+			logger.ResponseErr(ctx, logger.ErrUnencodableLabel.Error(), err, http.StatusBadRequest)
+			return
+		}
+		logger.ResponseErr(ctx, logger.MsgFailedToExport, err, http.StatusInternalServerError)
+	}
+}
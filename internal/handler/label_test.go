@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/label"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+	"github.com/oatsmoke/warehouse_backend/internal/service"
+)
+
+// labelEquipmentService lists total equipment with the serial number, at
+// most the requested limit.
+type labelEquipmentService struct {
+	service.Equipment
+	serialNumber string
+	total        int
+	qp           *dto.QueryParams
+}
+
+func (s *labelEquipmentService) List(_ context.Context, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Equipment], error) {
+	s.qp = qp
+	list := make([]*model.Equipment, 0, min(s.total, int(qp.PaginationLimit)))
+	for i := range cap(list) {
+		list = append(list, &model.Equipment{ID: int64(i + 1), SerialNumber: s.serialNumber})
+	}
+
+	return &dto.ListResponse[[]*model.Equipment]{
+		List:  list,
+		Total: int64(s.total),
+	}, nil
+}
+
+func TestEquipmentHandler_Labels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		serialNumber string
+		total        int
+		wantStatus   int
+	}{
+		{
+			name:       "labels by ids",
+			query:      "?ids=1&ids=2",
+			total:      2,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "labels by filter",
+			query:      "?search=SN&format=svg",
+			total:      3,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "refuse labels without ids or filter",
+			query:      "",
+			total:      2,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "refuse more labels than the limit",
+			query:      "?search=SN",
+			total:      int(label.MaxLabels) + 1,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "refuse code128 with cyrillic serial number",
+			query:        "?ids=1&code=code128",
+			serialNumber: "СН-001",
+			total:        1,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:       "refuse unknown format",
+			query:      "?ids=1&format=gif",
+			total:      1,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &labelEquipmentService{serialNumber: tt.serialNumber, total: tt.total}
+			if s.serialNumber == "" {
+				s.serialNumber = "SN-001"
+			}
+			h := NewEquipmentHandler(s)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/equipments/labels"+tt.query, nil)
+
+			h.Labels(ctx)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Labels() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if s.qp != nil && s.qp.PaginationLimit != label.MaxLabels {
+				t.Errorf("Labels() limit = %v, want %v", s.qp.PaginationLimit, label.MaxLabels)
+			}
+		})
+	}
+}
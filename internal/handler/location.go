@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/oatsmoke/warehouse_backend/internal/service"
 )
 
@@ -19,58 +23,230 @@ func NewLocationHandler(locationService service.Location) *LocationHandler {
 	}
 }
 
+func (h *LocationHandler) Move(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	var req *dto.MoveEquipmentRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.LocationService.Move(ctx, userId, req); err != nil {
+		moveErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, "")
+}
+
+func (h *LocationHandler) Replace(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	var req *dto.ReplaceEquipmentRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.LocationService.Replace(ctx, userId, req)
+	if err != nil {
+		moveErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (h *LocationHandler) Undo(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	userRole, err := getUserRole(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.LocationService.Undo(ctx, userId, userRole, id); err != nil {
+		switch {
+		case errors.Is(err, logger.ErrNotMoveOwner), errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		case errors.Is(err, logger.ErrIllegalMove):
+			logger.ResponseErr(ctx, logger.ErrIllegalMove.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrEquipmentDeleted):
+			logger.ResponseErr(ctx, logger.ErrEquipmentDeleted.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrEquipmentWrittenOff):
+			logger.ResponseErr(ctx, logger.ErrEquipmentWrittenOff.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrLocationChanged):
+			logger.ResponseErr(ctx, logger.ErrLocationChanged.Error(), err, http.StatusConflict)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToDelete, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, "")
+}
+
+func (h *LocationHandler) History(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	req := list_filter.ParseQueryParams(ctx)
+
+	res, err := h.LocationService.History(ctx, id, req)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
 func (h *LocationHandler) List(ctx *gin.Context) {
 	req := list_filter.ParseQueryParams(ctx)
+	if !exportFormat(ctx, req.Format) {
+		return
+	}
 
-	res, err := h.LocationService.List(ctx, req.ParamID)
+	res, err := h.LocationService.List(ctx, req)
 	if err != nil {
-		logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+		getErrResponse(ctx, err)
+		return
+	}
+
+	if req.Format != "" {
+		writeListExport(ctx, req, "locations", equipmentColumns, res, h.LocationService.List)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, res)
 }
 
-//// TransferTo is equipment transfer to
-//func (h *LocationHandler) TransferTo(ctx *gin.Context) {
-//	userId, err := getUserId(ctx)
-//	if err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusUnauthorized)
-//		return
-//	}
-//
-//	var request []*model.RequestLocation
-//	if err := ctx.BindJSON(&request); err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusBadRequest)
-//		return
-//	}
-//
-//	if err := h.LocationService.TransferTo(ctx, userId, request); err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusInternalServerError)
-//		return
-//	}
-//
-//	logger.InfoInConsole("transferred")
-//	ctx.JSON(http.StatusOK, "")
-//}
-//
-//// Delete is equipment location delete
-//func (h *LocationHandler) Delete(ctx *gin.Context) {
-//	var location *model.Location
-//	if err := ctx.BindJSON(&location); err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusBadRequest)
-//		return
-//	}
-//
-//	if err := h.LocationService.Delete(ctx, location.ID); err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusInternalServerError)
-//		return
-//	}
-//
-//	logger.InfoInConsole(fmt.Sprintf("%d, deleted", location.ID))
-//	ctx.JSON(http.StatusOK, "")
-//}
-//
+func (h *LocationHandler) ListByEmployee(ctx *gin.Context) {
+	h.listBy(ctx, list_filter.ParamEmployee)
+}
+
+func (h *LocationHandler) ListByContract(ctx *gin.Context) {
+	h.listBy(ctx, list_filter.ParamContract)
+}
+
+func (h *LocationHandler) listBy(ctx *gin.Context, param string) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	req := list_filter.ParseQueryParams(ctx)
+	req.Param = param
+	req.ParamID = id
+
+	if !exportFormat(ctx, req.Format) {
+		return
+	}
+
+	res, err := h.LocationService.List(ctx, req)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	if req.Format != "" {
+		writeListExport(ctx, req, "locations", equipmentColumns, res, h.LocationService.List)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *LocationHandler) DepartmentBalance(ctx *gin.Context) {
+	var req *dto.DepartmentBalanceRequest
+	if err := ctx.BindQuery(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	if !exportFormat(ctx, req.Format) {
+		return
+	}
+
+	res, err := h.LocationService.DepartmentBalance(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrInvalidDateRange):
+			logger.ResponseErr(ctx, logger.ErrInvalidDateRange.Error(), err, http.StatusBadRequest)
+		default:
+			getErrResponse(ctx, err)
+		}
+		return
+	}
+
+	if req.Format != "" {
+		total := *res.Total
+		total.Category = &model.Category{Title: totalTitle}
+		writeExport(ctx, req.Format, "department_balance", departmentBalanceColumns, append(res.Rows, &total))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func moveErrResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, logger.ErrOutOfScope):
+		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+	case errors.Is(err, logger.ErrInvalidDestination):
+		logger.ResponseErr(ctx, logger.ErrInvalidDestination.Error(), err, http.StatusBadRequest)
+	case errors.Is(err, logger.ErrIllegalMove):
+		logger.ResponseErr(ctx, logger.ErrIllegalMove.Error(), err, http.StatusConflict)
+	case errors.Is(err, logger.ErrEquipmentDeleted):
+		logger.ResponseErr(ctx, logger.ErrEquipmentDeleted.Error(), err, http.StatusConflict)
+	case errors.Is(err, logger.ErrEquipmentWrittenOff):
+		logger.ResponseErr(ctx, logger.ErrEquipmentWrittenOff.Error(), err, http.StatusConflict)
+	case errors.Is(err, logger.ErrEquipmentInRepair):
+		logger.ResponseErr(ctx, logger.ErrEquipmentInRepair.Error(), err, http.StatusConflict)
+	case errors.Is(err, logger.ErrEquipmentReserved):
+		logger.ResponseErr(ctx, logger.ErrEquipmentReserved.Error(), err, http.StatusConflict)
+	case errors.Is(err, logger.ErrLocationChanged):
+		logger.ResponseErr(ctx, logger.ErrLocationChanged.Error(), err, http.StatusConflict)
+	default:
+		logger.ResponseErr(ctx, logger.MsgFailedToInsert, err, http.StatusInternalServerError)
+	}
+}
+
+// getErrResponse answers a failed read, reading out of the department
+// scope of the user is forbidden.
+func getErrResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, logger.ErrOutOfScope):
+		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+	default:
+		logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+	}
+}
+
 //// GetById is equipment get by id
 //func (h *LocationHandler) GetById(ctx *gin.Context) {
 //	var equipment *model.Equipment
@@ -106,63 +282,3 @@ func (h *LocationHandler) List(ctx *gin.Context) {
 //	logger.InfoInConsole(fmt.Sprintf("%d, get", request))
 //	ctx.JSON(http.StatusOK, res)
 //}
-//
-//// GetHistory is equipment get history
-//func (h *LocationHandler) GetHistory(ctx *gin.Context) {
-//	var location *model.Location
-//	if err := ctx.BindJSON(&location); err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusBadRequest)
-//		return
-//	}
-//
-//	res, err := h.LocationService.GetHistory(ctx, location.Equipment.ID)
-//	if err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusInternalServerError)
-//		return
-//	}
-//
-//	logger.InfoInConsole(fmt.Sprintf("%d, get", location.Equipment.ID))
-//	ctx.JSON(http.StatusOK, res)
-//}
-//
-//// GetByLocation is equipment get by location
-//func (h *LocationHandler) GetByLocation(ctx *gin.Context) {
-//	location := new(model.Location)
-//	location.ToDepartment = new(model.Department)
-//	location.ToEmployee = new(model.Employee)
-//	location.ToContract = new(model.Contract)
-//	if err := ctx.BindJSON(&location); err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusBadRequest)
-//		return
-//	}
-//
-//	res, err := h.LocationService.GetByLocation(ctx, location.ToDepartment.ID, location.ToEmployee.ID, location.ToContract.ID)
-//	if err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusInternalServerError)
-//		return
-//	}
-//
-//	logger.InfoInConsole(fmt.Sprintf("sent location - department: %d, employee: %d, contract: %d", location.ToDepartment.ID, location.ToEmployee.ID, location.ToContract.ID))
-//	ctx.JSON(http.StatusOK, res)
-//}
-//
-//// ReportByCategory is equipment report by category
-//func (h *LocationHandler) ReportByCategory(ctx *gin.Context) {
-//	request := struct {
-//		DepartmentId int64      `json:"departmentId"`
-//		Date         *time.Time `json:"date"`
-//	}{}
-//	if err := ctx.BindJSON(&request); err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusBadRequest)
-//		return
-//	}
-//
-//	res, err := h.LocationService.ReportByCategory(ctx, request.DepartmentId, request.Date)
-//	if err != nil {
-//		logger.ErrResponse(ctx, err, http.StatusInternalServerError)
-//		return
-//	}
-//
-//	logger.InfoInConsole("sent report")
-//	ctx.JSON(http.StatusOK, res)
-//}
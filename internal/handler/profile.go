@@ -35,9 +35,14 @@ func (h *ProfileHandler) Create(ctx *gin.Context) {
 		Category: &model.Category{
 			ID: req.CategoryID,
 		},
+		SerialRule: req.SerialRule,
 	}
 
 	if err := h.profileService.Create(ctx, profile); err != nil {
+		if errors.Is(err, logger.ErrInvalidSerialRule) {
+			logger.ResponseErr(ctx, logger.ErrInvalidSerialRule.Error(), err, http.StatusBadRequest)
+			return
+		}
 		if errors.Is(err, logger.ErrAlreadyExists) {
 			logger.ResponseErr(ctx, logger.ErrAlreadyExists.Error(), err, http.StatusConflict)
 			return
@@ -84,9 +89,14 @@ func (h *ProfileHandler) Update(ctx *gin.Context) {
 		Category: &model.Category{
 			ID: req.CategoryID,
 		},
+		SerialRule: req.SerialRule,
 	}
 
 	if err := h.profileService.Update(ctx, profile); err != nil {
+		if errors.Is(err, logger.ErrInvalidSerialRule) {
+			logger.ResponseErr(ctx, logger.ErrInvalidSerialRule.Error(), err, http.StatusBadRequest)
+			return
+		}
 		logger.ResponseErr(ctx, logger.MsgFailedToUpdate, err, http.StatusInternalServerError)
 		return
 	}
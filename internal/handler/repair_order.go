@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/service"
+)
+
+type RepairOrderHandler struct {
+	repairOrderService service.RepairOrder
+}
+
+func NewRepairOrderHandler(repairOrderService service.RepairOrder) *RepairOrderHandler {
+	return &RepairOrderHandler{
+		repairOrderService: repairOrderService,
+	}
+}
+
+func (h *RepairOrderHandler) Send(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	var req *dto.SendRepairRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.repairOrderService.Send(ctx, userId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrAlreadyExists):
+			logger.ResponseErr(ctx, logger.ErrAlreadyExists.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrEquipmentNotFound):
+			logger.ResponseErr(ctx, logger.ErrEquipmentNotFound.Error(), err, http.StatusNotFound)
+		default:
+			moveErrResponse(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+func (h *RepairOrderHandler) Read(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.repairOrderService.Read(ctx, id)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *RepairOrderHandler) List(ctx *gin.Context) {
+	req := list_filter.ParseQueryParams(ctx)
+
+	res, err := h.repairOrderService.List(ctx, ctx.Query("status"), req)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *RepairOrderHandler) Update(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	var req *dto.UpdateRepairRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.repairOrderService.Update(ctx, id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrUnknownRepairStatus):
+			logger.ResponseErr(ctx, logger.ErrUnknownRepairStatus.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrIllegalRepairStatus):
+			logger.ResponseErr(ctx, logger.ErrIllegalRepairStatus.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrRepairOrderChanged):
+			logger.ResponseErr(ctx, logger.ErrRepairOrderChanged.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToUpdate, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *RepairOrderHandler) Return(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	var req *dto.ReturnRepairRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.repairOrderService.Return(ctx, userId, id, req)
+	if err != nil {
+		if errors.Is(err, logger.ErrIllegalRepairStatus) {
+			logger.ResponseErr(ctx, logger.ErrIllegalRepairStatus.Error(), err, http.StatusConflict)
+			return
+		}
+		moveErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
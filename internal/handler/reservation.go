@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/service"
+)
+
+type ReservationHandler struct {
+	reservationService service.Reservation
+}
+
+func NewReservationHandler(reservationService service.Reservation) *ReservationHandler {
+	return &ReservationHandler{
+		reservationService: reservationService,
+	}
+}
+
+func (h *ReservationHandler) Create(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	var req *dto.ReservationRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.reservationService.Create(ctx, userId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrInvalidDateRange):
+			logger.ResponseErr(ctx, logger.ErrInvalidDateRange.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrNotInStorage):
+			logger.ResponseErr(ctx, logger.ErrNotInStorage.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrAlreadyExists):
+			logger.ResponseErr(ctx, logger.ErrAlreadyExists.Error(), err, http.StatusConflict)
+		default:
+			moveErrResponse(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+func (h *ReservationHandler) Read(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.reservationService.Read(ctx, id)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *ReservationHandler) List(ctx *gin.Context) {
+	req := list_filter.ParseQueryParams(ctx)
+
+	res, err := h.reservationService.List(ctx, ctx.Query("status"), req)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *ReservationHandler) Cancel(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.reservationService.Cancel(ctx, userId, id); err != nil {
+		switch {
+		case errors.Is(err, logger.ErrReservationClosed):
+			logger.ResponseErr(ctx, logger.ErrReservationClosed.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToUpdate, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "")
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/service"
+)
+
+type StockMinimumHandler struct {
+	stockMinimumService service.StockMinimum
+}
+
+func NewStockMinimumHandler(stockMinimumService service.StockMinimum) *StockMinimumHandler {
+	return &StockMinimumHandler{
+		stockMinimumService: stockMinimumService,
+	}
+}
+
+func (h *StockMinimumHandler) Set(ctx *gin.Context) {
+	var req *dto.StockMinimumRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.stockMinimumService.Set(ctx, req)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToInsert, err, http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+func (h *StockMinimumHandler) Delete(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.stockMinimumService.Delete(ctx, id); err != nil {
+		if errors.Is(err, logger.ErrNoRowsAffected) {
+			logger.ResponseErr(ctx, logger.MsgFailedToDelete, err, http.StatusNotFound)
+			return
+		}
+		logger.ResponseErr(ctx, logger.MsgFailedToDelete, err, http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, "")
+}
+
+func (h *StockMinimumHandler) List(ctx *gin.Context) {
+	res, err := h.stockMinimumService.List(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *StockMinimumHandler) LowStock(ctx *gin.Context) {
+	res, err := h.stockMinimumService.LowStock(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToGet, err, http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
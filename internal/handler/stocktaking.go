@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/service"
+)
+
+type StocktakingHandler struct {
+	stocktakingService service.Stocktaking
+}
+
+func NewStocktakingHandler(stocktakingService service.Stocktaking) *StocktakingHandler {
+	return &StocktakingHandler{
+		stocktakingService: stocktakingService,
+	}
+}
+
+func (h *StocktakingHandler) Open(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	var req *dto.OpenStocktakingRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.stocktakingService.Open(ctx, userId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrInvalidDestination):
+			logger.ResponseErr(ctx, logger.ErrInvalidDestination.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToInsert, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+func (h *StocktakingHandler) Read(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.stocktakingService.Read(ctx, id)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *StocktakingHandler) Scan(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	var req *dto.ScanStocktakingRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.stocktakingService.Scan(ctx, userId, id, req); err != nil {
+		stocktakingErrResponse(ctx, err, logger.MsgFailedToInsert)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, "")
+}
+
+func (h *StocktakingHandler) Close(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.stocktakingService.Close(ctx, userId, id)
+	if err != nil {
+		stocktakingErrResponse(ctx, err, logger.MsgFailedToUpdate)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *StocktakingHandler) Apply(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	var req *dto.ApplyStocktakingRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.stocktakingService.Apply(ctx, userId, id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrStocktakingNotClosed), errors.Is(err, logger.ErrStocktakingApplied):
+			logger.ResponseErr(ctx, err.Error(), err, http.StatusConflict)
+		default:
+			moveErrResponse(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func stocktakingErrResponse(ctx *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, logger.ErrStocktakingClosed):
+		logger.ResponseErr(ctx, logger.ErrStocktakingClosed.Error(), err, http.StatusConflict)
+	case errors.Is(err, logger.ErrOutOfScope):
+		logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+	default:
+		logger.ResponseErr(ctx, msg, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/service"
+)
+
+type WriteOffHandler struct {
+	writeOffService service.WriteOff
+}
+
+func NewWriteOffHandler(writeOffService service.WriteOff) *WriteOffHandler {
+	return &WriteOffHandler{
+		writeOffService: writeOffService,
+	}
+}
+
+func (h *WriteOffHandler) Create(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	var req *dto.WriteOffRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.writeOffService.Create(ctx, userId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, logger.ErrUnknownWriteOffReason):
+			logger.ResponseErr(ctx, logger.ErrUnknownWriteOffReason.Error(), err, http.StatusBadRequest)
+		case errors.Is(err, logger.ErrAlreadyExists):
+			logger.ResponseErr(ctx, logger.ErrAlreadyExists.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrEquipmentDeleted):
+			logger.ResponseErr(ctx, logger.ErrEquipmentDeleted.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrEquipmentWrittenOff):
+			logger.ResponseErr(ctx, logger.ErrEquipmentWrittenOff.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrEquipmentNotFound):
+			logger.ResponseErr(ctx, logger.ErrEquipmentNotFound.Error(), err, http.StatusNotFound)
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToInsert, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+func (h *WriteOffHandler) Read(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.writeOffService.Read(ctx, id)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *WriteOffHandler) List(ctx *gin.Context) {
+	req := list_filter.ParseQueryParams(ctx)
+
+	res, err := h.writeOffService.List(ctx, ctx.Query("status"), req)
+	if err != nil {
+		getErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *WriteOffHandler) Approve(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	var req *dto.ApproveWriteOffRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.writeOffService.Approve(ctx, userId, id, req)
+	if err != nil {
+		if errors.Is(err, logger.ErrWriteOffDecided) {
+			logger.ResponseErr(ctx, logger.ErrWriteOffDecided.Error(), err, http.StatusConflict)
+			return
+		}
+		moveErrResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *WriteOffHandler) Reject(ctx *gin.Context) {
+	userId, err := getUserId(ctx)
+	if err != nil {
+		logger.ResponseErr(ctx, "", err, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.ResponseErr(ctx, logger.MsgFailedToParse, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.writeOffService.Reject(ctx, userId, id); err != nil {
+		switch {
+		case errors.Is(err, logger.ErrWriteOffDecided):
+			logger.ResponseErr(ctx, logger.ErrWriteOffDecided.Error(), err, http.StatusConflict)
+		case errors.Is(err, logger.ErrOutOfScope):
+			logger.ResponseErr(ctx, logger.MsgAccessDenied, err, http.StatusForbidden)
+		default:
+			logger.ResponseErr(ctx, logger.MsgFailedToUpdate, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "")
+}
@@ -17,21 +17,27 @@ type SendTo struct {
 	Password string
 }
 
+// LowStock is the data of a low-stock alert for one recipient.
+type LowStock struct {
+	Name  string
+	Items []*LowStockItem
+}
+
+type LowStockItem struct {
+	Profile     string
+	Place       string
+	Quantity    int64
+	MinQuantity int32
+}
+
 //go:embed templates/*.txt templates/*.html
 var templatesFS embed.FS
 
 func Send(data []*SendTo) error {
-	textFS, err := texttemplate.ParseFS(templatesFS, "templates/email.txt")
+	textTpl, htmlTpl, err := parseTemplates("email")
 	if err != nil {
-		return logger.Error(logger.MsgFailedToParse, err)
+		return err
 	}
-	textTpl := texttemplate.Must(textFS, err)
-
-	htmlFS, err := htmltemplate.ParseFS(templatesFS, "templates/email.html")
-	if err != nil {
-		return logger.Error(logger.MsgFailedToParse, err)
-	}
-	htmlTpl := htmltemplate.Must(htmlFS, err)
 
 	var messages []*mail.Msg
 
@@ -40,31 +46,89 @@ func Send(data []*SendTo) error {
 			d.Name = d.Username
 		}
 
-		message := mail.NewMsg()
-
-		if err := message.FromFormat("WareHouse", env.GetSmtpUser()); err != nil {
-			return logger.Error(logger.MsgFailedToSetSenderAddress, err)
+		message, err := newMessage(d.Name, d.Email, "Authorization data", textTpl, htmlTpl, d)
+		if err != nil {
+			return err
 		}
 
-		if err := message.AddToFormat(d.Name, d.Email); err != nil {
-			return logger.Error(logger.MsgFailedToAddRecipientAddress, err)
-		}
+		messages = append(messages, message)
+	}
 
-		message.SetDate()
-		message.SetMessageID()
-		message.SetBulk()
-		message.Subject("Authorization data")
+	return dialAndSend(messages)
+}
+
+// SendLowStock sends the same list of low-stock items to every recipient.
+func SendLowStock(to []*SendTo, items []*LowStockItem) error {
+	textTpl, htmlTpl, err := parseTemplates("low_stock")
+	if err != nil {
+		return err
+	}
+
+	var messages []*mail.Msg
+
+	for _, t := range to {
+		if t.Name == "" {
+			t.Name = t.Username
+		}
 
-		if err := message.SetBodyTextTemplate(textTpl, d); err != nil {
-			return logger.Error(logger.MsgFailedToSetBodyText, err)
+		data := &LowStock{
+			Name:  t.Name,
+			Items: items,
 		}
 
-		if err := message.AddAlternativeHTMLTemplate(htmlTpl, d); err != nil {
-			return logger.Error(logger.MsgFailedToSetBodyHTML, err)
+		message, err := newMessage(t.Name, t.Email, "Low stock", textTpl, htmlTpl, data)
+		if err != nil {
+			return err
 		}
 
 		messages = append(messages, message)
 	}
+
+	return dialAndSend(messages)
+}
+
+func parseTemplates(name string) (*texttemplate.Template, *htmltemplate.Template, error) {
+	textTpl, err := texttemplate.ParseFS(templatesFS, "templates/"+name+".txt")
+	if err != nil {
+		return nil, nil, logger.Error(logger.MsgFailedToParse, err)
+	}
+
+	htmlTpl, err := htmltemplate.ParseFS(templatesFS, "templates/"+name+".html")
+	if err != nil {
+		return nil, nil, logger.Error(logger.MsgFailedToParse, err)
+	}
+
+	return textTpl, htmlTpl, nil
+}
+
+func newMessage(name, address, subject string, textTpl *texttemplate.Template, htmlTpl *htmltemplate.Template, data any) (*mail.Msg, error) {
+	message := mail.NewMsg()
+
+	if err := message.FromFormat("WareHouse", env.GetSmtpUser()); err != nil {
+		return nil, logger.Error(logger.MsgFailedToSetSenderAddress, err)
+	}
+
+	if err := message.AddToFormat(name, address); err != nil {
+		return nil, logger.Error(logger.MsgFailedToAddRecipientAddress, err)
+	}
+
+	message.SetDate()
+	message.SetMessageID()
+	message.SetBulk()
+	message.Subject(subject)
+
+	if err := message.SetBodyTextTemplate(textTpl, data); err != nil {
+		return nil, logger.Error(logger.MsgFailedToSetBodyText, err)
+	}
+
+	if err := message.AddAlternativeHTMLTemplate(htmlTpl, data); err != nil {
+		return nil, logger.Error(logger.MsgFailedToSetBodyHTML, err)
+	}
+
+	return message, nil
+}
+
+func dialAndSend(messages []*mail.Msg) error {
 	client, err := mail.NewClient(env.GetSmtpHost(),
 		mail.WithSMTPAuth(mail.SMTPAuthAutoDiscover),
 		mail.WithTLSPortPolicy(mail.TLSMandatory),
@@ -19,6 +19,9 @@ const (
 	SmtpHost     = "SMTP_HOST"
 	SmtpUser     = "SMTP_USER"
 	SmtpPassword = "SMTP_PASSWORD"
+	StockCheck   = "STOCK_CHECK"
+	KafkaBrokers = "KAFKA_BROKERS"
+	RolePerms    = "ROLE_PERMISSIONS"
 )
 
 func GetLogLevel() string {
@@ -69,6 +72,18 @@ func GetSmtpPassword() string {
 	return get(SmtpPassword)
 }
 
+func GetStockCheck() string {
+	return get(StockCheck)
+}
+
+func GetKafkaBrokers() string {
+	return get(KafkaBrokers)
+}
+
+func GetRolePermissions() string {
+	return get(RolePerms)
+}
+
 func get(key string) string {
 	val, ok := os.LookupEnv(key)
 	if ok {
@@ -108,6 +123,15 @@ func get(key string) string {
 		case SmtpPassword:
 			message(SmtpPassword)
 			return ""
+		case StockCheck:
+			message(StockCheck)
+			return "300"
+		case KafkaBrokers:
+			message(KafkaBrokers)
+			return "localhost:9092"
+		case RolePerms:
+			// the built-in table is used
+			return ""
 		default:
 			logger.Info(fmt.Sprintf("%s not found", key))
 			return ""
@@ -0,0 +1,176 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	contentTypeCSV  = "text/csv; charset=utf-8"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	sheet           = "Sheet1"
+)
+
+// utf8BOM lets spreadsheet applications detect cyrillic headers in csv.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type Column[T any] struct {
+	Title string
+	Value func(T) any
+}
+
+func NewColumn[T any](title string, value func(T) any) Column[T] {
+	return Column[T]{
+		Title: title,
+		Value: value,
+	}
+}
+
+func IsValid(format string) bool {
+	return format == dto.FormatCSV || format == dto.FormatXLSX
+}
+
+// Write sends rows to the client as a file attachment in the given format.
+func Write[T any](ctx *gin.Context, format, name string, columns []Column[T], rows []T) error {
+	return Stream(ctx, format, name, columns, rows, nil)
+}
+
+// Stream sends the first page and then the pages returned by next, until an
+// empty one, to the client as a file attachment in the given format. A nil
+// next sends the first page only. Csv rows are sent page by page, so an
+// error after the first page leaves the file cut off; xlsx is kept by the
+// excelize stream writer and sent once all pages are read.
+func Stream[T any](ctx *gin.Context, format, name string, columns []Column[T], first []T, next func() ([]T, error)) error {
+	var err error
+	switch format {
+	case dto.FormatCSV:
+		err = streamCSV(ctx, name, columns, first, next)
+	case dto.FormatXLSX:
+		err = streamXLSX(ctx, name, columns, first, next)
+	default:
+		err = logger.ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return logger.Error(logger.MsgFailedToExport, err)
+	}
+
+	return nil
+}
+
+// pages calls fn for the first page and every next page.
+func pages[T any](first []T, next func() ([]T, error), fn func([]T) error) error {
+	for rows := first; len(rows) > 0; {
+		if err := fn(rows); err != nil {
+			return err
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		var err error
+		if rows, err = next(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func attach(ctx *gin.Context, name, format, contentType string) {
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+format))
+	ctx.Header("Content-Type", contentType)
+	ctx.Status(http.StatusOK)
+}
+
+func streamCSV[T any](ctx *gin.Context, name string, columns []Column[T], first []T, next func() ([]T, error)) error {
+	attach(ctx, name, dto.FormatCSV, contentTypeCSV)
+	if _, err := ctx.Writer.Write(utf8BOM); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(ctx.Writer)
+	cw.Comma = ';'
+
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.Title
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	if err := pages(first, next, func(rows []T) error {
+		for _, row := range rows {
+			for i, column := range columns {
+				record[i] = fmt.Sprint(column.Value(row))
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func streamXLSX[T any](ctx *gin.Context, name string, columns []Column[T], first []T, next func() ([]T, error)) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	record := make([]any, len(columns))
+	for i, column := range columns {
+		record[i] = column.Title
+	}
+	if err := sw.SetRow("A1", record); err != nil {
+		return err
+	}
+
+	n := 2
+	if err := pages(first, next, func(rows []T) error {
+		for _, row := range rows {
+			for i, column := range columns {
+				record[i] = column.Value(row)
+			}
+
+			cell, err := excelize.CoordinatesToCellName(1, n)
+			if err != nil {
+				return err
+			}
+
+			if err := sw.SetRow(cell, record); err != nil {
+				return err
+			}
+			n++
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	attach(ctx, name, dto.FormatXLSX, contentTypeXLSX)
+	return f.Write(ctx.Writer)
+}
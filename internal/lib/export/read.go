@@ -0,0 +1,69 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/xuri/excelize/v2"
+)
+
+// FormatFromFilename returns the file format by extension of the uploaded file.
+func FormatFromFilename(filename string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+// Read returns all rows of the first sheet (xlsx) or the whole file (csv).
+func Read(r io.Reader, format string) ([][]string, error) {
+	var (
+		rows [][]string
+		err  error
+	)
+	switch format {
+	case dto.FormatCSV:
+		rows, err = readCSV(r)
+	case dto.FormatXLSX:
+		rows, err = readXLSX(r)
+	default:
+		err = logger.ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToParse, err)
+	}
+
+	return rows, nil
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	// files exported by this service use ';', other tools usually use ','
+	if header, _, _ := bytes.Cut(data, []byte("\n")); bytes.Contains(header, []byte(";")) {
+		cr.Comma = ';'
+	}
+
+	return cr.ReadAll()
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return f.GetRows(f.GetSheetName(0))
+}
@@ -2,7 +2,8 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
+	"strings"
+	"sync"
 
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 	"github.com/segmentio/kafka-go"
@@ -10,40 +11,87 @@ import (
 
 const topic = "actions"
 
-func SendMessage(text string) {
-	broker := "localhost:9092"
-	value := struct {
-		Action string `json:"action"`
-	}{
-		Action: text,
-	}
+type Message struct {
+	Key   string
+	Value []byte
+}
 
-	go func() {
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+// Publisher delivers messages to the broker, all of them or none.
+type Publisher interface {
+	Publish(ctx context.Context, messages ...*Message) error
+	Close() error
+}
 
-		writer := &kafka.Writer{
-			Addr:  kafka.TCP(broker),
-			Topic: topic,
-		}
-		defer writer.Close()
+type Writer struct {
+	writer *kafka.Writer
+}
 
-		marshalled, err := json.Marshal(&value)
-		if err != nil {
-			logger.Error("failed to marshalling message", err)
-		}
+// NewWriter connects to a comma separated list of brokers. Messages with the
+// same key go to the same partition, so they keep their order.
+func NewWriter(brokers string) *Writer {
+	return &Writer{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(strings.Split(brokers, ",")...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			MaxAttempts:  3,
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
 
-		msg := kafka.Message{
-			Key:   []byte("1"),
-			Value: marshalled,
+func (w *Writer) Publish(ctx context.Context, messages ...*Message) error {
+	msgs := make([]kafka.Message, len(messages))
+	for i, m := range messages {
+		msgs[i] = kafka.Message{
+			Key:   []byte(m.Key),
+			Value: m.Value,
 		}
+	}
 
-		if err := writer.WriteMessages(ctx, msg); err != nil {
-			logger.Error("failed to write message", err)
-		}
+	if err := w.writer.WriteMessages(ctx, msgs...); err != nil {
+		return logger.Error(logger.MsgFailedToPublish, err)
+	}
+
+	return nil
+}
+
+func (w *Writer) Close() error {
+	return w.writer.Close()
+}
+
+// Stub keeps published messages in memory instead of sending them, it fails
+// every Publish while Err is set.
+type Stub struct {
+	mu       sync.Mutex
+	messages []*Message
+	Err      error
+}
+
+func NewStub() *Stub {
+	return &Stub{}
+}
+
+func (s *Stub) Publish(_ context.Context, messages ...*Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Err != nil {
+		return logger.Error(logger.MsgFailedToPublish, s.Err)
+	}
+
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func (s *Stub) Close() error {
+	return nil
+}
 
-		logger.Info(text)
-	}()
+// Messages returns what was published so far.
+func (s *Stub) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return
+	return append([]*Message(nil), s.messages...)
 }
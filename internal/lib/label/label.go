@@ -0,0 +1,148 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+)
+
+const (
+	FormatPDF = "pdf"
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+const (
+	CodeQR      = "qr"
+	CodeCode128 = "code128"
+)
+
+const (
+	contentTypePDF = "application/pdf"
+	contentTypePNG = "image/png"
+	contentTypeSVG = "image/svg+xml"
+)
+
+// MaxLabels is the most labels one request renders.
+const MaxLabels int32 = 1000
+
+// payloadPrefix marks codes printed by this service, see Payload.
+const payloadPrefix = "EQ"
+
+// Label sizes are in millimetres; images use pxPerMM pixels per millimetre.
+const (
+	labelWidth  = 100.0
+	labelHeight = 50.0
+	lineHeight  = 6.0
+	fontSize    = 10.0
+	pxPerMM     = 6
+)
+
+type Label struct {
+	ID           int64
+	SerialNumber string
+	Profile      string
+	Company      string
+}
+
+type box struct {
+	x, y, w, h float64
+}
+
+func IsValid(format, code string) bool {
+	return (format == FormatPDF || format == FormatPNG || format == FormatSVG) &&
+		(code == CodeQR || code == CodeCode128)
+}
+
+// Payload is the content of the printed code: "EQ:<id>:<serial number>".
+func Payload(id int64, serialNumber string) string {
+	return fmt.Sprintf("%s:%d:%s", payloadPrefix, id, serialNumber)
+}
+
+// ParsePayload returns the equipment id and serial number of a code printed
+// by Payload; ok is false for any other string.
+func ParsePayload(payload string) (id int64, serialNumber string, ok bool) {
+	parts := strings.SplitN(payload, ":", 3)
+	if len(parts) != 3 || parts[0] != payloadPrefix {
+		return 0, "", false
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id < 1 {
+		return 0, "", false
+	}
+
+	return id, parts[2], true
+}
+
+// Write sends labels to the client: a pdf page per label, or one image with
+// the labels stacked vertically. The labels are rendered before anything is
+// sent, so a failure can still be answered with an error.
+func Write(ctx *gin.Context, format, code, name string, labels []*Label) error {
+	var (
+		buf         bytes.Buffer
+		contentType string
+		err         error
+	)
+	switch format {
+	case FormatPDF:
+		contentType = contentTypePDF
+		err = writePDF(&buf, code, labels)
+	case FormatPNG:
+		contentType = contentTypePNG
+		err = writePNG(&buf, code, labels)
+	case FormatSVG:
+		contentType = contentTypeSVG
+		err = writeSVG(&buf, code, labels)
+	default:
+		err = logger.ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return logger.Error(logger.MsgFailedToExport, err)
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", name+"."+format))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+	return nil
+}
+
+func encode(code string, label *Label) (barcode.Barcode, error) {
+	payload := Payload(label.ID, label.SerialNumber)
+	switch code {
+	case CodeQR:
+		return qr.Encode(payload, qr.M, qr.Auto)
+	case CodeCode128:
+		// code128 holds ascii only, a serial number with other letters has
+		// to go into a qr code
+		bc, err := code128.Encode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", logger.ErrUnencodableLabel, err)
+		}
+		return bc, nil
+	default:
+		return nil, logger.ErrUnsupportedFormat
+	}
+}
+
+// layout returns where the code and the first text line go on a label,
+// the white space around the code is its quiet zone.
+func layout(code string) (codeBox box, textX, textY float64) {
+	if code == CodeQR {
+		return box{x: 5, y: 5, w: 40, h: 40}, 50, 12
+	}
+	// code128 needs a wider quiet zone than the label margin
+	return box{x: 8, y: 3, w: 84, h: 22}, 8, 32
+}
+
+func (l *Label) lines() []string {
+	return []string{l.SerialNumber, l.Profile, l.Company}
+}
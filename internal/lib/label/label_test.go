@@ -0,0 +1,254 @@
+package label
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+)
+
+func TestParsePayload(t *testing.T) {
+	type args struct {
+		payload string
+	}
+	tests := []struct {
+		name             string
+		args             args
+		wantID           int64
+		wantSerialNumber string
+		wantOk           bool
+	}{
+		{
+			name: "parse payload",
+			args: args{
+				payload: Payload(42, "SN-001"),
+			},
+			wantID:           42,
+			wantSerialNumber: "SN-001",
+			wantOk:           true,
+		},
+		{
+			name: "parse payload with colons in serial number",
+			args: args{
+				payload: Payload(7, "A:B:C"),
+			},
+			wantID:           7,
+			wantSerialNumber: "A:B:C",
+			wantOk:           true,
+		},
+		{
+			name: "parse payload with empty serial number",
+			args: args{
+				payload: "EQ:7:",
+			},
+			wantID:           7,
+			wantSerialNumber: "",
+			wantOk:           true,
+		},
+		{
+			name: "plain serial number",
+			args: args{
+				payload: "SN-001",
+			},
+			wantOk: false,
+		},
+		{
+			name: "other prefix",
+			args: args{
+				payload: "XX:42:SN-001",
+			},
+			wantOk: false,
+		},
+		{
+			name: "id is not a number",
+			args: args{
+				payload: "EQ:abc:SN-001",
+			},
+			wantOk: false,
+		},
+		{
+			name: "id is not positive",
+			args: args{
+				payload: "EQ:0:SN-001",
+			},
+			wantOk: false,
+		},
+		{
+			name: "no serial number part",
+			args: args{
+				payload: "EQ:42",
+			},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, serialNumber, ok := ParsePayload(tt.args.payload)
+			if ok != tt.wantOk {
+				t.Fatalf("ParsePayload() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if id != tt.wantID {
+				t.Errorf("ParsePayload() id = %v, want %v", id, tt.wantID)
+			}
+			if serialNumber != tt.wantSerialNumber {
+				t.Errorf("ParsePayload() serialNumber = %v, want %v", serialNumber, tt.wantSerialNumber)
+			}
+		})
+	}
+}
+
+func Test_encode(t *testing.T) {
+	type args struct {
+		code  string
+		label *Label
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "encode qr",
+			args: args{
+				code:  CodeQR,
+				label: &Label{ID: 1, SerialNumber: "SN-001"},
+			},
+		},
+		{
+			name: "encode qr with cyrillic serial number",
+			args: args{
+				code:  CodeQR,
+				label: &Label{ID: 1, SerialNumber: "СН-001"},
+			},
+		},
+		{
+			name: "encode code128",
+			args: args{
+				code:  CodeCode128,
+				label: &Label{ID: 1, SerialNumber: "SN-001"},
+			},
+		},
+		{
+			name: "refuse code128 with cyrillic serial number",
+			args: args{
+				code:  CodeCode128,
+				label: &Label{ID: 1, SerialNumber: "СН-001"},
+			},
+			wantErr: logger.ErrUnencodableLabel,
+		},
+		{
+			name: "refuse unknown code",
+			args: args{
+				code:  "ean13",
+				label: &Label{ID: 1, SerialNumber: "SN-001"},
+			},
+			wantErr: logger.ErrUnsupportedFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encode(tt.args.code, tt.args.label)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("encode() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("encode() error = %v", err)
+			}
+			if got.Content() != Payload(tt.args.label.ID, tt.args.label.SerialNumber) {
+				t.Errorf("encode() content = %v, want payload", got.Content())
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	labels := []*Label{
+		{ID: 1, SerialNumber: "SN-001", Profile: "Router", Company: "Company"},
+		{ID: 2, SerialNumber: "SN-002", Profile: "Router", Company: "Company"},
+	}
+
+	type args struct {
+		format string
+		code   string
+		labels []*Label
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "write pdf",
+			args: args{
+				format: FormatPDF,
+				code:   CodeQR,
+				labels: labels,
+			},
+		},
+		{
+			name: "write png",
+			args: args{
+				format: FormatPNG,
+				code:   CodeCode128,
+				labels: labels,
+			},
+		},
+		{
+			name: "write svg",
+			args: args{
+				format: FormatSVG,
+				code:   CodeQR,
+				labels: labels,
+			},
+		},
+		{
+			name: "refuse code128 with cyrillic serial number",
+			args: args{
+				format: FormatPDF,
+				code:   CodeCode128,
+				labels: append(labels, &Label{ID: 3, SerialNumber: "СН-003"}),
+			},
+			wantErr: logger.ErrUnencodableLabel,
+		},
+		{
+			name: "refuse unknown format",
+			args: args{
+				format: "gif",
+				code:   CodeQR,
+				labels: labels,
+			},
+			wantErr: logger.ErrUnsupportedFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			err := Write(ctx, tt.args.format, tt.args.code, "labels", tt.args.labels)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				// a refused label must leave the response untouched for the error
+				if w.Body.Len() != 0 {
+					t.Errorf("Write() wrote %d bytes on error", w.Body.Len())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if w.Code != http.StatusOK || w.Body.Len() == 0 {
+				t.Errorf("Write() status = %v, body length = %v", w.Code, w.Body.Len())
+			}
+		})
+	}
+}
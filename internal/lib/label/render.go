@@ -0,0 +1,197 @@
+package label
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/boombuler/barcode"
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// fontFamily is the Go font, it covers cyrillic titles.
+const fontFamily = "goregular"
+
+func writePDF(w io.Writer, code string, labels []*Label) error {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "L",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: labelWidth, Ht: labelHeight},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	pdf.SetFont(fontFamily, "", fontSize)
+
+	codeBox, textX, textY := layout(code)
+	for i, label := range labels {
+		bc, err := encode(code, label)
+		if err != nil {
+			return err
+		}
+
+		img, err := barcode.Scale(bc, int(codeBox.w)*pxPerMM, int(codeBox.h)*pxPerMM)
+		if err != nil {
+			return err
+		}
+
+		// gofpdf reads 8-bit png only, barcodes are drawn in 16-bit gray
+		gray := image.NewGray(img.Bounds())
+		draw.Draw(gray, gray.Bounds(), img, img.Bounds().Min, draw.Src)
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, gray); err != nil {
+			return err
+		}
+
+		name := "code" + strconv.Itoa(i)
+		options := gofpdf.ImageOptions{ImageType: "PNG"}
+
+		pdf.AddPage()
+		pdf.RegisterImageOptionsReader(name, options, &buf)
+		pdf.ImageOptions(name, codeBox.x, codeBox.y, codeBox.w, codeBox.h, false, options, 0, "")
+		for n, line := range label.lines() {
+			pdf.Text(textX, textY+float64(n)*lineHeight, line)
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+
+	return pdf.Output(w)
+}
+
+func writePNG(w io.Writer, code string, labels []*Label) error {
+	ttf, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return err
+	}
+
+	face, err := opentype.NewFace(ttf, &opentype.FaceOptions{
+		Size: fontSize * pxPerMM * 25.4 / 72,
+		DPI:  72,
+	})
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	width, height := int(labelWidth)*pxPerMM, int(labelHeight)*pxPerMM
+	img := image.NewRGBA(image.Rect(0, 0, width, height*len(labels)))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.Black),
+		Face: face,
+	}
+
+	codeBox, textX, textY := layout(code)
+	for i, label := range labels {
+		bc, err := encode(code, label)
+		if err != nil {
+			return err
+		}
+
+		scaled, err := barcode.Scale(bc, int(codeBox.w)*pxPerMM, int(codeBox.h)*pxPerMM)
+		if err != nil {
+			return err
+		}
+
+		top := i * height
+		at := image.Pt(int(codeBox.x)*pxPerMM, top+int(codeBox.y)*pxPerMM)
+		draw.Draw(img, scaled.Bounds().Add(at), scaled, image.Point{}, draw.Src)
+
+		for n, line := range label.lines() {
+			drawer.Dot = fixed.P(int(textX)*pxPerMM, top+int((textY+float64(n)*lineHeight)*pxPerMM))
+			drawer.DrawString(line)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func writeSVG(w io.Writer, code string, labels []*Label) error {
+	out := &writeTo{w: w}
+	codeBox, textX, textY := layout(code)
+
+	out.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%gmm" height="%gmm" viewBox="0 0 %g %g">`,
+		labelWidth, labelHeight*float64(len(labels)), labelWidth, labelHeight*float64(len(labels)))
+	out.printf(`<rect width="100%%" height="100%%" fill="#fff"/>`)
+
+	for i, label := range labels {
+		bc, err := encode(code, label)
+		if err != nil {
+			return err
+		}
+
+		top := float64(i) * labelHeight
+		bounds := bc.Bounds()
+		moduleW := codeBox.w / float64(bounds.Dx())
+		moduleH := codeBox.h / float64(bounds.Dy())
+
+		out.printf(`<g fill="#000">`)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			// dark modules in a row are merged into one rect
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if !isDark(bc.At(x, y)) {
+					continue
+				}
+
+				start := x
+				for x+1 < bounds.Max.X && isDark(bc.At(x+1, y)) {
+					x++
+				}
+
+				out.printf(`<rect x="%.3f" y="%.3f" width="%.3f" height="%.3f"/>`,
+					codeBox.x+float64(start-bounds.Min.X)*moduleW,
+					top+codeBox.y+float64(y-bounds.Min.Y)*moduleH,
+					float64(x-start+1)*moduleW,
+					moduleH,
+				)
+			}
+		}
+		out.printf(`</g>`)
+
+		for n, line := range label.lines() {
+			var text bytes.Buffer
+			if err := xml.EscapeText(&text, []byte(line)); err != nil {
+				return err
+			}
+
+			out.printf(`<text x="%g" y="%g" font-family="sans-serif" font-size="%g">%s</text>`,
+				textX, top+textY+float64(n)*lineHeight, fontSize*25.4/72, text.String())
+		}
+	}
+
+	out.printf(`</svg>`)
+	return out.err
+}
+
+// writeTo keeps the first write error of the svg renderer.
+type writeTo struct {
+	w   io.Writer
+	err error
+}
+
+func (w *writeTo) printf(format string, a ...any) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, a...)
+	}
+}
+
+func isDark(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r+g+b < 3*0x8000
+}
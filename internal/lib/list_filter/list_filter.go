@@ -17,6 +17,15 @@ const (
 	defaultParamID     int64 = 0
 )
 
+// exportPageSize is how many rows of an export are read at once.
+const exportPageSize int32 = 1000
+
+const (
+	ParamDepartment = "department"
+	ParamEmployee   = "employee"
+	ParamContract   = "contract"
+)
+
 func ParseQueryParams(c *gin.Context) *dto.QueryParams {
 	qp := new(dto.QueryParams)
 
@@ -59,5 +68,13 @@ func ParseQueryParams(c *gin.Context) *dto.QueryParams {
 		qp.ParamID = n
 	}
 
+	// an export holds the whole filtered list, it is read page by page
+	// from the start; an unknown format is kept for the handler to refuse
+	qp.Format = c.Query("format")
+	if qp.Format == dto.FormatCSV || qp.Format == dto.FormatXLSX {
+		qp.PaginationLimit = exportPageSize
+		qp.PaginationOffset = defaultOffset
+	}
+
 	return qp
 }
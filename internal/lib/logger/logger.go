@@ -130,6 +130,45 @@ var (
 	ErrUserIdNotFound          = errors.New("user id not found")
 	ErrUserRoleNotFound        = errors.New("user role not found")
 	ErrInvalidRole             = errors.New("invalid role")
+	ErrInvalidDestination      = errors.New("invalid destination")
+	ErrLocationChanged         = errors.New("location changed")
+	ErrIllegalMove             = errors.New("illegal move")
+	ErrEquipmentDeleted        = errors.New("equipment deleted")
+	ErrNotMoveOwner            = errors.New("move made by another user")
+	ErrInvalidDateRange        = errors.New("invalid date range")
+	ErrUnsupportedFormat       = errors.New("unsupported format")
+	ErrExportTooLarge          = errors.New("too many rows to export")
+	ErrNoLabelFilter           = errors.New("labels need ids or a filter")
+	ErrUnencodableLabel        = errors.New("label can not be encoded")
+	ErrLabelMismatch           = errors.New("label does not match the equipment")
+	ErrMissingColumn           = errors.New("missing column")
+	ErrEmptySerialNumber       = errors.New("empty serial number")
+	ErrDuplicateSerialNumber   = errors.New("duplicate serial number")
+	ErrUnknownCompany          = errors.New("unknown company")
+	ErrUnknownProfile          = errors.New("unknown profile")
+	ErrInvalidRows             = errors.New("invalid rows")
+	ErrStocktakingClosed       = errors.New("stocktaking closed")
+	ErrStocktakingNotClosed    = errors.New("stocktaking not closed")
+	ErrStocktakingApplied      = errors.New("stocktaking already applied")
+	ErrEquipmentNotFound       = errors.New("equipment not found")
+	ErrInvalidSerialRule       = errors.New("invalid serial rule")
+	ErrMergeSameEquipment      = errors.New("equipment can not be merged into itself")
+	ErrTimelineConflict        = errors.New("merged movement history is not coherent")
+	ErrMergeReserved           = errors.New("both merged equipment are reserved")
+	ErrEquipmentWrittenOff     = errors.New("equipment written off")
+	ErrUnknownWriteOffReason   = errors.New("unknown write-off reason")
+	ErrWriteOffDecided         = errors.New("write-off already decided")
+	ErrEquipmentInRepair       = errors.New("equipment in repair")
+	ErrUnknownRepairStatus     = errors.New("unknown repair status")
+	ErrIllegalRepairStatus     = errors.New("illegal repair status change")
+	ErrRepairOrderChanged      = errors.New("repair order changed")
+	ErrEquipmentReserved       = errors.New("equipment reserved for another destination")
+	ErrNotInStorage            = errors.New("equipment not in storage")
+	ErrReservationClosed       = errors.New("reservation closed")
+	ErrUnknownPermission       = errors.New("unknown permission")
+	ErrPermissionDenied        = errors.New("permission denied")
+	ErrOutOfScope              = errors.New("out of department scope")
+	ErrInvalidInterval         = errors.New("interval must be a positive number of seconds")
 )
 
 const (
@@ -157,6 +196,8 @@ const (
 	MsgFailedToSetBodyHTML         = "failed to set body html"
 	MsgFailedToSetMailClient       = "failed to set mail client"
 	MsgFailedToSendMail            = "failed to send mail"
+	MsgFailedToExport              = "failed to export"
+	MsgFailedToPublish             = "failed to publish"
 )
 
 func Info(msg string) {
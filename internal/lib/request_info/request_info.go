@@ -0,0 +1,42 @@
+package request_info
+
+import (
+	"context"
+
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
+)
+
+// Keys of the values the handlers set on the gin context, which is passed
+// down to the services and repositories as their context.
+const (
+	UserIDKey    = "userId"
+	UserRoleKey  = "userRole"
+	RequestIDKey = "requestId"
+	ClientIPKey  = "clientIp"
+)
+
+// HeaderRequestID carries the request id from the client and back to it.
+const HeaderRequestID = "X-Request-ID"
+
+// UserID returns the id of the user making the request, 0 outside of one.
+func UserID(ctx context.Context) int64 {
+	id, _ := ctx.Value(UserIDKey).(int64)
+	return id
+}
+
+// UserRole returns the role of the user making the request, an invalid
+// role outside of one.
+func UserRole(ctx context.Context) role.Role {
+	r, _ := ctx.Value(UserRoleKey).(role.Role)
+	return r
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDKey).(string)
+	return id
+}
+
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(ClientIPKey).(string)
+	return ip
+}
@@ -0,0 +1,170 @@
+package role
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+)
+
+type Permission string
+
+const (
+	UsersManage       Permission = "users.manage"
+	DirectoryView     Permission = "directory.view"
+	DirectoryManage   Permission = "directory.manage"
+	CompaniesManage   Permission = "companies.manage"
+	EquipmentView     Permission = "equipment.view"
+	EquipmentManage   Permission = "equipment.manage"
+	EquipmentMerge    Permission = "equipment.merge"
+	EquipmentMove     Permission = "equipment.move"
+	EquipmentUndoAny  Permission = "equipment.undo_any"
+	EquipmentWriteOff Permission = "equipment.writeoff"
+	WriteOffApprove   Permission = "writeoff.approve"
+	StocktakingManage Permission = "stocktaking.manage"
+	StockManage       Permission = "stock.manage"
+	ReportsView       Permission = "reports.view"
+	AuditView         Permission = "audit.view"
+	AllDepartments    Permission = "departments.all"
+)
+
+var allPermissions = []Permission{
+	UsersManage,
+	DirectoryView,
+	DirectoryManage,
+	CompaniesManage,
+	EquipmentView,
+	EquipmentManage,
+	EquipmentMerge,
+	EquipmentMove,
+	EquipmentUndoAny,
+	EquipmentWriteOff,
+	WriteOffApprove,
+	StocktakingManage,
+	StockManage,
+	ReportsView,
+	AuditView,
+	AllDepartments,
+}
+
+// permissions maps a role to what it is allowed to do. The root role is
+// not in the table, it has every permission and can not be locked out.
+// A role without AllDepartments sees and moves only the equipment of the
+// department of its user's employee.
+var permissions = map[Role][]Permission{
+	AdminRole: allPermissions,
+	GoverningRole: {
+		DirectoryView,
+		DirectoryManage,
+		EquipmentView,
+		EquipmentManage,
+		EquipmentMove,
+		EquipmentWriteOff,
+		WriteOffApprove,
+		StocktakingManage,
+		StockManage,
+		ReportsView,
+	},
+	EmployeeRole: {
+		DirectoryView,
+		EquipmentView,
+		EquipmentManage,
+		EquipmentMove,
+		EquipmentWriteOff,
+		StocktakingManage,
+		ReportsView,
+	},
+	UserRole: {
+		DirectoryView,
+		EquipmentView,
+	},
+}
+
+func (p Permission) IsValid() bool {
+	return slices.Contains(allPermissions, p)
+}
+
+// Can reports whether the role has the permission.
+func (r Role) Can(p Permission) bool {
+	if !r.IsValid() {
+		return false
+	}
+
+	if r == RootRole {
+		return true
+	}
+
+	return slices.Contains(permissions[r], p)
+}
+
+// Permissions returns the permissions of the role.
+func (r Role) Permissions() []Permission {
+	if r == RootRole {
+		return slices.Clone(allPermissions)
+	}
+
+	return slices.Clone(permissions[r])
+}
+
+// WithPermission returns the roles having the permission.
+func WithPermission(p Permission) []Role {
+	roles := make([]Role, 0, UserRole)
+	for r := RootRole; r <= UserRole; r++ {
+		if r.Can(p) {
+			roles = append(roles, r)
+		}
+	}
+
+	return roles
+}
+
+// LoadPermissions replaces the table with the one from a json file mapping
+// role ids to permission names, e.g. {"4": ["equipment.view"]}. A role
+// missing from the file keeps its default permissions, an empty path keeps
+// the defaults. It is meant to be called once at startup.
+func LoadPermissions(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var table map[string][]Permission
+	if err := json.Unmarshal(data, &table); err != nil {
+		return err
+	}
+
+	loaded := make(map[Role][]Permission, len(permissions))
+	for r, list := range permissions {
+		loaded[r] = list
+	}
+
+	for key, list := range table {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return err
+		}
+
+		r := Role(id)
+		if !r.IsValid() || r == RootRole {
+			return fmt.Errorf("%w: %s", logger.ErrInvalidRole, key)
+		}
+
+		for _, p := range list {
+			if !p.IsValid() {
+				return fmt.Errorf("%w: %s", logger.ErrUnknownPermission, p)
+			}
+		}
+
+		loaded[r] = list
+	}
+
+	permissions = loaded
+	return nil
+}
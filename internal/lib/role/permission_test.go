@@ -0,0 +1,246 @@
+package role
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+)
+
+func TestRole_Can(t *testing.T) {
+	type args struct {
+		p Permission
+	}
+	tests := []struct {
+		name string
+		r    Role
+		args args
+		want bool
+	}{
+		{
+			name: "root can everything",
+			r:    RootRole,
+			args: args{
+				p: UsersManage,
+			},
+			want: true,
+		},
+		{
+			name: "admin manages users",
+			r:    AdminRole,
+			args: args{
+				p: UsersManage,
+			},
+			want: true,
+		},
+		{
+			name: "governing approves write off",
+			r:    GoverningRole,
+			args: args{
+				p: WriteOffApprove,
+			},
+			want: true,
+		},
+		{
+			name: "governing does not see all departments",
+			r:    GoverningRole,
+			args: args{
+				p: AllDepartments,
+			},
+			want: false,
+		},
+		{
+			name: "employee moves equipment",
+			r:    EmployeeRole,
+			args: args{
+				p: EquipmentMove,
+			},
+			want: true,
+		},
+		{
+			name: "employee does not approve write off",
+			r:    EmployeeRole,
+			args: args{
+				p: WriteOffApprove,
+			},
+			want: false,
+		},
+		{
+			name: "user views equipment",
+			r:    UserRole,
+			args: args{
+				p: EquipmentView,
+			},
+			want: true,
+		},
+		{
+			name: "user does not move equipment",
+			r:    UserRole,
+			args: args{
+				p: EquipmentMove,
+			},
+			want: false,
+		},
+		{
+			name: "invalid role can nothing",
+			r:    Role(0),
+			args: args{
+				p: EquipmentView,
+			},
+			want: false,
+		},
+		{
+			name: "unknown permission",
+			r:    AdminRole,
+			args: args{
+				p: Permission("unknown"),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Can(tt.args.p); got != tt.want {
+				t.Errorf("Can() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPermission(t *testing.T) {
+	type args struct {
+		p Permission
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Role
+	}{
+		{
+			name: "roles approving write off",
+			args: args{
+				p: WriteOffApprove,
+			},
+			want: []Role{RootRole, AdminRole, GoverningRole},
+		},
+		{
+			name: "roles viewing equipment",
+			args: args{
+				p: EquipmentView,
+			},
+			want: []Role{RootRole, AdminRole, GoverningRole, EmployeeRole, UserRole},
+		},
+		{
+			name: "only root has unknown permission",
+			args: args{
+				p: Permission("unknown"),
+			},
+			want: []Role{RootRole},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithPermission(tt.args.p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPermissions(t *testing.T) {
+	defaults := permissions
+	t.Cleanup(func() {
+		permissions = defaults
+	})
+
+	writeTable := func(t *testing.T, table string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "permissions.json")
+		if err := os.WriteFile(path, []byte(table), 0o600); err != nil {
+			t.Fatalf("failed to write test permissions: %v", err)
+		}
+
+		return path
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		role    Role
+		want    []Permission
+		wantErr error
+	}{
+		{
+			name: "keep defaults without a file",
+			args: args{
+				path: "",
+			},
+			role: UserRole,
+			want: []Permission{DirectoryView, EquipmentView},
+		},
+		{
+			name: "replace permissions of a role",
+			args: args{
+				path: writeTable(t, `{"5": ["equipment.view", "reports.view"]}`),
+			},
+			role: UserRole,
+			want: []Permission{EquipmentView, ReportsView},
+		},
+		{
+			name: "keep permissions of a role missing from the file",
+			args: args{
+				path: writeTable(t, `{"5": ["equipment.view"]}`),
+			},
+			role: EmployeeRole,
+			want: defaults[EmployeeRole],
+		},
+		{
+			name: "refuse unknown permission",
+			args: args{
+				path: writeTable(t, `{"5": ["equipment.fly"]}`),
+			},
+			wantErr: logger.ErrUnknownPermission,
+		},
+		{
+			name: "refuse root role",
+			args: args{
+				path: writeTable(t, `{"1": ["equipment.view"]}`),
+			},
+			wantErr: logger.ErrInvalidRole,
+		},
+		{
+			name: "refuse unknown role",
+			args: args{
+				path: writeTable(t, `{"9": ["equipment.view"]}`),
+			},
+			wantErr: logger.ErrInvalidRole,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions = defaults
+			err := LoadPermissions(tt.args.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("LoadPermissions() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(permissions, defaults) {
+					t.Errorf("LoadPermissions() changed the table on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadPermissions() error = %v", err)
+			}
+			if got := tt.role.Permissions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Permissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,14 +25,6 @@ func (r Role) IsValid() bool {
 	return RootRole <= r && r <= UserRole
 }
 
-func (r Role) CanAccess(required Role) bool {
-	if !r.IsValid() {
-		return false
-	}
-
-	return r <= required
-}
-
 func (r Role) String() string {
 	if !r.IsValid() {
 		return UndefinedRole
@@ -42,8 +34,9 @@ func (r Role) String() string {
 }
 
 type FullRole struct {
-	ID   int    `json:"id"`
-	Role string `json:"role"`
+	ID          int          `json:"id"`
+	Role        string       `json:"role"`
+	Permissions []Permission `json:"permissions"`
 }
 
 func AllRole() []*FullRole {
@@ -53,7 +46,7 @@ func AllRole() []*FullRole {
 			continue
 		}
 
-		r = append(r, &FullRole{i, role})
+		r = append(r, &FullRole{i, role, Role(i).Permissions()})
 	}
 	return r
 }
@@ -0,0 +1,65 @@
+package serial
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Steps of a profile serial rule, applied in the order given by the rule.
+const (
+	Upper             = "upper"
+	StripSpaces       = "strip_spaces"
+	StripDashes       = "strip_dashes"
+	StripLeadingZeros = "strip_leading_zeros"
+)
+
+// Canonical is the rule used to find possible duplicates regardless of the
+// profile, it must match the canonical_serial_number column of equipments.
+var Canonical = []string{StripSpaces, StripDashes, Upper, StripLeadingZeros}
+
+func IsValidRule(rule []string) bool {
+	seen := make(map[string]bool, len(rule))
+	for _, step := range rule {
+		switch step {
+		case Upper, StripSpaces, StripDashes, StripLeadingZeros:
+		default:
+			return false
+		}
+		if seen[step] {
+			return false
+		}
+		seen[step] = true
+	}
+
+	return true
+}
+
+// Normalize trims the serial number and applies the steps of the rule,
+// unknown steps are ignored.
+func Normalize(serialNumber string, rule []string) string {
+	sn := strings.TrimSpace(serialNumber)
+	for _, step := range rule {
+		switch step {
+		case Upper:
+			sn = strings.ToUpper(sn)
+		case StripSpaces:
+			sn = strings.Map(func(r rune) rune {
+				if unicode.IsSpace(r) {
+					return -1
+				}
+				return r
+			}, sn)
+		case StripDashes:
+			sn = strings.ReplaceAll(sn, "-", "")
+		case StripLeadingZeros:
+			// a serial of zeros only keeps one
+			if trimmed := strings.TrimLeft(sn, "0"); trimmed != "" {
+				sn = trimmed
+			} else if sn != "" {
+				sn = "0"
+			}
+		}
+	}
+
+	return sn
+}
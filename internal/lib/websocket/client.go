@@ -1,6 +1,8 @@
 package websocket
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/websocket"
@@ -8,6 +10,11 @@ import (
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 )
 
+const (
+	actionSubscribe   = "subscribe"
+	actionUnsubscribe = "unsubscribe"
+)
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -18,12 +25,22 @@ var upgrader = websocket.Upgrader{
 }
 
 type Client struct {
-	conn     *websocket.Conn
-	hub      *Hub
-	location string
+	conn      *websocket.Conn
+	hub       *Hub
+	send      chan []byte
+	authorize func(topic string) bool
 }
 
-func NewClient(w http.ResponseWriter, r *http.Request, hub *Hub) {
+// request is a message from the client, e.g.
+// {"action":"subscribe","topics":["department:1","equipments"]}.
+type request struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+// NewClient upgrades the connection and serves the client; authorize tells
+// which topics the client may subscribe to.
+func NewClient(w http.ResponseWriter, r *http.Request, hub *Hub, authorize func(topic string) bool) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		logger.Error("failed websocket upgrade", err)
@@ -31,12 +48,26 @@ func NewClient(w http.ResponseWriter, r *http.Request, hub *Hub) {
 	}
 
 	client := &Client{
-		conn: conn,
-		hub:  hub,
+		conn:      conn,
+		hub:       hub,
+		send:      make(chan []byte, 16),
+		authorize: authorize,
 	}
 
 	client.hub.register <- client
 
+	go func() {
+		// closing the connection also stops the read loop below
+		defer client.conn.Close()
+
+		for msg := range client.send {
+			if err := client.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				logger.Error("failed write client message", err)
+				return
+			}
+		}
+	}()
+
 	go func() {
 		defer func() {
 			client.hub.unregister <- client
@@ -49,7 +80,43 @@ func NewClient(w http.ResponseWriter, r *http.Request, hub *Hub) {
 				logger.Error("failed read client message", err)
 				break
 			}
-			client.location = string(msg)
+
+			var req request
+			if err := json.Unmarshal(msg, &req); err != nil {
+				logger.Warn(fmt.Sprintf("invalid client message: %v", err))
+				continue
+			}
+
+			switch req.Action {
+			case actionSubscribe:
+				client.hub.subscribe <- &subscription{
+					client:    client,
+					topics:    client.allowed(req.Topics),
+					subscribe: true,
+				}
+			case actionUnsubscribe:
+				client.hub.subscribe <- &subscription{
+					client:    client,
+					topics:    req.Topics,
+					subscribe: false,
+				}
+			default:
+				logger.Warn(fmt.Sprintf("unknown client action %q", req.Action))
+			}
 		}
 	}()
 }
+
+// allowed drops the topics the client may not subscribe to.
+func (c *Client) allowed(topics []string) []string {
+	list := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		if !c.authorize(topic) {
+			logger.Warn(fmt.Sprintf("client %v denied topic %q", c.conn.RemoteAddr(), topic))
+			continue
+		}
+		list = append(list, topic)
+	}
+
+	return list
+}
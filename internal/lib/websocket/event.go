@@ -0,0 +1,76 @@
+package websocket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	EquipmentCreated = "equipment.created"
+	EquipmentMoved   = "equipment.moved"
+	EquipmentDeleted = "equipment.deleted"
+	UserChanged      = "user.changed"
+	StockLow         = "stock.low"
+)
+
+// List topics receive every event about a kind of entity, TopicStorage the
+// moves in and out of central storage.
+const (
+	TopicEquipments = "equipments"
+	TopicUsers      = "users"
+	TopicStorage    = "storage"
+	TopicStock      = "stock"
+)
+
+// Event is what the hub pushes to the clients subscribed to any of Topics.
+type Event struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics"`
+	Data   any      `json:"data,omitempty"`
+}
+
+// Kinds of the topics of a single entity, e.g. department:1.
+const (
+	KindDepartment = "department"
+	KindEmployee   = "employee"
+	KindContract   = "contract"
+	KindEquipment  = "equipment"
+	KindUser       = "user"
+)
+
+func DepartmentTopic(id int64) string {
+	return fmt.Sprintf("%s:%d", KindDepartment, id)
+}
+
+func EmployeeTopic(id int64) string {
+	return fmt.Sprintf("%s:%d", KindEmployee, id)
+}
+
+func ContractTopic(id int64) string {
+	return fmt.Sprintf("%s:%d", KindContract, id)
+}
+
+func EquipmentTopic(id int64) string {
+	return fmt.Sprintf("%s:%d", KindEquipment, id)
+}
+
+func UserTopic(id int64) string {
+	return fmt.Sprintf("%s:%d", KindUser, id)
+}
+
+// ParseTopic splits a topic into its kind and entity id; a list topic is
+// its own kind with id 0.
+func ParseTopic(topic string) (string, int64) {
+	kind, rawID, ok := strings.Cut(topic, ":")
+	if !ok {
+		return topic, 0
+	}
+
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return kind, 0
+	}
+
+	return kind, id
+}
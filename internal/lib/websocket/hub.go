@@ -1,24 +1,33 @@
 package websocket
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 )
 
+type subscription struct {
+	client    *Client
+	topics    []string
+	subscribe bool
+}
+
 type Hub struct {
-	clients    map[*Client]struct{}
+	clients    map[*Client]map[string]struct{}
 	register   chan *Client
 	unregister chan *Client
-	broadcast  chan []byte
+	subscribe  chan *subscription
+	broadcast  chan *Event
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		clients:    make(map[*Client]struct{}),
+		clients:    make(map[*Client]map[string]struct{}),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
-		broadcast:  make(chan []byte),
+		subscribe:  make(chan *subscription),
+		broadcast:  make(chan *Event, 256),
 	}
 }
 
@@ -26,13 +35,71 @@ func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.register:
-			h.clients[client] = struct{}{}
+			h.clients[client] = make(map[string]struct{})
 			logger.Info(fmt.Sprintf("register new client %v", client.conn.RemoteAddr()))
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
+				h.remove(client)
 				logger.Info(fmt.Sprintf("unregister client %v", client.conn.RemoteAddr()))
 			}
+		case sub := <-h.subscribe:
+			topics, ok := h.clients[sub.client]
+			if !ok {
+				continue
+			}
+			for _, topic := range sub.topics {
+				if sub.subscribe {
+					topics[topic] = struct{}{}
+				} else {
+					delete(topics, topic)
+				}
+			}
+		case event := <-h.broadcast:
+			msg, err := json.Marshal(event)
+			if err != nil {
+				logger.Error(logger.MsgFailedToMarshal, err)
+				continue
+			}
+			for client, topics := range h.clients {
+				if !subscribed(topics, event.Topics) {
+					continue
+				}
+				select {
+				case client.send <- msg:
+				default:
+					// the client does not keep up, drop it rather than block the hub
+					h.remove(client)
+				}
+			}
+		}
+	}
+}
+
+// Publish queues an event for the subscribers of any of the topics. It never
+// blocks the caller: a nil hub or a full queue drops the event.
+func (h *Hub) Publish(eventType string, topics []string, data any) {
+	if h == nil {
+		return
+	}
+
+	select {
+	case h.broadcast <- &Event{Type: eventType, Topics: topics, Data: data}:
+	default:
+		logger.Warn(fmt.Sprintf("websocket event %s dropped", eventType))
+	}
+}
+
+func (h *Hub) remove(client *Client) {
+	delete(h.clients, client)
+	close(client.send)
+}
+
+func subscribed(topics map[string]struct{}, eventTopics []string) bool {
+	for _, topic := range eventTopics {
+		if _, ok := topics[topic]; ok {
+			return true
 		}
 	}
+
+	return false
 }
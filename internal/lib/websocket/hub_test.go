@@ -0,0 +1,189 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/env"
+)
+
+func TestParseTopic(t *testing.T) {
+	type args struct {
+		topic string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantKind string
+		wantID   int64
+	}{
+		{
+			name: "entity topic",
+			args: args{
+				topic: DepartmentTopic(7),
+			},
+			wantKind: KindDepartment,
+			wantID:   7,
+		},
+		{
+			name: "list topic",
+			args: args{
+				topic: TopicEquipments,
+			},
+			wantKind: TopicEquipments,
+			wantID:   0,
+		},
+		{
+			name: "entity topic with invalid id",
+			args: args{
+				topic: "employee:abc",
+			},
+			wantKind: KindEmployee,
+			wantID:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, id := ParseTopic(tt.args.topic)
+			if kind != tt.wantKind {
+				t.Errorf("ParseTopic() kind = %v, want %v", kind, tt.wantKind)
+			}
+			if id != tt.wantID {
+				t.Errorf("ParseTopic() id = %v, want %v", id, tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_subscribed(t *testing.T) {
+	topics := map[string]struct{}{
+		DepartmentTopic(1): {},
+		TopicEquipments:    {},
+	}
+
+	type args struct {
+		eventTopics []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "event of a subscribed entity",
+			args: args{
+				eventTopics: []string{EquipmentTopic(5), DepartmentTopic(1)},
+			},
+			want: true,
+		},
+		{
+			name: "event of a subscribed list",
+			args: args{
+				eventTopics: []string{TopicEquipments},
+			},
+			want: true,
+		},
+		{
+			name: "event of another department",
+			args: args{
+				eventTopics: []string{EquipmentTopic(5), DepartmentTopic(2)},
+			},
+			want: false,
+		},
+		{
+			name: "event without topics",
+			args: args{
+				eventTopics: nil,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subscribed(topics, tt.args.eventTopics); got != tt.want {
+				t.Errorf("subscribed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHub_Publish(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	// the client may follow department 1 only
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NewClient(w, r, hub, func(topic string) bool {
+			return topic == DepartmentTopic(1)
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(server.URL, "http"),
+		http.Header{"Origin": []string{env.GetClientUrl()}},
+	)
+	if err != nil {
+		t.Fatalf("failed to dial test hub: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	if err := conn.WriteJSON(&request{
+		Action: actionSubscribe,
+		Topics: []string{DepartmentTopic(1), DepartmentTopic(2)},
+	}); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	received := make(chan *Event, 1)
+	go func() {
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				close(received)
+				return
+			}
+
+			event := new(Event)
+			if err := json.Unmarshal(msg, event); err != nil {
+				continue
+			}
+
+			// only the first event is checked
+			select {
+			case received <- event:
+			default:
+			}
+		}
+	}()
+
+	// the subscription reaches the hub asynchronously, publish until the
+	// allowed event arrives; the denied one must never come first
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case <-tick.C:
+			hub.Publish(EquipmentDeleted, []string{DepartmentTopic(2)}, nil)
+			hub.Publish(EquipmentMoved, []string{EquipmentTopic(5), DepartmentTopic(1)}, nil)
+		case event, ok := <-received:
+			if !ok {
+				t.Fatal("connection closed before an event arrived")
+			}
+			if event.Type != EquipmentMoved {
+				t.Fatalf("Publish() delivered %s of topics %v", event.Type, event.Topics)
+			}
+			return
+		case <-timeout:
+			t.Fatal("Publish() delivered nothing")
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EntityUser         = "user"
+	EntityEmployee     = "employee"
+	EntityDepartment   = "department"
+	EntityCategory     = "category"
+	EntityProfile      = "profile"
+	EntityEquipment    = "equipment"
+	EntityContract     = "contract"
+	EntityCompany      = "company"
+	EntityStocktaking  = "stocktaking"
+	EntityWriteOff     = "write_off"
+	EntityRepairOrder  = "repair_order"
+	EntityReservation  = "reservation"
+	EntityStockMinimum = "stock_minimum"
+)
+
+const (
+	AuditCreate        = "create"
+	AuditUpdate        = "update"
+	AuditDelete        = "delete"
+	AuditRestore       = "restore"
+	AuditSet           = "set"
+	AuditMove          = "move"
+	AuditUndoMove      = "undo_move"
+	AuditMerge         = "merge"
+	AuditSetDepartment = "set_department"
+	AuditSetPassword   = "set_password"
+	AuditResetPassword = "reset_password"
+	AuditSetEnabled    = "set_enabled"
+	AuditScan          = "scan"
+	AuditClose         = "close"
+	AuditApply         = "apply"
+	AuditApprove       = "approve"
+	AuditReject        = "reject"
+	AuditReturn        = "return"
+	AuditCancel        = "cancel"
+)
+
+// AuditChange is one field of an audited entity, a side where the field is
+// missing is null.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntry is one mutation, Diff maps the changed fields to AuditChange.
+type AuditEntry struct {
+	ID        int64           `json:"id,omitempty"`
+	User      *User           `json:"user,omitempty"`
+	Entity    string          `json:"entity,omitempty"`
+	EntityID  int64           `json:"entity_id,omitempty"`
+	Action    string          `json:"action,omitempty"`
+	Diff      json.RawMessage `json:"diff,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	ClientIP  string          `json:"client_ip,omitempty"`
+	CreatedAt *time.Time      `json:"created_at,omitempty"`
+}
@@ -3,9 +3,19 @@ package model
 import "time"
 
 type Equipment struct {
-	ID           int64      `json:"id,omitempty"`
-	Company      *Company   `json:"company,omitempty"`
-	Profile      *Profile   `json:"profile,omitempty"`
-	SerialNumber string     `json:"serial_number,omitempty"`
-	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	ID           int64          `json:"id,omitempty"`
+	Company      *Company       `json:"company,omitempty"`
+	Profile      *Profile       `json:"profile,omitempty"`
+	SerialNumber string         `json:"serial_number,omitempty"`
+	DeletedAt    *time.Time     `json:"deleted_at,omitempty"`
+	WrittenOffAt *time.Time     `json:"written_off_at,omitempty"`
+	RepairOrders []*RepairOrder `json:"repair_orders,omitempty"`
+	Reservation  *Reservation   `json:"reservation,omitempty"`
+}
+
+// SerialNumberDuplicate is a group of equipment whose serial numbers are the
+// same after the canonical normalisation.
+type SerialNumberDuplicate struct {
+	NormalizedSerialNumber string       `json:"normalized_serial_number,omitempty"`
+	Equipments             []*Equipment `json:"equipments,omitempty"`
 }
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type EquipmentMerge struct {
+	ID        int64      `json:"id,omitempty"`
+	Equipment *Equipment `json:"equipment,omitempty"`
+	Duplicate *Equipment `json:"duplicate,omitempty"`
+	MergedBy  *User      `json:"merged_by,omitempty"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
+	Locations int64      `json:"locations,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+const (
+	EventEquipmentCreated      = "equipment.created"
+	EventEquipmentMoved        = "equipment.moved"
+	EventEquipmentMoveReverted = "equipment.move_reverted"
+	EventEquipmentMerged       = "equipment.merged"
+	EventEquipmentWrittenOff   = "equipment.written_off"
+	EventUserCreated           = "user.created"
+)
+
+// EventVersion is raised when a field of Event or EventPayload changes its
+// meaning or goes away, new fields keep the version.
+const EventVersion = 1
+
+// Event is the envelope of every published event.
+type Event struct {
+	Version    int           `json:"version"`
+	ID         string        `json:"event_id"`
+	Type       string        `json:"event_type"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	UserID     int64         `json:"user_id"`
+	EntityType string        `json:"entity_type"`
+	EntityID   int64         `json:"entity_id"`
+	Payload    *EventPayload `json:"payload"`
+}
+
+// EventPayload holds the dimensions of the event, a field that does not
+// apply to the event is null.
+type EventPayload struct {
+	LocationID       *int64  `json:"location_id"`
+	MoveCode         *string `json:"move_code"`
+	FromDepartmentID *int64  `json:"from_department_id"`
+	FromEmployeeID   *int64  `json:"from_employee_id"`
+	FromContractID   *int64  `json:"from_contract_id"`
+	ToDepartmentID   *int64  `json:"to_department_id"`
+	ToEmployeeID     *int64  `json:"to_employee_id"`
+	ToContractID     *int64  `json:"to_contract_id"`
+	ProfileID        *int64  `json:"profile_id"`
+	CategoryID       *int64  `json:"category_id"`
+	CompanyID        *int64  `json:"company_id"`
+	DuplicateID      *int64  `json:"duplicate_id"`
+}
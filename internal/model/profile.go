@@ -3,8 +3,9 @@ package model
 import "time"
 
 type Profile struct {
-	ID        int64      `json:"id,omitempty"`
-	Title     string     `json:"title,omitempty"`
-	Category  *Category  `json:"category,omitempty"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID         int64      `json:"id,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	Category   *Category  `json:"category,omitempty"`
+	SerialRule []string   `json:"serial_rule,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	RepairSent         = "sent"
+	RepairDiagnosed    = "diagnosed"
+	RepairRepaired     = "repaired"
+	RepairUnrepairable = "unrepairable"
+	RepairReturned     = "returned"
+)
+
+// Move codes of the locations rows written when equipment is sent to repair
+// and returned from it.
+const (
+	SentToRepair       = "SentToRepair"
+	ReturnedFromRepair = "ReturnedFromRepair"
+)
+
+// RepairOrder tracks equipment at a vendor or at the internal repair bench,
+// an empty Vendor means the internal bench. The from side of SendLocation is
+// the place the equipment goes back to.
+type RepairOrder struct {
+	ID             int64      `json:"id,omitempty"`
+	Equipment      *Equipment `json:"equipment,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	Vendor         string     `json:"vendor,omitempty"`
+	Cost           string     `json:"cost,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	SentBy         *User      `json:"sent_by,omitempty"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	SendLocation   *Location  `json:"send_location,omitempty"`
+	ReturnedBy     *User      `json:"returned_by,omitempty"`
+	ReturnedAt     *time.Time `json:"returned_at,omitempty"`
+	ReturnLocation *Location  `json:"return_location,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
@@ -1,7 +1,7 @@
 package model
 
 type Replace struct {
-	ID           int64 `json:"id,omitempty"`
-	TransferFrom int64 `json:"transfer_from,omitempty"`
-	TransferTo   int64 `json:"transfer_to,omitempty"`
+	ID      int64     `json:"id,omitempty"`
+	MoveIn  *Location `json:"move_in,omitempty"`
+	MoveOut *Location `json:"move_out,omitempty"`
 }
@@ -1,14 +1,28 @@
 package model
 
-type Report struct {
-	Categories     []*Category                     `json:"categories"`
-	Departments    []*Department                   `json:"departments"`
-	Leftover       map[int64][]*Location           `json:"leftover"`
-	Total          map[int64][]*Location           `json:"total"`
-	FromStorage    map[int64][]*Location           `json:"from_storage"`
-	ToStorage      map[int64][]*Location           `json:"to_storage"`
-	FromContract   map[int64][]*Location           `json:"from_contract"`
-	ToContract     map[int64][]*Location           `json:"to_contract"`
-	FromDepartment map[int64]map[int64][]*Location `json:"from_department"`
-	ToDepartment   map[int64]map[int64][]*Location `json:"to_department"`
+import "time"
+
+type DepartmentBalance struct {
+	Department *Department             `json:"department"`
+	DateFrom   *time.Time              `json:"date_from"`
+	DateTo     *time.Time              `json:"date_to"`
+	Rows       []*DepartmentBalanceRow `json:"rows"`
+	Total      *DepartmentBalanceRow   `json:"total"`
+}
+
+type DepartmentBalanceRow struct {
+	Category       *Category `json:"category,omitempty"`
+	Opening        int64     `json:"opening"`
+	FromStorage    int64     `json:"from_storage"`
+	FromContract   int64     `json:"from_contract"`
+	FromDepartment int64     `json:"from_department"`
+	FromEmployee   int64     `json:"from_employee"`
+	FromRepair     int64     `json:"from_repair"`
+	ToStorage      int64     `json:"to_storage"`
+	ToContract     int64     `json:"to_contract"`
+	ToDepartment   int64     `json:"to_department"`
+	ToEmployee     int64     `json:"to_employee"`
+	ToRepair       int64     `json:"to_repair"`
+	WrittenOff     int64     `json:"written_off"`
+	Closing        int64     `json:"closing"`
 }
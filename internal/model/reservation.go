@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	ReservationActive    = "active"
+	ReservationConsumed  = "consumed"
+	ReservationCancelled = "cancelled"
+	ReservationExpired   = "expired"
+)
+
+// Reservation holds storage equipment for a future destination until
+// ExpiresAt; Location is the move that consumed it.
+type Reservation struct {
+	ID           int64       `json:"id,omitempty"`
+	Equipment    *Equipment  `json:"equipment,omitempty"`
+	ToDepartment *Department `json:"to_department,omitempty"`
+	ToEmployee   *Employee   `json:"to_employee,omitempty"`
+	ToContract   *Contract   `json:"to_contract,omitempty"`
+	ExpiresAt    *time.Time  `json:"expires_at,omitempty"`
+	Comment      string      `json:"comment,omitempty"`
+	Status       string      `json:"status,omitempty"`
+	ReservedBy   *User       `json:"reserved_by,omitempty"`
+	ReservedAt   *time.Time  `json:"reserved_at,omitempty"`
+	ClosedBy     *User       `json:"closed_by,omitempty"`
+	ClosedAt     *time.Time  `json:"closed_at,omitempty"`
+	Location     *Location   `json:"location,omitempty"`
+}
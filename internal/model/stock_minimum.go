@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// StockMinimum is the least quantity of a profile to keep in a department,
+// or in central storage when Department is nil. Quantity is the current
+// holding and LowSince is set while it stays below MinQuantity.
+type StockMinimum struct {
+	ID          int64       `json:"id,omitempty"`
+	Profile     *Profile    `json:"profile,omitempty"`
+	Department  *Department `json:"department,omitempty"`
+	MinQuantity int32       `json:"min_quantity,omitempty"`
+	Quantity    int64       `json:"quantity"`
+	LowSince    *time.Time  `json:"low_since,omitempty"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+const (
+	StocktakingMissing        = "missing"
+	StocktakingUnexpected     = "unexpected"
+	StocktakingFoundElsewhere = "found_elsewhere"
+)
+
+type Stocktaking struct {
+	ID         int64       `json:"id,omitempty"`
+	Department *Department `json:"department,omitempty"`
+	Employee   *Employee   `json:"employee,omitempty"`
+	OpenedBy   *User       `json:"opened_by,omitempty"`
+	OpenedAt   *time.Time  `json:"opened_at,omitempty"`
+	ClosedBy   *User       `json:"closed_by,omitempty"`
+	ClosedAt   *time.Time  `json:"closed_at,omitempty"`
+	AppliedAt  *time.Time  `json:"applied_at,omitempty"`
+	Expected   int64       `json:"expected"`
+	Scanned    int64       `json:"scanned"`
+}
+
+// StocktakingDiscrepancy is an item that differs between the snapshot and
+// the scans; Department, Employee and Contract show its current location.
+type StocktakingDiscrepancy struct {
+	Status       string      `json:"status"`
+	Equipment    *Equipment  `json:"equipment,omitempty"`
+	SerialNumber string      `json:"serial_number"`
+	Department   *Department `json:"department,omitempty"`
+	Employee     *Employee   `json:"employee,omitempty"`
+	Contract     *Contract   `json:"contract,omitempty"`
+}
+
+type StocktakingReport struct {
+	Stocktaking    *Stocktaking              `json:"stocktaking"`
+	Missing        []*StocktakingDiscrepancy `json:"missing"`
+	Unexpected     []*StocktakingDiscrepancy `json:"unexpected"`
+	FoundElsewhere []*StocktakingDiscrepancy `json:"found_elsewhere"`
+}
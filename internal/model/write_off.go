@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	WriteOffPending  = "pending"
+	WriteOffApproved = "approved"
+	WriteOffRejected = "rejected"
+)
+
+const (
+	WriteOffBroken   = "broken"
+	WriteOffLost     = "lost"
+	WriteOffStolen   = "stolen"
+	WriteOffObsolete = "obsolete"
+)
+
+// WrittenOff is the move code of the final move of written off equipment.
+const WrittenOff = "WrittenOff"
+
+// WriteOff is a request to take equipment out of service; Location is the
+// final move recorded on approval.
+type WriteOff struct {
+	ID          int64      `json:"id,omitempty"`
+	Equipment   *Equipment `json:"equipment,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	RequestedBy *User      `json:"requested_by,omitempty"`
+	RequestedAt *time.Time `json:"requested_at,omitempty"`
+	DecidedBy   *User      `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Location    *Location  `json:"location,omitempty"`
+}
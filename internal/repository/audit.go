@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type AuditRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewAuditRepository(postgresDB *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{postgresDB: postgresDB}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *queries.CreateAuditLogParams) error {
+	if err := queries.New(r.postgresDB).CreateAuditLog(ctx, entry); err != nil {
+		return logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter *queries.ListAuditLogParams) ([]*model.AuditEntry, int64, error) {
+	req, err := queries.New(r.postgresDB).ListAuditLog(ctx, filter)
+	if err != nil {
+		return nil, 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if len(req) < 1 {
+		return []*model.AuditEntry{}, 0, nil
+	}
+
+	list := make([]*model.AuditEntry, len(req))
+	for i, item := range req {
+		entry := &model.AuditEntry{
+			ID:        item.ID,
+			Entity:    item.Entity,
+			EntityID:  item.EntityID,
+			Action:    item.Action,
+			Diff:      item.Diff,
+			RequestID: validString(item.RequestID),
+			CreatedAt: validTime(item.CreatedAt),
+		}
+		if item.UserID.Valid {
+			entry.User = &model.User{
+				ID:       item.UserID.Int64,
+				Username: validString(item.Username),
+			}
+		}
+		if item.ClientIp != nil {
+			entry.ClientIP = item.ClientIp.String()
+		}
+		list[i] = entry
+	}
+
+	return list, req[0].Total, nil
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func truncateAuditLog(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE audit_log
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate audit log: %v", err)
+	}
+}
+
+func addTestAuditEntry(t *testing.T, testDB *pgxpool.Pool, userID int64, entity, action string, entityID int64, requestID string) {
+	t.Helper()
+	ip := netip.MustParseAddr("192.168.0.1")
+	if err := NewAuditRepository(testDB).Create(t.Context(), &queries.CreateAuditLogParams{
+		UserID:    pgtype.Int8{Int64: userID, Valid: userID != 0},
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		Diff:      []byte(`{"title":{"before":"old","after":"new"}}`),
+		RequestID: pgtype.Text{String: requestID, Valid: requestID != ""},
+		ClientIp:  &ip,
+	}); err != nil {
+		t.Fatalf("failed to insert test audit entry: %v", err)
+	}
+}
+
+func TestNewAuditRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *AuditRepository
+	}{
+		{
+			name: "create audit repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewAuditRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAuditRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewAuditRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditRepository_Create(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateAuditLog(t, testDB)
+		truncateUsers(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	ip := netip.MustParseAddr("::1")
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx   context.Context
+		entry *queries.CreateAuditLogParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "create entry",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				entry: &queries.CreateAuditLogParams{
+					UserID:    pgtype.Int8{Int64: u.ID, Valid: true},
+					Entity:    model.EntityCategory,
+					EntityID:  1,
+					Action:    model.AuditUpdate,
+					Diff:      []byte(`{"title":{"before":"old","after":"new"}}`),
+					RequestID: pgtype.Text{String: "request", Valid: true},
+					ClientIp:  &ip,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "create entry without request",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				entry: &queries.CreateAuditLogParams{
+					Entity:   model.EntityEquipment,
+					EntityID: 1,
+					Action:   model.AuditCreate,
+					Diff:     []byte(`{}`),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "create entry with invalid diff",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				entry: &queries.CreateAuditLogParams{
+					Entity:   model.EntityEquipment,
+					EntityID: 1,
+					Action:   model.AuditCreate,
+					Diff:     []byte(`{`),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AuditRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			if err := r.Create(tt.args.ctx, tt.args.entry); (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuditRepository_List(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateAuditLog(t, testDB)
+		truncateUsers(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	addTestAuditEntry(t, testDB, u.ID, model.EntityCategory, model.AuditCreate, 1, "first")
+	addTestAuditEntry(t, testDB, u.ID, model.EntityCategory, model.AuditUpdate, 1, "second")
+	addTestAuditEntry(t, testDB, 0, model.EntityEquipment, model.AuditMove, 2, "")
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx    context.Context
+		filter *queries.ListAuditLogParams
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantIDs   []int64
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name: "list all entries",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				filter: &queries.ListAuditLogParams{
+					PaginationLimit: 10,
+				},
+			},
+			wantIDs:   []int64{3, 2, 1},
+			wantTotal: 3,
+			wantErr:   false,
+		},
+		{
+			name: "list entries of user",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				filter: &queries.ListAuditLogParams{
+					UserID:          u.ID,
+					PaginationLimit: 10,
+				},
+			},
+			wantIDs:   []int64{2, 1},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name: "list entries of entity and action",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				filter: &queries.ListAuditLogParams{
+					Entity:          model.EntityCategory,
+					EntityID:        1,
+					Action:          model.AuditUpdate,
+					PaginationLimit: 10,
+				},
+			},
+			wantIDs:   []int64{2},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name: "list entries of request",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				filter: &queries.ListAuditLogParams{
+					RequestID:       "first",
+					PaginationLimit: 10,
+				},
+			},
+			wantIDs:   []int64{1},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name: "list entries before date",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				filter: &queries.ListAuditLogParams{
+					DateTo:          pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+					PaginationLimit: 10,
+				},
+			},
+			wantIDs:   []int64{},
+			wantTotal: 0,
+			wantErr:   false,
+		},
+		{
+			name: "list second page",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				filter: &queries.ListAuditLogParams{
+					PaginationLimit:  2,
+					PaginationOffset: 2,
+				},
+			},
+			wantIDs:   []int64{1},
+			wantTotal: 3,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AuditRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, total, err := r.List(tt.args.ctx, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			ids := make([]int64, len(got))
+			for i, entry := range got {
+				ids[i] = entry.ID
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("List() ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("List() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
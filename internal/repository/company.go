@@ -115,3 +115,17 @@ func (r *CompanyRepository) List(ctx context.Context, qp *dto.QueryParams) ([]*m
 
 	return list, req[0].Total, nil
 }
+
+func (r *CompanyRepository) IDsByTitles(ctx context.Context, titles []string) (map[string]int64, error) {
+	req, err := r.queries.ListCompanyByTitles(ctx, titles)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	ids := make(map[string]int64, len(req))
+	for _, item := range req {
+		ids[item.Title] = item.ID
+	}
+
+	return ids, nil
+}
@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"slices"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	queries "github.com/oatsmoke/warehouse_backend/internal/db"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/serial"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 )
 
@@ -45,6 +49,10 @@ func (r *EquipmentRepository) Create(ctx context.Context, equipment *queries.Cre
 		return 0, logger.Error(logger.MsgFailedToInsert, logger.ErrNoRowsAffected)
 	}
 
+	if err := addCreateEvent(ctx, q, location); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return 0, logger.Error("", err)
 	}
@@ -52,9 +60,68 @@ func (r *EquipmentRepository) Create(ctx context.Context, equipment *queries.Cre
 	return e.ID, nil
 }
 
+func (r *EquipmentRepository) CreateMany(ctx context.Context, equipments []*queries.CreateEquipmentParams, location *queries.AddToStorageParams, move *queries.MoveToLocationParams) ([]int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return nil, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	ids := make([]int64, 0, len(equipments))
+	for _, equipment := range equipments {
+		e, err := q.CreateEquipment(ctx, equipment)
+		if err != nil {
+			return nil, logger.Error(logger.MsgFailedToInsert, err)
+		}
+
+		l := *location
+		l.EquipmentID = e.ID
+
+		ct, err := q.AddToStorage(ctx, &l)
+		if err != nil {
+			return nil, logger.Error(logger.MsgFailedToInsert, err)
+		}
+
+		if ct.RowsAffected() == 0 {
+			return nil, logger.Error(logger.MsgFailedToInsert, logger.ErrNoRowsAffected)
+		}
+
+		if err := addCreateEvent(ctx, q, &l); err != nil {
+			return nil, err
+		}
+
+		if move != nil {
+			m := *move
+			m.EquipmentID = e.ID
+
+			id, err := q.MoveToLocation(ctx, &m)
+			if err != nil {
+				return nil, logger.Error(logger.MsgFailedToInsert, err)
+			}
+
+			if err := addMoveEvent(ctx, q, id, &m); err != nil {
+				return nil, err
+			}
+		}
+
+		ids = append(ids, e.ID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, logger.Error("", err)
+	}
+
+	return ids, nil
+}
+
 func (r *EquipmentRepository) Read(ctx context.Context, id int64) (*model.Equipment, error) {
 	res, err := queries.New(r.postgresDB).ReadEquipment(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, logger.Error(logger.MsgFailedToScan, logger.ErrEquipmentNotFound)
+		}
 		return nil, logger.Error(logger.MsgFailedToScan, err)
 	}
 
@@ -74,11 +141,27 @@ func (r *EquipmentRepository) Read(ctx context.Context, id int64) (*model.Equipm
 		},
 		SerialNumber: res.SerialNumber,
 		DeletedAt:    validTime(res.DeletedAt),
+		WrittenOffAt: validTime(res.WrittenOffAt),
 	}
 
 	return equipment, nil
 }
 
+func (r *EquipmentRepository) ReadBySerialNumber(ctx context.Context, serialNumber string) (*model.Equipment, error) {
+	id, err := queries.New(r.postgresDB).GetEquipmentIDBySerialNumber(ctx, &queries.GetEquipmentIDBySerialNumberParams{
+		CanonicalSerialNumber: serial.Normalize(serialNumber, serial.Canonical),
+		SerialNumber:          serialNumber,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, logger.Error(logger.MsgFailedToScan, logger.ErrEquipmentNotFound)
+		}
+		return nil, logger.Error(logger.MsgFailedToScan, err)
+	}
+
+	return r.Read(ctx, id)
+}
+
 func (r *EquipmentRepository) Update(ctx context.Context, equipment *model.Equipment) error {
 	ct, err := queries.New(r.postgresDB).UpdateEquipment(ctx, &queries.UpdateEquipmentParams{
 		ID:           equipment.ID,
@@ -128,6 +211,7 @@ func (r *EquipmentRepository) List(ctx context.Context, qp *dto.QueryParams) ([]
 		WithDeleted:      qp.WithDeleted,
 		Search:           qp.Search,
 		Ids:              qp.IDs,
+		DepartmentID:     qp.ScopeDepartmentID,
 		SortColumn:       qp.SortColumn,
 		SortOrder:        qp.SortOrder,
 		PaginationLimit:  qp.PaginationLimit,
@@ -146,6 +230,10 @@ func (r *EquipmentRepository) List(ctx context.Context, qp *dto.QueryParams) ([]
 		equipment := &model.Equipment{
 			ID:           item.ID,
 			SerialNumber: item.SerialNumber,
+			Company: &model.Company{
+				ID:    item.CompanyID,
+				Title: item.CompanyTitle,
+			},
 			Profile: &model.Profile{
 				ID:    item.ProfileID,
 				Title: item.ProfileTitle,
@@ -161,3 +249,203 @@ func (r *EquipmentRepository) List(ctx context.Context, qp *dto.QueryParams) ([]
 
 	return list, req[0].Total, nil
 }
+
+func (r *EquipmentRepository) ExistingSerialNumbers(ctx context.Context, serialNumbers []string) ([]string, error) {
+	req, err := queries.New(r.postgresDB).ListExistingSerialNumbers(ctx, serialNumbers)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	return req, nil
+}
+
+func (r *EquipmentRepository) Duplicates(ctx context.Context, departmentID int64) ([]*model.SerialNumberDuplicate, error) {
+	req, err := queries.New(r.postgresDB).ListSerialNumberDuplicates(ctx, departmentID)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	list := make([]*model.SerialNumberDuplicate, 0)
+	var group *model.SerialNumberDuplicate
+	for _, item := range req {
+		if group == nil || group.NormalizedSerialNumber != item.NormalizedSerialNumber {
+			group = &model.SerialNumberDuplicate{
+				NormalizedSerialNumber: item.NormalizedSerialNumber,
+			}
+			list = append(list, group)
+		}
+
+		group.Equipments = append(group.Equipments, &model.Equipment{
+			ID:           item.ID,
+			SerialNumber: item.SerialNumber,
+			Company: &model.Company{
+				ID:    item.CompanyID,
+				Title: item.CompanyTitle,
+			},
+			Profile: &model.Profile{
+				ID:    item.ProfileID,
+				Title: item.ProfileTitle,
+			},
+		})
+	}
+
+	return list, nil
+}
+
+// Merge moves the movement history of the duplicate onto the equipment and
+// soft-deletes the duplicate. The merged history must still be one chain of
+// moves and the two records must not have replaced each other.
+func (r *EquipmentRepository) Merge(ctx context.Context, equipmentID, duplicateID, mergedBy int64) (*model.EquipmentMerge, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return nil, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	// rows are locked in id order so that concurrent merges can not deadlock
+	ids := []int64{equipmentID, duplicateID}
+	slices.Sort(ids)
+	for _, id := range ids {
+		equipment, err := q.LockEquipment(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, logger.Error(logger.MsgFailedToSelect, logger.ErrEquipmentNotFound)
+			}
+			return nil, logger.Error(logger.MsgFailedToSelect, err)
+		}
+
+		if equipment.DeletedAt.Valid {
+			return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+		}
+
+		if equipment.WrittenOffAt.Valid {
+			return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentWrittenOff)
+		}
+
+		if err := q.ExpireReservations(ctx, id); err != nil {
+			return nil, logger.Error(logger.MsgFailedToUpdate, err)
+		}
+	}
+
+	// an equipment holds one active reservation, the two can not be kept
+	reserved, err := q.CountReservedBetween(ctx, &queries.CountReservedBetweenParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+	})
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if reserved > 1 {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrMergeReserved)
+	}
+
+	replaces, err := q.CountReplacesBetween(ctx, &queries.CountReplacesBetweenParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+	})
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	locations, err := q.ListLocationsOfEquipments(ctx, ids)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if replaces != 0 || !coherentTimeline(locations) {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrTimelineConflict)
+	}
+
+	ct, err := q.ReparentLocations(ctx, &queries.ReparentLocationsParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+	})
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := q.ReparentLocationReversals(ctx, &queries.ReparentLocationReversalsParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+	}); err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := q.ReparentStocktakingItems(ctx, &queries.ReparentStocktakingItemsParams{
+		DuplicateID: duplicateID,
+		EquipmentID: equipmentID,
+	}); err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := q.ReparentStocktakingScans(ctx, &queries.ReparentStocktakingScansParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+	}); err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := q.ReparentRepairOrders(ctx, &queries.ReparentRepairOrdersParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+	}); err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := q.ReparentReservations(ctx, &queries.ReparentReservationsParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+	}); err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if _, err := q.DeleteEquipment(ctx, duplicateID); err != nil {
+		return nil, logger.Error(logger.MsgFailedToDelete, err)
+	}
+
+	merge, err := q.CreateEquipmentMerge(ctx, &queries.CreateEquipmentMergeParams{
+		EquipmentID: equipmentID,
+		DuplicateID: duplicateID,
+		MergedBy:    mergedBy,
+		Locations:   ct.RowsAffected(),
+	})
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := addMergeEvent(ctx, q, merge); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, logger.Error("", err)
+	}
+
+	return &model.EquipmentMerge{
+		ID:        merge.ID,
+		Equipment: &model.Equipment{ID: merge.EquipmentID},
+		Duplicate: &model.Equipment{ID: merge.DuplicateID},
+		MergedBy:  &model.User{ID: merge.MergedBy},
+		MergedAt:  validTime(merge.MergedAt),
+		Locations: merge.Locations,
+	}, nil
+}
+
+// coherentTimeline reports whether every move, ordered by time, starts where
+// the previous one ended. A second AddToStorage is allowed only while the
+// equipment is in storage, as it moves from and to nowhere.
+func coherentTimeline(locations []*queries.Location) bool {
+	for i := 1; i < len(locations); i++ {
+		last, next := locations[i-1], locations[i]
+		if last.ToDepartmentID != next.FromDepartmentID ||
+			last.ToEmployeeID != next.FromEmployeeID ||
+			last.ToContractID != next.FromContractID {
+			return false
+		}
+	}
+
+	return true
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	queries "github.com/oatsmoke/warehouse_backend/internal/db"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
@@ -20,7 +22,7 @@ func truncateEquipments(t *testing.T, testDB *pgxpool.Pool) {
 	defer cancel()
 
 	const query = `
-		TRUNCATE equipments, profiles, categories
+		TRUNCATE equipments, profiles, categories, companies
 		RESTART IDENTITY CASCADE;`
 
 	if _, err := testDB.Exec(ctx, query); err != nil {
@@ -30,19 +32,21 @@ func truncateEquipments(t *testing.T, testDB *pgxpool.Pool) {
 
 func addTestEquipment(t *testing.T, testDB *pgxpool.Pool) *model.Equipment {
 	t.Helper()
+	c := addTestCompany(t, testDB)
 	p := addTestProfile(t, testDB)
 	e := new(model.Equipment)
 
 	const query = `
-		INSERT INTO equipments (serial_number, profile_id)
-		VALUES ($1, $2)
+		INSERT INTO equipments (serial_number, profile_id, company_id)
+		VALUES ($1, $2, $3)
 		RETURNING id, serial_number;`
 
-	if err := testDB.QueryRow(t.Context(), query, generate.RandString(10), p.ID).
+	if err := testDB.QueryRow(t.Context(), query, generate.RandString(10), p.ID, c.ID).
 		Scan(&e.ID, &e.SerialNumber); err != nil {
 		t.Fatalf("failed to insert test equipment: %v", err)
 	}
 
+	e.Company = c
 	e.Profile = p
 
 	return e
@@ -50,19 +54,21 @@ func addTestEquipment(t *testing.T, testDB *pgxpool.Pool) *model.Equipment {
 
 func addTestDeletedEquipment(t *testing.T, testDB *pgxpool.Pool) *model.Equipment {
 	t.Helper()
+	c := addTestCompany(t, testDB)
 	p := addTestProfile(t, testDB)
 	e := new(model.Equipment)
 
 	const query = `
-		INSERT INTO equipments (serial_number, profile_id, deleted_at)
-		VALUES ($1, $2, now())
+		INSERT INTO equipments (serial_number, profile_id, company_id, deleted_at)
+		VALUES ($1, $2, $3, now())
 		RETURNING id, serial_number, deleted_at;`
 
-	if err := testDB.QueryRow(t.Context(), query, generate.RandString(10), p.ID).
+	if err := testDB.QueryRow(t.Context(), query, generate.RandString(10), p.ID, c.ID).
 		Scan(&e.ID, &e.SerialNumber, &e.DeletedAt); err != nil {
 		t.Fatalf("failed to insert test equipment: %v", err)
 	}
 
+	e.Company = c
 	e.Profile = p
 
 	return e
@@ -71,10 +77,9 @@ func addTestDeletedEquipment(t *testing.T, testDB *pgxpool.Pool) *model.Equipmen
 func TestNewEquipmentRepository(t *testing.T) {
 	testDB := postgresql.ConnectTest()
 	defer testDB.Close()
-	q := queries.New(testDB)
 
 	type args struct {
-		queries queries.Querier
+		postgresDB *pgxpool.Pool
 	}
 	tests := []struct {
 		name string
@@ -84,14 +89,14 @@ func TestNewEquipmentRepository(t *testing.T) {
 		{
 			name: "create equipment repository",
 			args: args{
-				queries: q,
+				postgresDB: testDB,
 			},
-			want: NewEquipmentRepository(q),
+			want: NewEquipmentRepository(testDB),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewEquipmentRepository(tt.args.queries); !reflect.DeepEqual(got, tt.want) {
+			if got := NewEquipmentRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewEquipmentRepository() = %v, want %v", got, tt.want)
 			}
 		})
@@ -102,17 +107,20 @@ func TestEquipmentRepository_Create(t *testing.T) {
 	testDB := postgresql.ConnectTest()
 	t.Cleanup(func() {
 		truncateEquipments(t, testDB)
+		truncateUsers(t, testDB)
 		testDB.Close()
 	})
-	q := queries.New(testDB)
+	c := addTestCompany(t, testDB)
 	p := addTestProfile(t, testDB)
+	u := addTestUser(t, testDB)
 
 	type fields struct {
-		queries queries.Querier
+		postgresDB *pgxpool.Pool
 	}
 	type args struct {
 		ctx       context.Context
-		equipment *model.Equipment
+		equipment *queries.CreateEquipmentParams
+		location  *queries.AddToStorageParams
 	}
 	tests := []struct {
 		name    string
@@ -124,15 +132,19 @@ func TestEquipmentRepository_Create(t *testing.T) {
 		{
 			name: "create equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
-				equipment: &model.Equipment{
+				equipment: &queries.CreateEquipmentParams{
 					SerialNumber: "test equipment",
-					Profile: &model.Profile{
-						ID: p.ID,
-					},
+					ProfileID:    p.ID,
+					CompanyID:    c.ID,
+				},
+				location: &queries.AddToStorageParams{
+					UserID:   u.ID,
+					MoveAt:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+					MoveCode: "AddToStorage",
 				},
 			},
 			want:    1,
@@ -141,15 +153,19 @@ func TestEquipmentRepository_Create(t *testing.T) {
 		{
 			name: "create duplicate equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
-				equipment: &model.Equipment{
+				equipment: &queries.CreateEquipmentParams{
 					SerialNumber: "test equipment",
-					Profile: &model.Profile{
-						ID: p.ID,
-					},
+					ProfileID:    p.ID,
+					CompanyID:    c.ID,
+				},
+				location: &queries.AddToStorageParams{
+					UserID:   u.ID,
+					MoveAt:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+					MoveCode: "AddToStorage",
 				},
 			},
 			wantErr: true,
@@ -158,9 +174,9 @@ func TestEquipmentRepository_Create(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &EquipmentRepository{
-				queries: tt.fields.queries,
+				postgresDB: tt.fields.postgresDB,
 			}
-			got, err := r.Create(tt.args.ctx, tt.args.equipment)
+			got, err := r.Create(tt.args.ctx, tt.args.equipment, tt.args.location)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -172,17 +188,165 @@ func TestEquipmentRepository_Create(t *testing.T) {
 	}
 }
 
+func TestEquipmentRepository_CreateMany(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	c := addTestCompany(t, testDB)
+	p := addTestProfile(t, testDB)
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+
+	date := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	location := &queries.AddToStorageParams{
+		UserID:   u.ID,
+		MoveAt:   date,
+		MoveCode: "AddToStorage",
+	}
+	move := &queries.MoveToLocationParams{
+		UserID:         u.ID,
+		MoveAt:         date,
+		MoveCode:       "StorageToDepartment",
+		ToDepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+	}
+	failedMove := *move
+	failedMove.ToDepartmentID = pgtype.Int8{Int64: d.ID + 1000, Valid: true}
+
+	countSerialNumbers := func(t *testing.T, serialNumbers []string) int64 {
+		t.Helper()
+		const query = `
+		SELECT COUNT(*)
+		FROM equipments
+		WHERE serial_number = ANY ($1);`
+
+		var n int64
+		if err := testDB.QueryRow(t.Context(), query, serialNumbers).Scan(&n); err != nil {
+			t.Fatalf("failed to count test equipments: %v", err)
+		}
+
+		return n
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx        context.Context
+		equipments []*queries.CreateEquipmentParams
+		location   *queries.AddToStorageParams
+		move       *queries.MoveToLocationParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "create equipments in storage",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				equipments: []*queries.CreateEquipmentParams{
+					{SerialNumber: "test equipment 1", ProfileID: p.ID, CompanyID: c.ID},
+					{SerialNumber: "test equipment 2", ProfileID: p.ID, CompanyID: c.ID},
+				},
+				location: location,
+			},
+			want:    []int64{1, 2},
+			wantErr: false,
+		},
+		{
+			name: "create equipments in department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				equipments: []*queries.CreateEquipmentParams{
+					{SerialNumber: "test equipment 3", ProfileID: p.ID, CompanyID: c.ID},
+				},
+				location: location,
+				move:     move,
+			},
+			want:    []int64{3},
+			wantErr: false,
+		},
+		{
+			name: "create equipments with duplicate rolls back",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				equipments: []*queries.CreateEquipmentParams{
+					{SerialNumber: "test equipment 4", ProfileID: p.ID, CompanyID: c.ID},
+					{SerialNumber: "test equipment 1", ProfileID: p.ID, CompanyID: c.ID},
+				},
+				location: location,
+			},
+			wantErr: true,
+		},
+		{
+			name: "create equipments with failed move rolls back",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				equipments: []*queries.CreateEquipmentParams{
+					{SerialNumber: "test equipment 5", ProfileID: p.ID, CompanyID: c.ID},
+					{SerialNumber: "test equipment 6", ProfileID: p.ID, CompanyID: c.ID},
+				},
+				location: location,
+				move:     &failedMove,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EquipmentRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.CreateMany(tt.args.ctx, tt.args.equipments, tt.args.location, tt.args.move)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateMany() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				// nothing of a failed batch is kept, not even the rows before the failure
+				serialNumbers := make([]string, 0, len(tt.args.equipments))
+				for _, e := range tt.args.equipments[:len(tt.args.equipments)-1] {
+					serialNumbers = append(serialNumbers, e.SerialNumber)
+				}
+				if n := countSerialNumbers(t, serialNumbers); n != 0 {
+					t.Errorf("CreateMany() kept %d equipments of a failed batch", n)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateMany() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEquipmentRepository_Read(t *testing.T) {
 	testDB := postgresql.ConnectTest()
 	t.Cleanup(func() {
 		truncateEquipments(t, testDB)
 		testDB.Close()
 	})
-	q := queries.New(testDB)
 	e := addTestEquipment(t, testDB)
 
 	type fields struct {
-		queries queries.Querier
+		postgresDB *pgxpool.Pool
 	}
 	type args struct {
 		ctx context.Context
@@ -198,7 +362,7 @@ func TestEquipmentRepository_Read(t *testing.T) {
 		{
 			name: "read equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -210,7 +374,7 @@ func TestEquipmentRepository_Read(t *testing.T) {
 		{
 			name: "read non-existing equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -222,7 +386,7 @@ func TestEquipmentRepository_Read(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &EquipmentRepository{
-				queries: tt.fields.queries,
+				postgresDB: tt.fields.postgresDB,
 			}
 			got, err := r.Read(tt.args.ctx, tt.args.id)
 			if (err != nil) != tt.wantErr {
@@ -236,17 +400,91 @@ func TestEquipmentRepository_Read(t *testing.T) {
 	}
 }
 
+func TestEquipmentRepository_ReadBySerialNumber(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateEquipments(t, testDB)
+		testDB.Close()
+	})
+	e := addTestEquipment(t, testDB)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx          context.Context
+		serialNumber string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Equipment
+		wantErr bool
+	}{
+		{
+			name: "read equipment by serial number",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				serialNumber: e.SerialNumber,
+			},
+			want:    e,
+			wantErr: false,
+		},
+		{
+			name: "read equipment by serial number written another way",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				serialNumber: strings.ToLower(e.SerialNumber[:5]) + "- " + e.SerialNumber[5:],
+			},
+			want:    e,
+			wantErr: false,
+		},
+		{
+			name: "read equipment by unknown serial number",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				serialNumber: "unknown",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EquipmentRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.ReadBySerialNumber(tt.args.ctx, tt.args.serialNumber)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadBySerialNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadBySerialNumber() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEquipmentRepository_Update(t *testing.T) {
 	testDB := postgresql.ConnectTest()
 	t.Cleanup(func() {
 		truncateEquipments(t, testDB)
 		testDB.Close()
 	})
-	q := queries.New(testDB)
 	e := addTestEquipment(t, testDB)
 
 	type fields struct {
-		queries queries.Querier
+		postgresDB *pgxpool.Pool
 	}
 	type args struct {
 		ctx       context.Context
@@ -261,7 +499,7 @@ func TestEquipmentRepository_Update(t *testing.T) {
 		{
 			name: "update equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -278,7 +516,7 @@ func TestEquipmentRepository_Update(t *testing.T) {
 		{
 			name: "update non-existing equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -296,7 +534,7 @@ func TestEquipmentRepository_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &EquipmentRepository{
-				queries: tt.fields.queries,
+				postgresDB: tt.fields.postgresDB,
 			}
 			if err := r.Update(tt.args.ctx, tt.args.equipment); (err != nil) != tt.wantErr {
 				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
@@ -311,11 +549,10 @@ func TestEquipmentRepository_Delete(t *testing.T) {
 		truncateEquipments(t, testDB)
 		testDB.Close()
 	})
-	q := queries.New(testDB)
 	e := addTestEquipment(t, testDB)
 
 	type fields struct {
-		queries queries.Querier
+		postgresDB *pgxpool.Pool
 	}
 	type args struct {
 		ctx context.Context
@@ -330,7 +567,7 @@ func TestEquipmentRepository_Delete(t *testing.T) {
 		{
 			name: "delete equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -341,7 +578,7 @@ func TestEquipmentRepository_Delete(t *testing.T) {
 		{
 			name: "delete non-existing equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -353,7 +590,7 @@ func TestEquipmentRepository_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &EquipmentRepository{
-				queries: tt.fields.queries,
+				postgresDB: tt.fields.postgresDB,
 			}
 			if err := r.Delete(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
 				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
@@ -368,12 +605,11 @@ func TestEquipmentRepository_Restore(t *testing.T) {
 		truncateEquipments(t, testDB)
 		testDB.Close()
 	})
-	q := queries.New(testDB)
 	e := addTestEquipment(t, testDB)
 	de := addTestDeletedEquipment(t, testDB)
 
 	type fields struct {
-		queries queries.Querier
+		postgresDB *pgxpool.Pool
 	}
 	type args struct {
 		ctx context.Context
@@ -388,7 +624,7 @@ func TestEquipmentRepository_Restore(t *testing.T) {
 		{
 			name: "restore equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -399,7 +635,7 @@ func TestEquipmentRepository_Restore(t *testing.T) {
 		{
 			name: "restore non-existing equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -410,7 +646,7 @@ func TestEquipmentRepository_Restore(t *testing.T) {
 		{
 			name: "restore not deleted equipment",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -422,7 +658,7 @@ func TestEquipmentRepository_Restore(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &EquipmentRepository{
-				queries: tt.fields.queries,
+				postgresDB: tt.fields.postgresDB,
 			}
 			if err := r.Restore(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
 				t.Errorf("Restore() error = %v, wantErr %v", err, tt.wantErr)
@@ -437,12 +673,11 @@ func TestEquipmentRepository_List(t *testing.T) {
 		truncateEquipments(t, testDB)
 		testDB.Close()
 	})
-	q := queries.New(testDB)
 	e := addTestEquipment(t, testDB)
 	de := addTestDeletedEquipment(t, testDB)
 
 	type fields struct {
-		queries queries.Querier
+		postgresDB *pgxpool.Pool
 	}
 	type args struct {
 		ctx context.Context
@@ -459,7 +694,7 @@ func TestEquipmentRepository_List(t *testing.T) {
 		{
 			name: "list equipments without deleted",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -477,7 +712,7 @@ func TestEquipmentRepository_List(t *testing.T) {
 		{
 			name: "list equipments with deleted",
 			fields: fields{
-				queries: q,
+				postgresDB: testDB,
 			},
 			args: args{
 				ctx: t.Context(),
@@ -497,7 +732,7 @@ func TestEquipmentRepository_List(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &EquipmentRepository{
-				queries: tt.fields.queries,
+				postgresDB: tt.fields.postgresDB,
 			}
 			got, got1, err := r.List(tt.args.ctx, tt.args.qp)
 			if (err != nil) != tt.wantErr {
@@ -513,3 +748,303 @@ func TestEquipmentRepository_List(t *testing.T) {
 		})
 	}
 }
+
+func TestEquipmentRepository_ExistingSerialNumbers(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateEquipments(t, testDB)
+		testDB.Close()
+	})
+	e := addTestEquipment(t, testDB)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx           context.Context
+		serialNumbers []string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "existing serial numbers",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:           t.Context(),
+				serialNumbers: []string{e.SerialNumber, "unknown serial number"},
+			},
+			want:    []string{e.SerialNumber},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EquipmentRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.ExistingSerialNumbers(tt.args.ctx, tt.args.serialNumbers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExistingSerialNumbers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExistingSerialNumbers() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEquipmentRepository_Duplicates(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateEquipments(t, testDB)
+		testDB.Close()
+	})
+	addTestEquipment(t, testDB)
+	e1 := addTestEquipment(t, testDB)
+	e2 := addTestEquipment(t, testDB)
+
+	const query = `
+		UPDATE equipments
+		SET serial_number = $2
+		WHERE id = $1;`
+
+	for _, e := range []struct {
+		equipment    *model.Equipment
+		serialNumber string
+	}{{e1, "00ab-1"}, {e2, "AB 1"}} {
+		if _, err := testDB.Exec(t.Context(), query, e.equipment.ID, e.serialNumber); err != nil {
+			t.Fatalf("failed to update test equipment: %v", err)
+		}
+		e.equipment.SerialNumber = e.serialNumber
+		e.equipment.Profile = &model.Profile{
+			ID:    e.equipment.Profile.ID,
+			Title: e.equipment.Profile.Title,
+		}
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx          context.Context
+		departmentID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*model.SerialNumberDuplicate
+		wantErr bool
+	}{
+		{
+			name: "list serial number duplicates",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+			},
+			want: []*model.SerialNumberDuplicate{
+				{
+					NormalizedSerialNumber: "AB1",
+					Equipments:             []*model.Equipment{e1, e2},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "list serial number duplicates of other department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				departmentID: 999,
+			},
+			want:    []*model.SerialNumberDuplicate{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EquipmentRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Duplicates(tt.args.ctx, tt.args.departmentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Duplicates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Duplicates() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEquipmentRepository_Merge(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateOutboxEvents(t, testDB)
+		truncateReservations(t, testDB)
+		truncateWriteOffs(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e1 := addTestEquipment(t, testDB)
+	e2 := addTestEquipment(t, testDB)
+	e3 := addTestEquipment(t, testDB)
+	e4 := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e1.ID, u.ID, 0)
+	addTestLocation(t, testDB, e2.ID, u.ID, 0)
+	addTestLocation(t, testDB, e3.ID, u.ID, 0)
+	addTestLocation(t, testDB, e3.ID, u.ID, d.ID)
+	addTestLocation(t, testDB, e4.ID, u.ID, 0)
+	e5 := addTestEquipment(t, testDB)
+	e6 := addTestEquipment(t, testDB)
+	for _, e := range []*model.Equipment{e5, e6} {
+		addTestLocation(t, testDB, e.ID, u.ID, 0)
+		addTestReservation(t, testDB, e.ID, u.ID, d.ID)
+	}
+	e7 := addTestEquipment(t, testDB)
+	e8 := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e7.ID, u.ID, 0)
+	addTestLocation(t, testDB, e8.ID, u.ID, 0)
+	if _, err := NewWriteOffRepository(testDB).Approve(t.Context(), addTestWriteOff(t, testDB, e8.ID, u.ID), u.ID, testWriteOffMove(e8.ID, u.ID)); err != nil {
+		t.Fatalf("failed to approve test write-off: %v", err)
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx         context.Context
+		equipmentID int64
+		duplicateID int64
+		mergedBy    int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.EquipmentMerge
+		wantErr bool
+	}{
+		{
+			name: "merge equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e1.ID,
+				duplicateID: e2.ID,
+				mergedBy:    u.ID,
+			},
+			want: &model.EquipmentMerge{
+				ID:        1,
+				Equipment: &model.Equipment{ID: e1.ID},
+				Duplicate: &model.Equipment{ID: e2.ID},
+				MergedBy:  &model.User{ID: u.ID},
+				Locations: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "merge deleted duplicate",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e1.ID,
+				duplicateID: e2.ID,
+				mergedBy:    u.ID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "merge incoherent history",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e3.ID,
+				duplicateID: e4.ID,
+				mergedBy:    u.ID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "merge written off duplicate",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e7.ID,
+				duplicateID: e8.ID,
+				mergedBy:    u.ID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "merge equipment reserved on both sides",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e5.ID,
+				duplicateID: e6.ID,
+				mergedBy:    u.ID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "merge non-existing equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e1.ID,
+				duplicateID: 999,
+				mergedBy:    u.ID,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EquipmentRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Merge(tt.args.ctx, tt.args.equipmentID, tt.args.duplicateID, tt.args.mergedBy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil {
+				got.MergedAt = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := countTestOutboxEvents(t, testDB, model.EventEquipmentMerged, e1.ID); got != 1 {
+		t.Errorf("Merge() events = %d, want %d", got, 1)
+	}
+}
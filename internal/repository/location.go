@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 )
@@ -17,21 +21,399 @@ func NewLocationRepository(postgresDB *pgxpool.Pool) *LocationRepository {
 	return &LocationRepository{postgresDB: postgresDB}
 }
 
-func (r *LocationRepository) Move(ctx context.Context, location *queries.MoveToLocationParams) error {
-	ct, err := queries.New(r.postgresDB).MoveToLocation(ctx, location)
+func (r *LocationRepository) Move(ctx context.Context, location *queries.MoveToLocationParams) (int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
 	if err != nil {
-		return logger.Error(logger.MsgFailedToInsert, err)
+		return 0, logger.Error("", err)
 	}
+	defer tx.Rollback(ctx)
 
-	if ct.RowsAffected() == 0 {
-		return logger.Error(logger.MsgFailedToInsert, logger.ErrNoRowsAffected)
+	q := queries.New(tx)
+
+	id, err := q.MoveToLocation(ctx, location)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := addMoveEvent(ctx, q, id, location); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return id, nil
+}
+
+func (r *LocationRepository) MoveMany(ctx context.Context, locations []*queries.MoveToLocationParams) ([]int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return nil, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	ids := make([]int64, 0, len(locations))
+	for _, location := range locations {
+		id, err := moveInTx(ctx, q, location)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, logger.Error("", err)
+	}
+
+	return ids, nil
+}
+
+// moveInTx locks the equipment row and inserts the move only if the
+// from_* columns still match the latest location of the equipment.
+func moveInTx(ctx context.Context, q *queries.Queries, location *queries.MoveToLocationParams) (int64, error) {
+	equipment, err := q.LockEquipment(ctx, location.EquipmentID)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if equipment.DeletedAt.Valid {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+	}
+
+	if equipment.WrittenOffAt.Valid {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentWrittenOff)
+	}
+
+	last, err := q.GetLastLocation(ctx, location.EquipmentID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	// equipment at repair comes back only through its repair order
+	if last.MoveCode == model.SentToRepair && location.MoveCode != model.ReturnedFromRepair {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentInRepair)
+	}
+
+	if last.MoveCode != model.SentToRepair && location.MoveCode == model.ReturnedFromRepair {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+	}
+
+	if last.ToDepartmentID != location.FromDepartmentID ||
+		last.ToEmployeeID != location.FromEmployeeID ||
+		last.ToContractID != location.FromContractID {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrLocationChanged)
+	}
+
+	// a back-dated move would land behind the latest location and leave it
+	// as the current one
+	if last.MoveAt.Valid && location.MoveAt.Time.Before(last.MoveAt.Time) {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+	}
+
+	// a reserved equipment goes only to the reserved destination, and that
+	// move consumes the reservation
+	reservation, err := q.GetActiveReservation(ctx, location.EquipmentID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+	reserved := err == nil
+
+	if reserved && (reservation.ToDepartmentID != location.ToDepartmentID ||
+		reservation.ToEmployeeID != location.ToEmployeeID ||
+		reservation.ToContractID != location.ToContractID) {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentReserved)
+	}
+
+	id, err := q.MoveToLocation(ctx, location)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := addMoveEvent(ctx, q, id, location); err != nil {
+		return 0, err
+	}
+
+	if reserved {
+		if err := q.ConsumeReservation(ctx, &queries.ConsumeReservationParams{
+			ClosedBy:   location.UserID,
+			LocationID: id,
+			ID:         reservation.ID,
+		}); err != nil {
+			return 0, logger.Error(logger.MsgFailedToUpdate, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r *LocationRepository) Revert(ctx context.Context, locationID, revertedBy int64) ([]int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return nil, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	location, err := lastInTx(ctx, q, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []*queries.Location{location}
+	var replaceID pgtype.Int8
+
+	replace, err := q.GetReplaceByLocation(ctx, locationID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if err == nil {
+		replaceID = pgtype.Int8{Int64: replace.ID, Valid: true}
+
+		pairID := replace.MoveInID
+		if pairID == locationID {
+			pairID = replace.MoveOutID
+		}
+
+		pair, err := lastInTx(ctx, q, pairID)
+		if err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, pair)
 	}
 
-	return nil
+	ids := make([]int64, 0, len(locations))
+	for _, l := range locations {
+		if _, err := q.CreateLocationReversal(ctx, &queries.CreateLocationReversalParams{
+			LocationID:  l.ID,
+			ReplaceID:   replaceID,
+			EquipmentID: l.EquipmentID,
+			RevertedBy:  revertedBy,
+		}); err != nil {
+			return nil, logger.Error(logger.MsgFailedToInsert, err)
+		}
+
+		// the reservation consumed by the move holds the location, so it is
+		// opened again before the move goes away
+		if err := q.ReopenReservation(ctx, l.ID); err != nil {
+			return nil, logger.Error(logger.MsgFailedToUpdate, err)
+		}
+
+		ct, err := q.DeleteLocation(ctx, l.ID)
+		if err != nil {
+			return nil, logger.Error(logger.MsgFailedToDelete, err)
+		}
+
+		if ct.RowsAffected() == 0 {
+			return nil, logger.Error(logger.MsgFailedToDelete, logger.ErrNoRowsAffected)
+		}
+
+		if err := addRevertEvent(ctx, q, l, revertedBy); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, l.ID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, logger.Error("", err)
+	}
+
+	return ids, nil
 }
 
-func (r *LocationRepository) List(ctx context.Context, toDepartmentID int64) ([]*model.Equipment, int64, error) {
-	res, err := queries.New(r.postgresDB).ListEquipmentFromLocation(ctx, toDepartmentID)
+// lastInTx locks the equipment of the location and returns the location
+// only if it is still the latest one of that equipment.
+func lastInTx(ctx context.Context, q *queries.Queries, locationID int64) (*queries.Location, error) {
+	location, err := q.GetLocation(ctx, locationID)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	equipment, err := q.LockEquipment(ctx, location.EquipmentID)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if equipment.DeletedAt.Valid {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+	}
+
+	if equipment.WrittenOffAt.Valid {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentWrittenOff)
+	}
+
+	last, err := q.GetLastLocation(ctx, location.EquipmentID)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if last.ID != location.ID {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrLocationChanged)
+	}
+
+	return location, nil
+}
+
+func (r *LocationRepository) GetLast(ctx context.Context, equipmentID int64) (*model.Location, error) {
+	res, err := queries.New(r.postgresDB).GetLastLocation(ctx, equipmentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &model.Location{Equipment: &model.Equipment{ID: equipmentID}}, nil
+		}
+		return nil, logger.Error(logger.MsgFailedToScan, err)
+	}
+
+	return toLocation(res), nil
+}
+
+// Current returns the latest location of the equipment with titles of its
+// destination, or nil when the equipment has never been placed.
+func (r *LocationRepository) Current(ctx context.Context, equipmentID int64) (*model.Location, error) {
+	res, err := queries.New(r.postgresDB).GetCurrentLocation(ctx, equipmentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, logger.Error(logger.MsgFailedToScan, err)
+	}
+
+	location := &model.Location{
+		ID:        res.ID,
+		Equipment: &model.Equipment{ID: equipmentID},
+		User: &model.User{
+			ID:       res.UserID,
+			Username: res.UserUsername,
+		},
+		MoveAt:   validTime(res.MoveAt),
+		MoveCode: res.MoveCode,
+	}
+
+	if res.ToDepartmentID.Valid {
+		location.ToDepartment = &model.Department{
+			ID:    res.ToDepartmentID.Int64,
+			Title: validString(res.ToDepartmentTitle),
+		}
+	}
+	if res.ToEmployeeID.Valid {
+		location.ToEmployee = &model.Employee{
+			ID:         res.ToEmployeeID.Int64,
+			LastName:   validString(res.ToEmployeeLastName),
+			FirstName:  validString(res.ToEmployeeFirstName),
+			MiddleName: validString(res.ToEmployeeMiddleName),
+		}
+	}
+	if res.ToContractID.Valid {
+		location.ToContract = &model.Contract{
+			ID:      res.ToContractID.Int64,
+			Number:  validString(res.ToContractNumber),
+			Address: validString(res.ToContractAddress),
+		}
+	}
+
+	return location, nil
+}
+
+func (r *LocationRepository) History(ctx context.Context, equipmentID int64, qp *dto.QueryParams) ([]*model.Location, int64, error) {
+	req, err := queries.New(r.postgresDB).ListLocationHistory(ctx, &queries.ListLocationHistoryParams{
+		EquipmentID:      equipmentID,
+		PaginationLimit:  qp.PaginationLimit,
+		PaginationOffset: qp.PaginationOffset,
+	})
+	if err != nil {
+		return nil, 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if len(req) < 1 {
+		return []*model.Location{}, 0, nil
+	}
+
+	list := make([]*model.Location, len(req))
+	for i, item := range req {
+		location := &model.Location{
+			ID:        item.ID,
+			Equipment: &model.Equipment{ID: equipmentID},
+			User: &model.User{
+				ID:       item.UserID,
+				Username: item.UserUsername,
+			},
+			MoveAt:   validTime(item.MoveAt),
+			MoveCode: item.MoveCode,
+			MoveType: validString(item.MoveType),
+			Price:    validString(item.Price),
+			Comment:  validString(item.Comment),
+		}
+
+		if item.FromDepartmentID.Valid {
+			location.FromDepartment = &model.Department{
+				ID:    item.FromDepartmentID.Int64,
+				Title: validString(item.FromDepartmentTitle),
+			}
+		}
+		if item.FromEmployeeID.Valid {
+			location.FromEmployee = &model.Employee{
+				ID:         item.FromEmployeeID.Int64,
+				LastName:   validString(item.FromEmployeeLastName),
+				FirstName:  validString(item.FromEmployeeFirstName),
+				MiddleName: validString(item.FromEmployeeMiddleName),
+			}
+		}
+		if item.FromContractID.Valid {
+			location.FromContract = &model.Contract{
+				ID:     item.FromContractID.Int64,
+				Number: validString(item.FromContractNumber),
+			}
+		}
+		if item.ToDepartmentID.Valid {
+			location.ToDepartment = &model.Department{
+				ID:    item.ToDepartmentID.Int64,
+				Title: validString(item.ToDepartmentTitle),
+			}
+		}
+		if item.ToEmployeeID.Valid {
+			location.ToEmployee = &model.Employee{
+				ID:         item.ToEmployeeID.Int64,
+				LastName:   validString(item.ToEmployeeLastName),
+				FirstName:  validString(item.ToEmployeeFirstName),
+				MiddleName: validString(item.ToEmployeeMiddleName),
+			}
+		}
+		if item.ToContractID.Valid {
+			location.ToContract = &model.Contract{
+				ID:     item.ToContractID.Int64,
+				Number: validString(item.ToContractNumber),
+			}
+		}
+		if item.ReplaceID.Valid {
+			location.Replace = &model.Replace{
+				ID:      item.ReplaceID.Int64,
+				MoveIn:  &model.Location{ID: item.ReplaceMoveInID.Int64},
+				MoveOut: &model.Location{ID: item.ReplaceMoveOutID.Int64},
+			}
+		}
+
+		list[i] = location
+	}
+
+	return list, req[0].Total, nil
+}
+
+func (r *LocationRepository) List(ctx context.Context, qp *dto.QueryParams) ([]*model.Equipment, int64, error) {
+	res, err := queries.New(r.postgresDB).ListEquipmentFromLocation(ctx, &queries.ListEquipmentFromLocationParams{
+		Param:            qp.Param,
+		ParamID:          qp.ParamID,
+		Search:           qp.Search,
+		SortColumn:       qp.SortColumn,
+		SortOrder:        qp.SortOrder,
+		PaginationLimit:  qp.PaginationLimit,
+		PaginationOffset: qp.PaginationOffset,
+	})
 	if err != nil {
 		return nil, 0, logger.Error(logger.MsgFailedToSelect, err)
 	}
@@ -51,6 +433,12 @@ func (r *LocationRepository) List(ctx context.Context, toDepartmentID int64) ([]
 				},
 			},
 		}
+		if item.ReservationID.Valid {
+			equipment.Reservation = &model.Reservation{
+				ID:        item.ReservationID.Int64,
+				ExpiresAt: validTime(item.ReservationExpiresAt),
+			}
+		}
 		list = append(list, equipment)
 	}
 
@@ -62,107 +450,91 @@ func (r *LocationRepository) List(ctx context.Context, toDepartmentID int64) ([]
 	return list, total, nil
 }
 
-//// AddToStorage is equipment add to storage
-//func (r *LocationRepository) AddToStorage(ctx context.Context, date *time.Time, equipmentId, employeeId, companyId int64) error {
-//	fmt.Println("repo:", date)
-//	const query = `
-//		INSERT INTO locations (date, code, equipment, employee, company)
-//		VALUES ($1, $2, $3, $4, $5);`
-//
-//	if _, err := r.DB.Exec(ctx, query, date, "ADD_TO_STORAGE", equipmentId, employeeId, companyId); err != nil {
-//		return err
-//	}
-//
-//	return nil
-//}
-//
-//// TransferToStorage is equipment transfer to storage
-//func (r *LocationRepository) TransferToStorage(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId int64, nowLocation []interface{}) (int64, error) {
-//	var id int64
-//	const query = `
-//		INSERT INTO locations (date, code, equipment, employee, company, from_department, from_employee, from_contract)
-//		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-//		RETURNING id;`
-//
-//	if err := r.DB.QueryRow(ctx, query, date, code, equipmentId, employeeId, companyId, nowLocation[0], nowLocation[1], nowLocation[2]).Scan(&id); err != nil {
-//		return 0, err
-//	}
-//
-//	return id, nil
-//}
-//
-//// TransferToDepartment is equipment transfer to department
-//func (r *LocationRepository) TransferToDepartment(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toDepartment int64, nowLocation []interface{}) (int64, error) {
-//	var id int64
-//	const query = `
-//		INSERT INTO locations (date, code, equipment, employee, company, to_department, from_department, from_employee, from_contract)
-//		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-//		RETURNING id;`
-//
-//	if err := r.DB.QueryRow(ctx, query, date, code, equipmentId, employeeId, companyId, toDepartment, nowLocation[0], nowLocation[1], nowLocation[2]).Scan(&id); err != nil {
-//		return 0, err
-//	}
-//
-//	return id, nil
-//}
-//
-//// TransferToEmployee is equipment transfer to employee
-//func (r *LocationRepository) TransferToEmployee(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toEmployee int64, nowLocation []interface{}) (int64, error) {
-//	var id int64
-//	const query = `
-//		INSERT INTO locations (date, code, equipment, employee, company, to_employee, from_department, from_employee, from_contract)
-//		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-//		RETURNING id;`
-//
-//	if err := r.DB.QueryRow(ctx, query, date, code, equipmentId, employeeId, companyId, toEmployee, nowLocation[0], nowLocation[1], nowLocation[2]).Scan(&id); err != nil {
-//		return 0, err
-//	}
-//
-//	return id, nil
-//}
-//
-//// TransferToEmployeeInDepartment is equipment transfer to employee in department
-//func (r *LocationRepository) TransferToEmployeeInDepartment(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toDepartment, toEmployee int64, nowLocation []interface{}) (int64, error) {
-//	var id int64
-//	const query = `
-//		INSERT INTO locations (date, code, equipment, employee, company, to_department, to_employee, from_department, from_employee, from_contract)
-//		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-//		RETURNING id;`
-//
-//	if err := r.DB.QueryRow(ctx, query, date, code, equipmentId, employeeId, companyId, toDepartment, toEmployee, nowLocation[0], nowLocation[1], nowLocation[2]).Scan(&id); err != nil {
-//		return 0, err
-//	}
-//
-//	return id, nil
-//}
-//
-//// TransferToContract is equipment transfer to contract
-//func (r *LocationRepository) TransferToContract(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toContract int64, transferType string, price string, nowLocation []interface{}) (int64, error) {
-//	var id int64
-//	const query = `
-//		INSERT INTO locations (date, code, equipment, employee, company, to_contract, transfer_type, price, from_department, from_employee, from_contract)
-//		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-//		RETURNING id;`
-//
-//	if err := r.DB.QueryRow(ctx, query, date, code, equipmentId, employeeId, companyId, toContract, transferType, price, nowLocation[0], nowLocation[1], nowLocation[2]).Scan(&id); err != nil {
-//		return 0, err
-//	}
-//	return id, nil
-//}
-//
-//// Delete is equipment transfer delete
-//func (r *LocationRepository) Delete(ctx context.Context, id int64) error {
-//	const query = `
-//		DELETE FROM locations
-//       	WHERE id = $1;`
-//
-//	if _, err := r.DB.Exec(ctx, query, id); err != nil {
-//		return err
-//	}
-//
-//	return nil
-//}
-//
+func (r *LocationRepository) DepartmentBalance(ctx context.Context, params *queries.DepartmentBalanceReportParams) ([]*model.DepartmentBalanceRow, error) {
+	res, err := queries.New(r.postgresDB).DepartmentBalanceReport(ctx, params)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	list := make([]*model.DepartmentBalanceRow, len(res))
+	for i, item := range res {
+		list[i] = &model.DepartmentBalanceRow{
+			Category: &model.Category{
+				ID:    item.CategoryID,
+				Title: item.CategoryTitle,
+			},
+			Opening:        item.Opening,
+			FromStorage:    item.FromStorage,
+			FromContract:   item.FromContract,
+			FromDepartment: item.FromDepartment,
+			FromEmployee:   item.FromEmployee,
+			FromRepair:     item.FromRepair,
+			ToStorage:      item.ToStorage,
+			ToContract:     item.ToContract,
+			ToDepartment:   item.ToDepartment,
+			ToEmployee:     item.ToEmployee,
+			ToRepair:       item.ToRepair,
+			WrittenOff:     item.WrittenOff,
+			Closing:        item.Closing,
+		}
+	}
+
+	return list, nil
+}
+
+func movedLocation(id int64, location *queries.MoveToLocationParams) *model.Location {
+	return toLocation(&queries.Location{
+		ID:               id,
+		EquipmentID:      location.EquipmentID,
+		UserID:           location.UserID,
+		MoveAt:           location.MoveAt,
+		MoveCode:         location.MoveCode,
+		MoveType:         location.MoveType,
+		Price:            location.Price,
+		FromDepartmentID: location.FromDepartmentID,
+		FromEmployeeID:   location.FromEmployeeID,
+		FromContractID:   location.FromContractID,
+		ToDepartmentID:   location.ToDepartmentID,
+		ToEmployeeID:     location.ToEmployeeID,
+		ToContractID:     location.ToContractID,
+		Comment:          location.Comment,
+	})
+}
+
+func toLocation(l *queries.Location) *model.Location {
+	location := &model.Location{
+		ID:        l.ID,
+		Equipment: &model.Equipment{ID: l.EquipmentID},
+		User:      &model.User{ID: l.UserID},
+		MoveAt:    validTime(l.MoveAt),
+		MoveCode:  l.MoveCode,
+		MoveType:  validString(l.MoveType),
+		Price:     validString(l.Price),
+		Comment:   validString(l.Comment),
+	}
+
+	if l.FromDepartmentID.Valid {
+		location.FromDepartment = &model.Department{ID: l.FromDepartmentID.Int64}
+	}
+	if l.FromEmployeeID.Valid {
+		location.FromEmployee = &model.Employee{ID: l.FromEmployeeID.Int64}
+	}
+	if l.FromContractID.Valid {
+		location.FromContract = &model.Contract{ID: l.FromContractID.Int64}
+	}
+	if l.ToDepartmentID.Valid {
+		location.ToDepartment = &model.Department{ID: l.ToDepartmentID.Int64}
+	}
+	if l.ToEmployeeID.Valid {
+		location.ToEmployee = &model.Employee{ID: l.ToEmployeeID.Int64}
+	}
+	if l.ToContractID.Valid {
+		location.ToContract = &model.Contract{ID: l.ToContractID.Int64}
+	}
+
+	return location
+}
+
 //// GetById is equipment get by id
 //func (r *LocationRepository) GetById(ctx context.Context, equipmentId int64) (*model.Location, error) {
 //	equipmentByLoc := newLocation()
@@ -229,579 +601,6 @@ func (r *LocationRepository) List(ctx context.Context, toDepartmentID int64) ([]
 //	return equipmentByLoc, nil
 //}
 //
-//// GetHistory is equipment get history
-//func (r *LocationRepository) GetHistory(ctx context.Context, equipmentId int64) ([]*model.Location, error) {
-//	var histories []*model.Location
-//
-//	const query = `
-//		SELECT locations.id, locations.date, locations.code, locations.transfer_type, locations.price,
-//			   employees.name,
-//			   companies.title,
-//			   to_department.title,
-//			   to_employee.name,
-//			   to_contract.number, to_contract.address
-//		FROM locations
-//		LEFT JOIN employees ON employees.id = locations.employee
-//		LEFT JOIN companies ON companies.id = locations.company
-//		LEFT JOIN departments to_department ON to_department.id = locations.to_department
-//		LEFT JOIN employees to_employee ON to_employee.id = locations.to_employee
-//		LEFT JOIN contracts to_contract ON to_contract.id = locations.to_contract
-//		WHERE locations.equipment = $1
-//		ORDER BY locations.id DESC;`
-//
-//	rows, err := r.DB.Query(ctx, query, equipmentId)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		location := newLocation()
-//		var (
-//			transferType      pgtype.Text
-//			price             pgtype.Text
-//			toDepartmentTitle pgtype.Text
-//			toEmployeeName    pgtype.Text
-//			toContractNumber  pgtype.Text
-//			toContractAddress pgtype.Text
-//		)
-//
-//		if err := rows.Scan(
-//			&location.ID,
-//			&location.Date,
-//			&location.Code,
-//			&transferType,
-//			&price,
-//			&location.Employee.FirstName,
-//			&location.Company.Title,
-//			&toDepartmentTitle,
-//			&toEmployeeName,
-//			&toContractNumber,
-//			&toContractAddress,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		location.TransferType = validString(transferType)
-//		location.Price = validString(price)
-//		location.ToDepartment.Title = validString(toDepartmentTitle)
-//		location.ToEmployee.FirstName = validString(toEmployeeName)
-//		location.ToContract.Number = validString(toContractNumber)
-//		location.ToContract.Address = validString(toContractAddress)
-//
-//		histories = append(histories, location)
-//	}
-//
-//	return histories, err
-//}
-//
-//// GetLocationNow is equipment get location now
-//func (r *LocationRepository) GetLocationNow(ctx context.Context, equipmentId int64) ([]interface{}, error) {
-//	var department, employee, contract interface{}
-//	var arr []interface{}
-//
-//	const query = `
-//		SELECT to_department, to_employee, to_contract
-//		FROM locations
-//       	WHERE locations.id IN
-//       	      (SELECT MAX(locations.id)
-//       	       FROM locations
-//       	       GROUP BY locations.equipment)
-//		AND locations.equipment = $1;`
-//
-//	if err := r.DB.QueryRow(ctx, query, equipmentId).Scan(
-//		&department,
-//		&employee,
-//		&contract,
-//	); err != nil {
-//		return []interface{}{}, err
-//	}
-//	arr = append(arr, department, employee, contract)
-//
-//	return arr, nil
-//}
-//
-//// GetByLocationStorage is equipment get by location storage
-//func (r *LocationRepository) GetByLocationStorage(ctx context.Context) ([]*model.Location, error) {
-//	var equipmentsByLoc []*model.Location
-//
-//	const query = `
-//		SELECT equipments.id, equipments.serial_number,
-//		       profiles.title,
-//		       categories.title,
-//		       companies.id, companies.title
-//		FROM locations
-//		LEFT JOIN equipments ON equipments.id = locations.equipment
-//		LEFT JOIN profiles ON profiles.id = equipments.profile
-//		LEFT JOIN categories ON categories.id = profiles.category
-//		LEFT JOIN companies ON companies.id = locations.company
-//		WHERE locations.id IN
-//		      (SELECT MAX(locations.id)
-//		       FROM locations
-//		       GROUP BY locations.equipment)
-//		AND locations.to_department IS NULL
-//		AND locations.to_employee IS NULL
-//		AND locations.to_contract IS NULL
-//		AND equipments.deleted = FALSE
-//		ORDER BY profiles.title,
-//		         equipments.serial_number;`
-//
-//	rows, err := r.DB.Query(ctx, query)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		equipmentByLoc := newLocation()
-//		if err := rows.Scan(
-//			&equipmentByLoc.Equipment.ID,
-//			&equipmentByLoc.Equipment.SerialNumber,
-//			&equipmentByLoc.Equipment.Profile.Title,
-//			&equipmentByLoc.Equipment.Profile.Category.Title,
-//			&equipmentByLoc.Company.ID,
-//			&equipmentByLoc.Company.Title,
-//		); err != nil {
-//			return nil, err
-//		}
-//		equipmentsByLoc = append(equipmentsByLoc, equipmentByLoc)
-//	}
-//
-//	return equipmentsByLoc, nil
-//}
-//
-//// GetByLocationDepartment is equipment get by location department
-//func (r *LocationRepository) GetByLocationDepartment(ctx context.Context, toDepartment int64) ([]*model.Location, error) {
-//	var equipmentsByLoc []*model.Location
-//
-//	const query = `
-//		SELECT equipments.id, equipments.serial_number,
-//		       profiles.title,
-//		       categories.title,
-//		       companies.id, companies.title,
-//		       to_department.id, to_department.title,
-//		       to_employee.id, to_employee.name
-//		FROM locations
-//		LEFT JOIN equipments ON equipments.id = locations.equipment
-//		LEFT JOIN profiles ON profiles.id = equipments.profile
-//		LEFT JOIN categories ON categories.id = profiles.category
-//		LEFT JOIN companies ON companies.id = locations.company
-//		LEFT JOIN departments to_department ON to_department.id = locations.to_department
-//		LEFT JOIN employees to_employee ON to_employee.id = locations.to_employee
-//		WHERE locations.id IN
-//		      (SELECT MAX(locations.id)
-//		       FROM locations
-//		       GROUP BY locations.equipment)
-//		AND locations.to_department = $1
-//		AND equipments.deleted = FALSE
-//		ORDER BY profiles.title,
-//		         equipments.serial_number;`
-//
-//	rows, err := r.DB.Query(ctx, query, toDepartment)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		equipmentByLoc := newLocation()
-//		var (
-//			toDepartmentId, toEmployeeId      pgtype.Int8
-//			toDepartmentTitle, toEmployeeName pgtype.Text
-//		)
-//
-//		if err := rows.Scan(
-//			&equipmentByLoc.Equipment.ID,
-//			&equipmentByLoc.Equipment.SerialNumber,
-//			&equipmentByLoc.Equipment.Profile.Title,
-//			&equipmentByLoc.Equipment.Profile.Category.Title,
-//			&equipmentByLoc.Company.ID,
-//			&equipmentByLoc.Company.Title,
-//			&toDepartmentId,
-//			&toDepartmentTitle,
-//			&toEmployeeId,
-//			&toEmployeeName,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		equipmentByLoc.ToDepartment.ID = validInt64(toDepartmentId)
-//		equipmentByLoc.ToDepartment.Title = validString(toDepartmentTitle)
-//		equipmentByLoc.ToEmployee.ID = validInt64(toEmployeeId)
-//		equipmentByLoc.ToEmployee.FirstName = validString(toEmployeeName)
-//
-//		equipmentsByLoc = append(equipmentsByLoc, equipmentByLoc)
-//	}
-//
-//	return equipmentsByLoc, nil
-//}
-//
-//// GetByLocationEmployee is equipment get by location employee
-//func (r *LocationRepository) GetByLocationEmployee(ctx context.Context, toEmployee int64) ([]*model.Location, error) {
-//	var equipmentsByLoc []*model.Location
-//
-//	const query = `
-//		SELECT equipments.id, equipments.serial_number,
-//		       profiles.title,
-//		       categories.title,
-//		       companies.id, companies.title,
-//		       to_department.title,
-//		       to_employee.name
-//		FROM locations
-//		LEFT JOIN equipments ON equipments.id = locations.equipment
-//		LEFT JOIN profiles ON profiles.id = equipments.profile
-//		LEFT JOIN categories ON categories.id = profiles.category
-//		LEFT JOIN companies ON companies.id = locations.company
-//		LEFT JOIN departments to_department ON to_department.id = locations.to_department
-//		LEFT JOIN employees to_employee ON to_employee.id = locations.to_employee
-//		WHERE locations.id IN
-//		      (SELECT MAX(locations.id)
-//		       FROM locations
-//		       GROUP BY locations.equipment)
-//		AND locations.to_employee = $1
-//		AND equipments.deleted = FALSE
-//		ORDER BY profiles.title,
-//		         equipments.serial_number;`
-//
-//	rows, err := r.DB.Query(ctx, query, toEmployee)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		equipmentByLoc := newLocation()
-//		var toDepartmentTitle, toEmployeeName pgtype.Text
-//
-//		if err := rows.Scan(
-//			&equipmentByLoc.Equipment.ID,
-//			&equipmentByLoc.Equipment.SerialNumber,
-//			&equipmentByLoc.Equipment.Profile.Title,
-//			&equipmentByLoc.Equipment.Profile.Category.Title,
-//			&equipmentByLoc.Company.ID,
-//			&equipmentByLoc.Company.Title,
-//			&toDepartmentTitle,
-//			&toEmployeeName,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		equipmentByLoc.ToDepartment.Title = validString(toDepartmentTitle)
-//		equipmentByLoc.ToEmployee.FirstName = validString(toEmployeeName)
-//
-//		equipmentsByLoc = append(equipmentsByLoc, equipmentByLoc)
-//	}
-//
-//	return equipmentsByLoc, nil
-//}
-//
-//// GetByLocationContract is equipment get by location contract
-//func (r *LocationRepository) GetByLocationContract(ctx context.Context, toContract int64) ([]*model.Location, error) {
-//	var equipmentsByLoc []*model.Location
-//
-//	const query = `
-//		SELECT equipments.id, equipments.serial_number,
-//		       profiles.title,
-//		       categories.title,
-//		       companies.id, companies.title
-//		FROM locations
-//		LEFT JOIN equipments ON equipments.id = locations.equipment
-//		LEFT JOIN profiles ON profiles.id = equipments.profile
-//		LEFT JOIN categories ON categories.id = profiles.category
-//		LEFT JOIN companies ON companies.id = locations.company
-//		LEFT JOIN departments ON departments.id = locations.to_department
-//		LEFT JOIN employees ON employees.id = locations.to_employee
-//		LEFT JOIN contracts ON contracts.id = locations.to_contract
-//		WHERE locations.id IN
-//		      (SELECT MAX(locations.id)
-//		       FROM locations
-//		       GROUP BY locations.equipment)
-//		AND locations.to_department IS NULL
-//		AND locations.to_employee IS NULL
-//		AND locations.to_contract = $1
-//		AND equipments.deleted = FALSE
-//		ORDER BY profiles.title,
-//		         equipments.serial_number;`
-//
-//	rows, err := r.DB.Query(ctx, query, toContract)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		equipmentByLoc := newLocation()
-//
-//		if err := rows.Scan(
-//			&equipmentByLoc.Equipment.ID,
-//			&equipmentByLoc.Equipment.SerialNumber,
-//			&equipmentByLoc.Equipment.Profile.Title,
-//			&equipmentByLoc.Equipment.Profile.Category.Title,
-//			&equipmentByLoc.Company.ID,
-//			&equipmentByLoc.Company.Title,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		equipmentsByLoc = append(equipmentsByLoc, equipmentByLoc)
-//	}
-//
-//	return equipmentsByLoc, nil
-//}
-//
-//// GetByLocationDepartmentEmployee is equipment get by location department employee
-//func (r *LocationRepository) GetByLocationDepartmentEmployee(ctx context.Context, toDepartment, toEmployee int64) ([]*model.Location, error) {
-//	var equipmentsByLoc []*model.Location
-//
-//	const query = `
-//		SELECT equipments.id, equipments.serial_number,
-//		       profiles.title,
-//		       categories.title,
-//		       companies.id, companies.title,
-//		       to_department.title,
-//		       to_employee.name
-//		FROM locations
-//		LEFT JOIN equipments ON equipments.id = locations.equipment
-//		LEFT JOIN profiles ON profiles.id = equipments.profile
-//		LEFT JOIN categories ON categories.id = profiles.category
-//		LEFT JOIN companies ON companies.id = locations.company
-//		LEFT JOIN departments to_department ON to_department.id = locations.to_department
-//		LEFT JOIN employees to_employee ON to_employee.id = locations.to_employee
-//		WHERE locations.id IN
-//		      (SELECT MAX(locations.id)
-//		       FROM locations
-//		       GROUP BY locations.equipment)
-//		AND locations.to_department = $1
-//		AND locations.to_employee = $2
-//		AND equipments.deleted = FALSE
-//		ORDER BY profiles.title,
-//		         equipments.serial_number;`
-//
-//	rows, err := r.DB.Query(ctx, query, toDepartment, toEmployee)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		equipmentByLoc := newLocation()
-//		var toDepartmentTitle, toEmployeeName pgtype.Text
-//
-//		if err := rows.Scan(
-//			&equipmentByLoc.Equipment.ID,
-//			&equipmentByLoc.Equipment.SerialNumber,
-//			&equipmentByLoc.Equipment.Profile.Title,
-//			&equipmentByLoc.Equipment.Profile.Category.Title,
-//			&equipmentByLoc.Company.ID,
-//			&equipmentByLoc.Company.Title,
-//			&toDepartmentTitle,
-//			&toEmployeeName,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		equipmentByLoc.ToDepartment.Title = validString(toDepartmentTitle)
-//		equipmentByLoc.ToEmployee.FirstName = validString(toEmployeeName)
-//
-//		equipmentsByLoc = append(equipmentsByLoc, equipmentByLoc)
-//	}
-//
-//	return equipmentsByLoc, nil
-//}
-//
-//// RemainderByCategory is remainder equipment get by category
-//func (r *LocationRepository) RemainderByCategory(ctx context.Context, categoryId, departmentId int64, date *time.Time) ([]*model.Location, error) {
-//	var locations []*model.Location
-//
-//	const query = `
-//		SELECT locations.date,
-//		       profiles.title,
-//		       equipments.serial_number
-//		FROM locations
-//		LEFT JOIN equipments ON locations.equipment = equipments.id
-//		LEFT JOIN profiles ON equipments.profile = profiles.id
-//		LEFT JOIN departments ON departments.id = locations.to_department
-//		WHERE locations.id IN
-//		      (SELECT MAX(locations.id)
-//			   FROM locations
-//			   WHERE locations.date < $3
-//			   GROUP BY locations.equipment)
-//		AND profiles.category = $1
-//		AND locations.to_department = $2
-//		AND locations.date < $3;`
-//
-//	rows, err := r.DB.Query(ctx, query, categoryId, departmentId, date)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		location := newLocation()
-//
-//		if err := rows.Scan(
-//			&location.Date,
-//			&location.Equipment.Profile.Title,
-//			&location.Equipment.SerialNumber,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		locations = append(locations, location)
-//	}
-//
-//	return locations, nil
-//}
-//
-//// TransferByCategory is transfer equipment get by category
-//func (r *LocationRepository) TransferByCategory(ctx context.Context, categoryId, departmentId int64, fromDate, toDate *time.Time, code string) ([]*model.Location, error) {
-//	var locations []*model.Location
-//	var query string
-//
-//	switch {
-//	case code == "STORAGE_TO_DEPARTMENT" || code == "CONTRACT_TO_DEPARTMENT":
-//		query = `
-//			SELECT locations.date,
-//			       profiles.title,
-//			       equipments.serial_number
-//			FROM locations
-//			LEFT JOIN equipments ON locations.equipment = equipments.id
-//			LEFT JOIN profiles ON equipments.profile = profiles.id
-//			WHERE profiles.category = $1
-//			AND locations.to_department = $2
-//			AND locations.code = $3
-//			AND locations.date >= $4
-//			AND locations.date < $5;`
-//	case code == "DEPARTMENT_TO_STORAGE" || code == "DEPARTMENT_TO_CONTRACT":
-//		query = `
-//			SELECT locations.date,
-//			       profiles.title,
-//			       equipments.serial_number
-//			FROM locations
-//			LEFT JOIN equipments ON locations.equipment = equipments.id
-//			LEFT JOIN profiles ON equipments.profile = profiles.id
-//			WHERE profiles.category = $1
-//			AND locations.from_department = $2
-//			AND locations.code = $3
-//			AND locations.date >= $4
-//			AND locations.date < $5;`
-//	}
-//
-//	rows, err := r.DB.Query(ctx, query, categoryId, departmentId, code, fromDate, toDate)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		location := newLocation()
-//
-//		if err := rows.Scan(
-//			&location.Date,
-//			&location.Equipment.Profile.Title,
-//			&location.Equipment.SerialNumber,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		locations = append(locations, location)
-//	}
-//
-//	return locations, nil
-//}
-//
-//// ToDepartmentTransferByCategory is transfer equipment to department by category
-//func (r *LocationRepository) ToDepartmentTransferByCategory(ctx context.Context, categoryId, departmentId int64, fromDate, toDate *time.Time) ([]*model.Location, error) {
-//	var locations []*model.Location
-//
-//	const query = `
-//		SELECT locations.date,
-//		       profiles.title,
-//		       equipments.serial_number,
-//		       from_department.id, from_department.title
-//		FROM locations
-//		LEFT JOIN equipments ON locations.equipment = equipments.id
-//		LEFT JOIN profiles ON equipments.profile = profiles.id
-//		LEFT JOIN departments from_department ON from_department.id = locations.from_department
-//		WHERE profiles.category = $1
-//		AND locations.to_department = $2
-//		AND locations.code = 'DEPARTMENT_TO_DEPARTMENT'
-//		AND locations.date >= $3
-//		AND locations.date < $4;`
-//
-//	rows, err := r.DB.Query(ctx, query, categoryId, departmentId, fromDate, toDate)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		location := newLocation()
-//		var (
-//			fromDepartmentId    pgtype.Int8
-//			fromDepartmentTitle pgtype.Text
-//		)
-//
-//		if err := rows.Scan(
-//			&location.Date,
-//			&location.Equipment.Profile.Title,
-//			&location.Equipment.SerialNumber,
-//			&fromDepartmentId,
-//			&fromDepartmentTitle,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		location.ToDepartment.ID = validInt64(fromDepartmentId)
-//		location.ToDepartment.Title = validString(fromDepartmentTitle)
-//
-//		locations = append(locations, location)
-//	}
-//
-//	return locations, nil
-//}
-//
-//// FromDepartmentTransferByCategory is transfer equipment from department by category
-//func (r *LocationRepository) FromDepartmentTransferByCategory(ctx context.Context, categoryId, departmentId int64, fromDate, toDate *time.Time) ([]*model.Location, error) {
-//	var locations []*model.Location
-//
-//	const query = `
-//		SELECT locations.date,
-//		       profiles.title,
-//		       equipments.serial_number,
-//		       to_department.id, to_department.title
-//		FROM locations
-//		LEFT JOIN equipments ON locations.equipment = equipments.id
-//		LEFT JOIN profiles ON equipments.profile = profiles.id
-//		LEFT JOIN departments to_department ON to_department.id = locations.to_department
-//		WHERE profiles.category = $1
-//		AND locations.from_department = $2
-//		AND locations.code = 'DEPARTMENT_TO_DEPARTMENT'
-//		AND locations.date >= $3
-//		AND locations.date < $4;`
-//
-//	rows, err := r.DB.Query(ctx, query, categoryId, departmentId, fromDate, toDate)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	for rows.Next() {
-//		location := newLocation()
-//		var (
-//			toDepartmentId    pgtype.Int8
-//			toDepartmentTitle pgtype.Text
-//		)
-//
-//		if err := rows.Scan(
-//			&location.Date,
-//			&location.Equipment.Profile.Title,
-//			&location.Equipment.SerialNumber,
-//			&toDepartmentId,
-//			&toDepartmentTitle,
-//		); err != nil {
-//			return nil, err
-//		}
-//
-//		location.ToDepartment.ID = validInt64(toDepartmentId)
-//		location.ToDepartment.Title = validString(toDepartmentTitle)
-//
-//		locations = append(locations, location)
-//	}
-//
-//	return locations, nil
-//}
-//
 //func newLocation() *model.Location {
 //	return &model.Location{
 //		Equipment: &model.Equipment{
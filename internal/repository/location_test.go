@@ -0,0 +1,907 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/generate"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func truncateUsers(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE users
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate user: %v", err)
+	}
+}
+
+func truncateLocations(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE location_reversals, locations, equipments, profiles, categories, companies, departments, users
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate location: %v", err)
+	}
+}
+
+func addTestUser(t *testing.T, testDB *pgxpool.Pool) *model.User {
+	t.Helper()
+	u := new(model.User)
+
+	const query = `
+		INSERT INTO users (username, password_hash, email, role)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, username, email, role;`
+
+	if err := testDB.QueryRow(t.Context(), query, generate.RandString(10), generate.RandString(10), generate.RandString(10), role.EmployeeRole).
+		Scan(&u.ID, &u.Username, &u.Email, &u.Role); err != nil {
+		t.Fatalf("failed to insert test user: %v", err)
+	}
+
+	return u
+}
+
+func addTestLocation(t *testing.T, testDB *pgxpool.Pool, equipmentID, userID, toDepartmentID int64) *model.Location {
+	t.Helper()
+	l := &model.Location{
+		Equipment: &model.Equipment{ID: equipmentID},
+		User:      &model.User{ID: userID},
+		MoveCode:  "AddToStorage",
+	}
+
+	var toDepartment pgtype.Int8
+	if toDepartmentID != 0 {
+		toDepartment = pgtype.Int8{Int64: toDepartmentID, Valid: true}
+		l.MoveCode = "StorageToDepartment"
+		l.ToDepartment = &model.Department{ID: toDepartmentID}
+	}
+
+	const query = `
+		INSERT INTO locations (equipment_id, user_id, move_at, move_code, to_department_id)
+		VALUES ($1, $2, now(), $3, $4)
+		RETURNING id, move_at;`
+
+	var moveAt pgtype.Timestamptz
+	if err := testDB.QueryRow(t.Context(), query, equipmentID, userID, l.MoveCode, toDepartment).
+		Scan(&l.ID, &moveAt); err != nil {
+		t.Fatalf("failed to insert test location: %v", err)
+	}
+	l.MoveAt = validTime(moveAt)
+
+	return l
+}
+
+func TestNewLocationRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *LocationRepository
+	}{
+		{
+			name: "create location repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewLocationRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLocationRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewLocationRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_MoveMany(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	de := addTestDeletedEquipment(t, testDB)
+	addTestLocation(t, testDB, de.ID, u.ID, 0)
+	be := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, be.ID, u.ID, 0)
+
+	date := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	backDate := pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx       context.Context
+		locations []*queries.MoveToLocationParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "move equipment from storage to department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				locations: []*queries.MoveToLocationParams{
+					{
+						EquipmentID:    e.ID,
+						UserID:         u.ID,
+						MoveAt:         date,
+						MoveCode:       "StorageToDepartment",
+						ToDepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+					},
+				},
+			},
+			want:    []int64{4},
+			wantErr: false,
+		},
+		{
+			name: "move equipment before its latest location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				locations: []*queries.MoveToLocationParams{
+					{
+						EquipmentID:    be.ID,
+						UserID:         u.ID,
+						MoveAt:         backDate,
+						MoveCode:       "StorageToDepartment",
+						ToDepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "move deleted equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				locations: []*queries.MoveToLocationParams{
+					{
+						EquipmentID:    de.ID,
+						UserID:         u.ID,
+						MoveAt:         date,
+						MoveCode:       "StorageToDepartment",
+						ToDepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "move equipment from stale location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				locations: []*queries.MoveToLocationParams{
+					{
+						EquipmentID: e.ID,
+						UserID:      u.ID,
+						MoveAt:      date,
+						MoveCode:    "StorageToStorage",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "move non-existing equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				locations: []*queries.MoveToLocationParams{
+					{
+						EquipmentID: 999,
+						UserID:      u.ID,
+						MoveAt:      date,
+						MoveCode:    "StorageToStorage",
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LocationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.MoveMany(tt.args.ctx, tt.args.locations)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MoveMany() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MoveMany() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_Revert(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateOutboxEvents(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	first := addTestLocation(t, testDB, e.ID, u.ID, 0)
+	last := addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+	se := addTestEquipment(t, testDB)
+	stale := addTestLocation(t, testDB, se.ID, u.ID, 0)
+	addTestLocation(t, testDB, se.ID, u.ID, d.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx        context.Context
+		locationID int64
+		revertedBy int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "revert last location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:        t.Context(),
+				locationID: last.ID,
+				revertedBy: u.ID,
+			},
+			want:    []int64{last.ID},
+			wantErr: false,
+		},
+		{
+			name: "revert not last location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:        t.Context(),
+				locationID: stale.ID,
+				revertedBy: u.ID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "revert already reverted location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:        t.Context(),
+				locationID: last.ID,
+				revertedBy: u.ID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "revert location after previous revert",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:        t.Context(),
+				locationID: first.ID,
+				revertedBy: u.ID,
+			},
+			want:    []int64{first.ID},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LocationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Revert(tt.args.ctx, tt.args.locationID, tt.args.revertedBy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Revert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Revert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := countTestOutboxEvents(t, testDB, model.EventEquipmentMoveReverted, e.ID); got != 2 {
+		t.Errorf("Revert() events = %d, want %d", got, 2)
+	}
+}
+
+func TestLocationRepository_GetLast(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	l := addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx         context.Context
+		equipmentID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Location
+		wantErr bool
+	}{
+		{
+			name: "get last location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e.ID,
+			},
+			want:    l,
+			wantErr: false,
+		},
+		{
+			name: "get last location of non-existing equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: 999,
+			},
+			want: &model.Location{
+				Equipment: &model.Equipment{ID: 999},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LocationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.GetLast(tt.args.ctx, tt.args.equipmentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetLast() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLast() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_Current(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	l := addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+	l.User.Username = u.Username
+	l.ToDepartment.Title = d.Title
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx         context.Context
+		equipmentID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Location
+		wantErr bool
+	}{
+		{
+			name: "get current location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e.ID,
+			},
+			want:    l,
+			wantErr: false,
+		},
+		{
+			name: "get current location of non-existing equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: 999,
+			},
+			want:    nil,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LocationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Current(tt.args.ctx, tt.args.equipmentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Current() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Current() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_History(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	first := addTestLocation(t, testDB, e.ID, u.ID, 0)
+	second := addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+	for _, l := range []*model.Location{first, second} {
+		l.User.Username = u.Username
+	}
+	second.ToDepartment.Title = d.Title
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx         context.Context
+		equipmentID int64
+		qp          *dto.QueryParams
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      []*model.Location
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name: "history of equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e.ID,
+				qp: &dto.QueryParams{
+					PaginationLimit:  10,
+					PaginationOffset: 0,
+				},
+			},
+			want:      []*model.Location{first, second},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name: "history of equipment with offset",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: e.ID,
+				qp: &dto.QueryParams{
+					PaginationLimit:  10,
+					PaginationOffset: 1,
+				},
+			},
+			want:      []*model.Location{second},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name: "history of non-existing equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				equipmentID: 999,
+				qp: &dto.QueryParams{
+					PaginationLimit:  10,
+					PaginationOffset: 0,
+				},
+			},
+			want:      []*model.Location{},
+			wantTotal: 0,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LocationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, total, err := r.History(tt.args.ctx, tt.args.equipmentID, tt.args.qp)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("History() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("History() got = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("History() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_List(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		truncateEmployees(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	emp := addTestEmployee(t, testDB)
+	held := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, held.ID, u.ID, 0)
+	stored := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, stored.ID, u.ID, 0)
+
+	if _, err := NewLocationRepository(testDB).Move(t.Context(), &queries.MoveToLocationParams{
+		EquipmentID:  held.ID,
+		UserID:       u.ID,
+		MoveAt:       pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		MoveCode:     "StorageToEmployee",
+		ToEmployeeID: pgtype.Int8{Int64: emp.ID, Valid: true},
+	}); err != nil {
+		t.Fatalf("failed to move test equipment: %v", err)
+	}
+
+	listed := func(e *model.Equipment) *model.Equipment {
+		return &model.Equipment{
+			ID:           e.ID,
+			SerialNumber: e.SerialNumber,
+			Company: &model.Company{
+				Title: e.Company.Title,
+			},
+			Profile: &model.Profile{
+				Title: e.Profile.Title,
+				Category: &model.Category{
+					Title: e.Profile.Category.Title,
+				},
+			},
+		}
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx context.Context
+		qp  *dto.QueryParams
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      []*model.Equipment
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name: "list equipment held by employee",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				qp: &dto.QueryParams{
+					Param:           "employee",
+					ParamID:         emp.ID,
+					SortColumn:      "id",
+					SortOrder:       "asc",
+					PaginationLimit: 10,
+				},
+			},
+			want:      []*model.Equipment{listed(held)},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name: "list equipment in storage",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				qp: &dto.QueryParams{
+					SortColumn:      "id",
+					SortOrder:       "asc",
+					PaginationLimit: 10,
+				},
+			},
+			want:      []*model.Equipment{listed(stored)},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name: "list equipment in storage with search",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				qp: &dto.QueryParams{
+					Search:          held.SerialNumber,
+					SortColumn:      "id",
+					SortOrder:       "asc",
+					PaginationLimit: 10,
+				},
+			},
+			want:      []*model.Equipment{},
+			wantTotal: 0,
+			wantErr:   false,
+		},
+		{
+			name: "list equipment installed on contract",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				qp: &dto.QueryParams{
+					Param:           "contract",
+					ParamID:         999,
+					SortColumn:      "id",
+					SortOrder:       "asc",
+					PaginationLimit: 10,
+				},
+			},
+			want:      []*model.Equipment{},
+			wantTotal: 0,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LocationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, total, err := r.List(tt.args.ctx, tt.args.qp)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() got = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("List() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_DepartmentBalance(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateRepairOrders(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+	rd := addTestDepartment(t, testDB)
+	re := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, re.ID, u.ID, 0)
+	addTestLocation(t, testDB, re.ID, u.ID, rd.ID)
+	repairs := NewRepairOrderRepository(testDB)
+	order := addTestRepairOrder(t, testDB, re.ID, u.ID, rd.ID)
+	if err := repairs.Update(t.Context(), &queries.UpdateRepairOrderParams{
+		Status:        model.RepairRepaired,
+		ID:            order,
+		CurrentStatus: model.RepairSent,
+	}); err != nil {
+		t.Fatalf("failed to update test repair order: %v", err)
+	}
+	if _, err := repairs.Return(t.Context(), order, u.ID, testRepairMove(re.ID, u.ID, model.ReturnedFromRepair, rd.ID)); err != nil {
+		t.Fatalf("failed to return test repair order: %v", err)
+	}
+
+	now := time.Now()
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx    context.Context
+		params *queries.DepartmentBalanceReportParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*model.DepartmentBalanceRow
+		wantErr bool
+	}{
+		{
+			name: "balance of department within range",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				params: &queries.DepartmentBalanceReportParams{
+					DateFrom:     pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+					DateTo:       pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+					DepartmentID: d.ID,
+				},
+			},
+			want: []*model.DepartmentBalanceRow{
+				{
+					Category:    e.Profile.Category,
+					FromStorage: 1,
+					Closing:     1,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "balance of department after range",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				params: &queries.DepartmentBalanceReportParams{
+					DateFrom:     pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+					DateTo:       pgtype.Timestamptz{Time: now.Add(2 * time.Hour), Valid: true},
+					DepartmentID: d.ID,
+				},
+			},
+			want: []*model.DepartmentBalanceRow{
+				{
+					Category: e.Profile.Category,
+					Opening:  1,
+					Closing:  1,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "balance of department with repair round trip",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				params: &queries.DepartmentBalanceReportParams{
+					DateFrom:     pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+					DateTo:       pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+					DepartmentID: rd.ID,
+				},
+			},
+			want: []*model.DepartmentBalanceRow{
+				{
+					Category:    re.Profile.Category,
+					FromStorage: 1,
+					FromRepair:  1,
+					ToRepair:    1,
+					Closing:     1,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "balance of non-existing department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				params: &queries.DepartmentBalanceReportParams{
+					DateFrom:     pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+					DateTo:       pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+					DepartmentID: 999,
+				},
+			},
+			want:    []*model.DepartmentBalanceRow{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LocationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.DepartmentBalance(tt.args.ctx, tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DepartmentBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DepartmentBalance() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/generate"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/kafka"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type OutboxRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewOutboxRepository(postgresDB *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{postgresDB: postgresDB}
+}
+
+// Relay publishes the oldest pending events, at most batchSize of them, and
+// marks them published. A failed batch is retried as a whole after a growing
+// delay, newer events wait for it so that the order is kept. An event can be
+// published twice if the commit fails after Publish.
+func (r *OutboxRepository) Relay(ctx context.Context, publisher kafka.Publisher, batchSize int32) (int, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	events, err := q.LockPendingOutboxEvents(ctx, batchSize)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if len(events) < 1 || events[0].NextAttemptAt.Time.After(time.Now()) {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(events))
+	messages := make([]*kafka.Message, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+		messages[i] = &kafka.Message{
+			Key:   strconv.FormatInt(event.EntityID, 10),
+			Value: event.Payload,
+		}
+	}
+
+	if publishErr := publisher.Publish(ctx, messages...); publishErr != nil {
+		if err := q.SetOutboxEventsFailed(ctx, &queries.SetOutboxEventsFailedParams{
+			LastError: pgtype.Text{String: publishErr.Error(), Valid: true},
+			Ids:       ids,
+		}); err != nil {
+			return 0, logger.Error(logger.MsgFailedToUpdate, err)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return 0, logger.Error("", err)
+		}
+
+		return 0, publishErr
+	}
+
+	if err := q.SetOutboxEventsPublished(ctx, ids); err != nil {
+		return 0, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return len(events), nil
+}
+
+// addOutboxEvent wraps the payload into the current event envelope and
+// stores it in the transaction of the change, the entity id becomes the
+// message key.
+func addOutboxEvent(ctx context.Context, q *queries.Queries, event *model.Event) error {
+	event.Version = model.EventVersion
+	event.ID = generate.UUID()
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if event.Payload == nil {
+		event.Payload = &model.EventPayload{}
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return logger.Error(logger.MsgFailedToMarshal, err)
+	}
+
+	if err := q.CreateOutboxEvent(ctx, &queries.CreateOutboxEventParams{
+		EventType: event.Type,
+		EntityID:  event.EntityID,
+		Payload:   data,
+	}); err != nil {
+		return logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	return nil
+}
+
+// addEquipmentEvent stores an equipment event with the profile, category
+// and company of the equipment added to the payload.
+func addEquipmentEvent(ctx context.Context, q *queries.Queries, eventType string, equipmentID, userID int64, occurredAt pgtype.Timestamptz, payload *model.EventPayload) error {
+	dimensions, err := q.GetEquipmentDimensions(ctx, equipmentID)
+	if err != nil {
+		return logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	payload.ProfileID = &dimensions.ProfileID
+	payload.CategoryID = &dimensions.CategoryID
+	payload.CompanyID = &dimensions.CompanyID
+
+	return addOutboxEvent(ctx, q, &model.Event{
+		Type:       eventType,
+		OccurredAt: occurredAt.Time,
+		UserID:     userID,
+		EntityType: model.EntityEquipment,
+		EntityID:   equipmentID,
+		Payload:    payload,
+	})
+}
+
+// addCreateEvent stores the creation of an equipment, location holds its
+// first move to the storage.
+func addCreateEvent(ctx context.Context, q *queries.Queries, location *queries.AddToStorageParams) error {
+	return addEquipmentEvent(ctx, q, model.EventEquipmentCreated, location.EquipmentID, location.UserID, location.MoveAt, &model.EventPayload{
+		MoveCode: &location.MoveCode,
+	})
+}
+
+// addMoveEvent stores a move, the final move of a write-off is stored as
+// the write-off.
+func addMoveEvent(ctx context.Context, q *queries.Queries, id int64, location *queries.MoveToLocationParams) error {
+	eventType := model.EventEquipmentMoved
+	if location.MoveCode == model.WrittenOff {
+		eventType = model.EventEquipmentWrittenOff
+	}
+
+	return addEquipmentEvent(ctx, q, eventType, location.EquipmentID, location.UserID, location.MoveAt, &model.EventPayload{
+		LocationID:       &id,
+		MoveCode:         &location.MoveCode,
+		FromDepartmentID: validInt64Pointer(location.FromDepartmentID),
+		FromEmployeeID:   validInt64Pointer(location.FromEmployeeID),
+		FromContractID:   validInt64Pointer(location.FromContractID),
+		ToDepartmentID:   validInt64Pointer(location.ToDepartmentID),
+		ToEmployeeID:     validInt64Pointer(location.ToEmployeeID),
+		ToContractID:     validInt64Pointer(location.ToContractID),
+	})
+}
+
+// addRevertEvent stores the revert of a move, the payload repeats the
+// reverted move so that a consumer can take it back.
+func addRevertEvent(ctx context.Context, q *queries.Queries, location *queries.Location, revertedBy int64) error {
+	return addEquipmentEvent(ctx, q, model.EventEquipmentMoveReverted, location.EquipmentID, revertedBy, pgtype.Timestamptz{}, &model.EventPayload{
+		LocationID:       &location.ID,
+		MoveCode:         &location.MoveCode,
+		FromDepartmentID: validInt64Pointer(location.FromDepartmentID),
+		FromEmployeeID:   validInt64Pointer(location.FromEmployeeID),
+		FromContractID:   validInt64Pointer(location.FromContractID),
+		ToDepartmentID:   validInt64Pointer(location.ToDepartmentID),
+		ToEmployeeID:     validInt64Pointer(location.ToEmployeeID),
+		ToContractID:     validInt64Pointer(location.ToContractID),
+	})
+}
+
+// addMergeEvent stores the merge of the duplicate into the equipment, the
+// duplicate stops being counted.
+func addMergeEvent(ctx context.Context, q *queries.Queries, merge *queries.EquipmentMerge) error {
+	return addEquipmentEvent(ctx, q, model.EventEquipmentMerged, merge.EquipmentID, merge.MergedBy, merge.MergedAt, &model.EventPayload{
+		DuplicateID: &merge.DuplicateID,
+	})
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/generate"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/kafka"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func truncateOutboxEvents(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE outbox_events
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate outbox event: %v", err)
+	}
+}
+
+// addTestOutboxEvent creates equipment through the repository, which stores
+// an equipment.created event, and returns the equipment id.
+func addTestOutboxEvent(t *testing.T, testDB *pgxpool.Pool, userID int64) int64 {
+	t.Helper()
+	c := addTestCompany(t, testDB)
+	p := addTestProfile(t, testDB)
+
+	id, err := NewEquipmentRepository(testDB).Create(t.Context(), &queries.CreateEquipmentParams{
+		SerialNumber: generate.RandString(10),
+		ProfileID:    p.ID,
+		CompanyID:    c.ID,
+	}, &queries.AddToStorageParams{
+		UserID:   userID,
+		MoveAt:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		MoveCode: "AddToStorage",
+	})
+	if err != nil {
+		t.Fatalf("failed to insert test outbox event: %v", err)
+	}
+
+	return id
+}
+
+func countTestOutboxEvents(t *testing.T, testDB *pgxpool.Pool, eventType string, entityID int64) int64 {
+	t.Helper()
+
+	const query = `
+		SELECT count(*)
+		FROM outbox_events
+		WHERE event_type = $1
+		  AND entity_id = $2;`
+
+	var count int64
+	if err := testDB.QueryRow(t.Context(), query, eventType, entityID).Scan(&count); err != nil {
+		t.Fatalf("failed to count test outbox events: %v", err)
+	}
+
+	return count
+}
+
+func TestNewOutboxRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *OutboxRepository
+	}{
+		{
+			name: "create outbox repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewOutboxRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewOutboxRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewOutboxRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboxRepository_Relay(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateOutboxEvents(t, testDB)
+		truncateLocations(t, testDB)
+		truncateEquipments(t, testDB)
+		testDB.Close()
+	})
+	// other tests leave their events behind
+	truncateOutboxEvents(t, testDB)
+	u := addTestUser(t, testDB)
+	published := kafka.NewStub()
+	failing := kafka.NewStub()
+	failing.Err = errors.New("broker not available")
+
+	equipmentID := addTestOutboxEvent(t, testDB, u.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx       context.Context
+		publisher kafka.Publisher
+		batchSize int32
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wait     time.Duration
+		want     int
+		wantKeys []string
+		wantErr  bool
+	}{
+		{
+			name: "fail to publish",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				publisher: failing,
+				batchSize: 10,
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "wait before retry",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				publisher: published,
+				batchSize: 10,
+			},
+			want:     0,
+			wantKeys: nil,
+			wantErr:  false,
+		},
+		{
+			name: "publish after delay",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				publisher: published,
+				batchSize: 10,
+			},
+			wait:     1500 * time.Millisecond,
+			want:     1,
+			wantKeys: []string{strconv.FormatInt(equipmentID, 10)},
+			wantErr:  false,
+		},
+		{
+			name: "nothing pending",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				publisher: published,
+				batchSize: 10,
+			},
+			want:     0,
+			wantKeys: []string{strconv.FormatInt(equipmentID, 10)},
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			time.Sleep(tt.wait)
+			r := &OutboxRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Relay(tt.args.ctx, tt.args.publisher, tt.args.batchSize)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Relay() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Relay() got = %v, want %v", got, tt.want)
+			}
+			var keys []string
+			for _, m := range published.Messages() {
+				keys = append(keys, m.Key)
+
+				var event model.Event
+				if err := json.Unmarshal(m.Value, &event); err != nil {
+					t.Fatalf("failed to unmarshal event: %v", err)
+				}
+				if event.Version != model.EventVersion || event.Type != model.EventEquipmentCreated ||
+					event.UserID != u.ID || strconv.FormatInt(event.EntityID, 10) != m.Key ||
+					event.Payload == nil || event.Payload.CategoryID == nil || event.Payload.CompanyID == nil {
+					t.Errorf("Relay() event = %+v, want equipment.created envelope with dimensions", event)
+				}
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("Relay() keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
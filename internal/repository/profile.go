@@ -23,6 +23,7 @@ func (r *ProfileRepository) Create(ctx context.Context, profile *model.Profile)
 	req, err := r.queries.CreateProfile(ctx, &queries.CreateProfileParams{
 		Title:      profile.Title,
 		CategoryID: profile.Category.ID,
+		SerialRule: serialRule(profile.SerialRule),
 	})
 	if err != nil {
 		return 0, logger.Error(logger.MsgFailedToInsert, err)
@@ -44,7 +45,8 @@ func (r *ProfileRepository) Read(ctx context.Context, id int64) (*model.Profile,
 			ID:    req.CategoryID,
 			Title: req.CategoryTitle,
 		},
-		DeletedAt: validTime(req.DeletedAt),
+		SerialRule: validStrings(req.SerialRule),
+		DeletedAt:  validTime(req.DeletedAt),
 	}
 
 	return profile, nil
@@ -55,6 +57,7 @@ func (r *ProfileRepository) Update(ctx context.Context, profile *model.Profile)
 		ID:         profile.ID,
 		Title:      profile.Title,
 		CategoryID: profile.Category.ID,
+		SerialRule: serialRule(profile.SerialRule),
 	})
 	if err != nil {
 		return logger.Error(logger.MsgFailedToUpdate, err)
@@ -120,10 +123,48 @@ func (r *ProfileRepository) List(ctx context.Context, qp *dto.QueryParams) ([]*m
 				ID:    item.CategoryID,
 				Title: item.CategoryTitle,
 			},
-			DeletedAt: validTime(item.DeletedAt),
+			SerialRule: validStrings(item.SerialRule),
+			DeletedAt:  validTime(item.DeletedAt),
 		}
 		list[i] = profile
 	}
 
 	return list, req[0].Total, nil
 }
+
+func (r *ProfileRepository) IDsByTitles(ctx context.Context, titles []string) (map[string]int64, error) {
+	req, err := r.queries.ListProfileByTitles(ctx, titles)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	ids := make(map[string]int64, len(req))
+	for _, item := range req {
+		ids[item.Title] = item.ID
+	}
+
+	return ids, nil
+}
+
+func (r *ProfileRepository) SerialRules(ctx context.Context, ids []int64) (map[int64][]string, error) {
+	req, err := r.queries.ListProfileSerialRules(ctx, ids)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	rules := make(map[int64][]string, len(req))
+	for _, item := range req {
+		rules[item.ID] = item.SerialRule
+	}
+
+	return rules, nil
+}
+
+// serialRule keeps the column not null when the profile has no rule.
+func serialRule(rule []string) []string {
+	if rule == nil {
+		return []string{}
+	}
+
+	return rule
+}
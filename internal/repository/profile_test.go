@@ -11,6 +11,7 @@ import (
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/generate"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/serial"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 )
 
@@ -513,3 +514,70 @@ func TestProfileRepository_List(t *testing.T) {
 		})
 	}
 }
+
+func TestProfileRepository_SerialRules(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateProfiles(t, testDB)
+		testDB.Close()
+	})
+	q := queries.New(testDB)
+	p := addTestProfile(t, testDB)
+	rp := addTestProfile(t, testDB)
+
+	const query = `
+		UPDATE profiles
+		SET serial_rule = $2
+		WHERE id = $1;`
+
+	rule := []string{serial.StripSpaces, serial.Upper}
+	if _, err := testDB.Exec(t.Context(), query, rp.ID, rule); err != nil {
+		t.Fatalf("failed to update test profile: %v", err)
+	}
+
+	type fields struct {
+		queries queries.Querier
+	}
+	type args struct {
+		ctx context.Context
+		ids []int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    map[int64][]string
+		wantErr bool
+	}{
+		{
+			name: "get serial rules",
+			fields: fields{
+				queries: q,
+			},
+			args: args{
+				ctx: t.Context(),
+				ids: []int64{p.ID, rp.ID, 999},
+			},
+			want: map[int64][]string{
+				p.ID:  {},
+				rp.ID: rule,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ProfileRepository{
+				queries: tt.fields.queries,
+			}
+			got, err := r.SerialRules(tt.args.ctx, tt.args.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SerialRules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SerialRules() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type RepairOrderRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewRepairOrderRepository(postgresDB *pgxpool.Pool) *RepairOrderRepository {
+	return &RepairOrderRepository{postgresDB: postgresDB}
+}
+
+// Send records the move to repair and opens the order in one transaction,
+// an equipment can have only one open order.
+func (r *RepairOrderRepository) Send(ctx context.Context, order *queries.CreateRepairOrderParams, location *queries.MoveToLocationParams) (int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	locationID, err := moveInTx(ctx, q, location)
+	if err != nil {
+		return 0, err
+	}
+
+	order.SendLocationID = locationID
+	id, err := q.CreateRepairOrder(ctx, order)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return id, nil
+}
+
+func (r *RepairOrderRepository) Read(ctx context.Context, id int64) (*model.RepairOrder, error) {
+	req, err := queries.New(r.postgresDB).GetRepairOrder(ctx, id)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	return toRepairOrder(req), nil
+}
+
+func (r *RepairOrderRepository) List(ctx context.Context, status string, qp *dto.QueryParams) ([]*model.RepairOrder, int64, error) {
+	req, err := queries.New(r.postgresDB).ListRepairOrders(ctx, &queries.ListRepairOrdersParams{
+		Status:           status,
+		DepartmentID:     qp.ScopeDepartmentID,
+		PaginationLimit:  qp.PaginationLimit,
+		PaginationOffset: qp.PaginationOffset,
+	})
+	if err != nil {
+		return nil, 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if len(req) < 1 {
+		return []*model.RepairOrder{}, 0, nil
+	}
+
+	list := make([]*model.RepairOrder, len(req))
+	for i, item := range req {
+		list[i] = toRepairOrder(&queries.GetRepairOrderRow{
+			ID:                 item.ID,
+			EquipmentID:        item.EquipmentID,
+			SerialNumber:       item.SerialNumber,
+			Status:             item.Status,
+			Vendor:             item.Vendor,
+			Cost:               item.Cost,
+			Notes:              item.Notes,
+			SentBy:             item.SentBy,
+			SentByUsername:     item.SentByUsername,
+			SentAt:             item.SentAt,
+			SendLocationID:     item.SendLocationID,
+			OriginDepartmentID: item.OriginDepartmentID,
+			OriginEmployeeID:   item.OriginEmployeeID,
+			OriginContractID:   item.OriginContractID,
+			ReturnedBy:         item.ReturnedBy,
+			ReturnedByUsername: item.ReturnedByUsername,
+			ReturnedAt:         item.ReturnedAt,
+			ReturnLocationID:   item.ReturnLocationID,
+			UpdatedAt:          item.UpdatedAt,
+		})
+	}
+
+	return list, req[0].Total, nil
+}
+
+func (r *RepairOrderRepository) ListByEquipment(ctx context.Context, equipmentID int64) ([]*model.RepairOrder, error) {
+	req, err := queries.New(r.postgresDB).ListRepairOrdersByEquipment(ctx, equipmentID)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	list := make([]*model.RepairOrder, len(req))
+	for i, item := range req {
+		list[i] = toRepairOrder((*queries.GetRepairOrderRow)(item))
+	}
+
+	return list, nil
+}
+
+// Update changes the order only if its status is still currentStatus.
+func (r *RepairOrderRepository) Update(ctx context.Context, order *queries.UpdateRepairOrderParams) error {
+	ct, err := queries.New(r.postgresDB).UpdateRepairOrder(ctx, order)
+	if err != nil {
+		return logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return logger.Error(logger.MsgFailedToUpdate, logger.ErrRepairOrderChanged)
+	}
+
+	return nil
+}
+
+// Return records the move back from repair and closes the order, which has
+// to be repaired or unrepairable by then.
+func (r *RepairOrderRepository) Return(ctx context.Context, id, returnedBy int64, location *queries.MoveToLocationParams) (int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	order, err := q.LockRepairOrder(ctx, id)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if order.Status != model.RepairRepaired && order.Status != model.RepairUnrepairable {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalRepairStatus)
+	}
+
+	if order.EquipmentID != location.EquipmentID {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrLocationChanged)
+	}
+
+	locationID, err := moveInTx(ctx, q, location)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := q.ReturnRepairOrder(ctx, &queries.ReturnRepairOrderParams{
+		ReturnedBy:       returnedBy,
+		ReturnedAt:       location.MoveAt,
+		ReturnLocationID: locationID,
+		ID:               id,
+	}); err != nil {
+		return 0, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return locationID, nil
+}
+
+func toRepairOrder(r *queries.GetRepairOrderRow) *model.RepairOrder {
+	order := &model.RepairOrder{
+		ID: r.ID,
+		Equipment: &model.Equipment{
+			ID:           r.EquipmentID,
+			SerialNumber: r.SerialNumber,
+		},
+		Status: r.Status,
+		Vendor: validString(r.Vendor),
+		Cost:   validString(r.Cost),
+		Notes:  validString(r.Notes),
+		SentBy: &model.User{
+			ID:       r.SentBy,
+			Username: r.SentByUsername,
+		},
+		SentAt: validTime(r.SentAt),
+		SendLocation: toLocation(&queries.Location{
+			ID:               r.SendLocationID,
+			EquipmentID:      r.EquipmentID,
+			UserID:           r.SentBy,
+			MoveAt:           r.SentAt,
+			MoveCode:         model.SentToRepair,
+			FromDepartmentID: r.OriginDepartmentID,
+			FromEmployeeID:   r.OriginEmployeeID,
+			FromContractID:   r.OriginContractID,
+		}),
+		ReturnedAt: validTime(r.ReturnedAt),
+		UpdatedAt:  validTime(r.UpdatedAt),
+	}
+
+	if r.ReturnedBy.Valid {
+		order.ReturnedBy = &model.User{
+			ID:       r.ReturnedBy.Int64,
+			Username: validString(r.ReturnedByUsername),
+		}
+	}
+	if r.ReturnLocationID.Valid {
+		order.ReturnLocation = &model.Location{ID: r.ReturnLocationID.Int64}
+	}
+
+	return order
+}
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func truncateRepairOrders(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE repair_orders
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate repair order: %v", err)
+	}
+}
+
+func testRepairMove(equipmentID, userID int64, code string, departmentID int64) *queries.MoveToLocationParams {
+	move := &queries.MoveToLocationParams{
+		EquipmentID: equipmentID,
+		UserID:      userID,
+		MoveAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		MoveCode:    code,
+	}
+	department := pgtype.Int8{Int64: departmentID, Valid: departmentID != 0}
+	if code == model.SentToRepair {
+		move.FromDepartmentID = department
+	} else {
+		move.ToDepartmentID = department
+	}
+
+	return move
+}
+
+func addTestRepairOrder(t *testing.T, testDB *pgxpool.Pool, equipmentID, userID, departmentID int64) int64 {
+	t.Helper()
+	id, err := NewRepairOrderRepository(testDB).Send(t.Context(), &queries.CreateRepairOrderParams{
+		EquipmentID: equipmentID,
+		SentBy:      userID,
+		SentAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}, testRepairMove(equipmentID, userID, model.SentToRepair, departmentID))
+	if err != nil {
+		t.Fatalf("failed to insert test repair order: %v", err)
+	}
+
+	return id
+}
+
+func TestNewRepairOrderRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *RepairOrderRepository
+	}{
+		{
+			name: "create repair order repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewRepairOrderRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRepairOrderRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewRepairOrderRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepairOrderRepository_Send(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateRepairOrders(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx      context.Context
+		order    *queries.CreateRepairOrderParams
+		location *queries.MoveToLocationParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "send equipment to repair",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				order: &queries.CreateRepairOrderParams{
+					EquipmentID: e.ID,
+					Vendor:      pgtype.Text{String: "vendor", Valid: true},
+					SentBy:      u.ID,
+					SentAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				},
+				location: testRepairMove(e.ID, u.ID, model.SentToRepair, d.ID),
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "send equipment in repair",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				order: &queries.CreateRepairOrderParams{
+					EquipmentID: e.ID,
+					SentBy:      u.ID,
+					SentAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				},
+				location: testRepairMove(e.ID, u.ID, model.SentToRepair, 0),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RepairOrderRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Send(tt.args.ctx, tt.args.order, tt.args.location)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Send() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepairOrderRepository_Update(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateRepairOrders(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	id := addTestRepairOrder(t, testDB, e.ID, u.ID, 0)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx   context.Context
+		order *queries.UpdateRepairOrderParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "diagnose repair order",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				order: &queries.UpdateRepairOrderParams{
+					Status:        model.RepairDiagnosed,
+					Cost:          pgtype.Text{String: "100", Valid: true},
+					ID:            id,
+					CurrentStatus: model.RepairSent,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "update changed repair order",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				order: &queries.UpdateRepairOrderParams{
+					Status:        model.RepairRepaired,
+					ID:            id,
+					CurrentStatus: model.RepairSent,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RepairOrderRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			if err := r.Update(tt.args.ctx, tt.args.order); (err != nil) != tt.wantErr {
+				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRepairOrderRepository_Return(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateRepairOrders(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+	open := addTestRepairOrder(t, testDB, e.ID, u.ID, d.ID)
+	repaired := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, repaired.ID, u.ID, 0)
+	done := addTestRepairOrder(t, testDB, repaired.ID, u.ID, 0)
+	if err := NewRepairOrderRepository(testDB).Update(t.Context(), &queries.UpdateRepairOrderParams{
+		Status:        model.RepairRepaired,
+		ID:            done,
+		CurrentStatus: model.RepairSent,
+	}); err != nil {
+		t.Fatalf("failed to update test repair order: %v", err)
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx        context.Context
+		id         int64
+		returnedBy int64
+		location   *queries.MoveToLocationParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "return unfinished repair order",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:        t.Context(),
+				id:         open,
+				returnedBy: u.ID,
+				location:   testRepairMove(e.ID, u.ID, model.ReturnedFromRepair, d.ID),
+			},
+			wantErr: true,
+		},
+		{
+			name: "return repaired equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:        t.Context(),
+				id:         done,
+				returnedBy: u.ID,
+				location:   testRepairMove(repaired.ID, u.ID, model.ReturnedFromRepair, 0),
+			},
+			wantErr: false,
+		},
+		{
+			name: "return returned equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:        t.Context(),
+				id:         done,
+				returnedBy: u.ID,
+				location:   testRepairMove(repaired.ID, u.ID, model.ReturnedFromRepair, 0),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RepairOrderRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Return(tt.args.ctx, tt.args.id, tt.args.returnedBy, tt.args.location)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Return() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			read, err := r.Read(tt.args.ctx, tt.args.id)
+			if err != nil {
+				t.Fatalf("failed to read repair order: %v", err)
+			}
+			if read.Status != model.RepairReturned || read.ReturnLocation == nil || read.ReturnLocation.ID != got {
+				t.Errorf("Return() got = %v, want returned with location %v", read, got)
+			}
+		})
+	}
+}
@@ -4,46 +4,66 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 )
 
 type ReplaceRepository struct {
-	DB *pgxpool.Pool
+	postgresDB *pgxpool.Pool
 }
 
-func NewReplaceRepository(db *pgxpool.Pool) *ReplaceRepository {
-	return &ReplaceRepository{DB: db}
+func NewReplaceRepository(postgresDB *pgxpool.Pool) *ReplaceRepository {
+	return &ReplaceRepository{postgresDB: postgresDB}
 }
 
-// Create is replace create
-func (r *ReplaceRepository) Create(ctx context.Context, transferIds []int64) error {
-	const query = `
-		INSERT INTO replaces (transfer_from, transfer_to) 
-		VALUES ($1, $2);`
+func (r *ReplaceRepository) Create(ctx context.Context, moveOut, moveIn *queries.MoveToLocationParams) (*model.Replace, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return nil, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	moveOutID, err := moveInTx(ctx, q, moveOut)
+	if err != nil {
+		return nil, err
+	}
 
-	if _, err := r.DB.Exec(ctx, query, transferIds[0], transferIds[1]); err != nil {
-		return err
+	moveInID, err := moveInTx(ctx, q, moveIn)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	res, err := q.CreateReplace(ctx, &queries.CreateReplaceParams{
+		MoveInID:  moveInID,
+		MoveOutID: moveOutID,
+	})
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, logger.Error("", err)
+	}
+
+	return &model.Replace{
+		ID:      res.ID,
+		MoveIn:  movedLocation(moveInID, moveIn),
+		MoveOut: movedLocation(moveOutID, moveOut),
+	}, nil
 }
 
-// FindByLocationId is replaced get by location
 func (r *ReplaceRepository) FindByLocationId(ctx context.Context, locationId int64) (*model.Replace, error) {
-	replace := new(model.Replace)
-
-	query := `
-			SELECT id, transfer_from, transfer_to 
-			FROM replaces 
-			WHERE transfer_from = $1 OR transfer_to = $1;`
-
-	if err := r.DB.QueryRow(ctx, query, locationId).Scan(
-		&replace.ID,
-		&replace.TransferFrom,
-		&replace.TransferTo,
-	); err != nil {
-		return nil, err
+	res, err := queries.New(r.postgresDB).GetReplaceByLocation(ctx, locationId)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToScan, err)
 	}
 
-	return replace, nil
+	return &model.Replace{
+		ID:      res.ID,
+		MoveIn:  &model.Location{ID: res.MoveInID},
+		MoveOut: &model.Location{ID: res.MoveOutID},
+	}, nil
 }
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func TestNewReplaceRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *ReplaceRepository
+	}{
+		{
+			name: "create replace repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewReplaceRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewReplaceRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewReplaceRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceRepository_Create(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		truncateContracts(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	c := addTestContract(t, testDB)
+	out := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, out.ID, u.ID, 0)
+	addTestLocation(t, testDB, out.ID, u.ID, d.ID)
+	in := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, in.ID, u.ID, 0)
+	addTestLocation(t, testDB, in.ID, u.ID, d.ID)
+
+	date := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	department := pgtype.Int8{Int64: d.ID, Valid: true}
+	contract := pgtype.Int8{Int64: c.ID, Valid: true}
+
+	if _, err := NewLocationRepository(testDB).Move(t.Context(), &queries.MoveToLocationParams{
+		EquipmentID:      in.ID,
+		UserID:           u.ID,
+		MoveAt:           date,
+		MoveCode:         "DepartmentToContract",
+		FromDepartmentID: department,
+		ToContractID:     contract,
+	}); err != nil {
+		t.Fatalf("failed to move test equipment: %v", err)
+	}
+
+	moveOut := &queries.MoveToLocationParams{
+		EquipmentID:      out.ID,
+		UserID:           u.ID,
+		MoveAt:           date,
+		MoveCode:         "DepartmentToContract",
+		FromDepartmentID: department,
+		ToContractID:     contract,
+	}
+	moveIn := &queries.MoveToLocationParams{
+		EquipmentID:    in.ID,
+		UserID:         u.ID,
+		MoveAt:         date,
+		MoveCode:       "ContractToDepartment",
+		FromContractID: contract,
+		ToDepartmentID: department,
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		moveOut *queries.MoveToLocationParams
+		moveIn  *queries.MoveToLocationParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Replace
+		wantErr bool
+	}{
+		{
+			name: "replace equipment on contract",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:     t.Context(),
+				moveOut: moveOut,
+				moveIn:  moveIn,
+			},
+			want: &model.Replace{
+				ID:      1,
+				MoveIn:  movedLocation(8, moveIn),
+				MoveOut: movedLocation(7, moveOut),
+			},
+			wantErr: false,
+		},
+		{
+			name: "replace equipment from stale location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:     t.Context(),
+				moveOut: moveOut,
+				moveIn:  moveIn,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReplaceRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Create(tt.args.ctx, tt.args.moveOut, tt.args.moveIn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,37 +9,55 @@ import (
 	queries "github.com/oatsmoke/warehouse_backend/internal/db"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/jwt_auth"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/kafka"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/redis/go-redis/v9"
 )
 
 type Repository struct {
-	Auth       *AuthRepository
-	User       *UserRepository
-	Employee   *EmployeeRepository
-	Department *DepartmentRepository
-	Category   *CategoryRepository
-	Profile    *ProfileRepository
-	Equipment  *EquipmentRepository
-	Location   *LocationRepository
-	Contract   *ContractRepository
-	Company    *CompanyRepository
-	Replace    *ReplaceRepository
+	Auth         *AuthRepository
+	User         *UserRepository
+	Employee     *EmployeeRepository
+	Department   *DepartmentRepository
+	Category     *CategoryRepository
+	Profile      *ProfileRepository
+	Equipment    *EquipmentRepository
+	Location     *LocationRepository
+	Contract     *ContractRepository
+	Company      *CompanyRepository
+	Replace      *ReplaceRepository
+	Stocktaking  *StocktakingRepository
+	WriteOff     *WriteOffRepository
+	RepairOrder  *RepairOrderRepository
+	Reservation  *ReservationRepository
+	StockMinimum *StockMinimumRepository
+	Outbox       *OutboxRepository
+	Audit        *AuditRepository
+	Scope        *ScopeRepository
 }
 
 func New(postgresDB *pgxpool.Pool, redisDB *redis.Client, queries queries.Querier) *Repository {
 	return &Repository{
-		Auth:       NewAuthRepository(redisDB),
-		User:       NewUserRepository(queries),
-		Employee:   NewEmployeeRepository(queries),
-		Department: NewDepartmentRepository(queries),
-		Category:   NewCategoryRepository(queries),
-		Profile:    NewProfileRepository(queries),
-		Equipment:  NewEquipmentRepository(postgresDB),
-		Location:   NewLocationRepository(postgresDB),
-		Contract:   NewContractRepository(queries),
-		Company:    NewCompanyRepository(queries),
-		Replace:    NewReplaceRepository(postgresDB),
+		Auth:         NewAuthRepository(redisDB),
+		User:         NewUserRepository(queries, postgresDB),
+		Employee:     NewEmployeeRepository(queries),
+		Department:   NewDepartmentRepository(queries),
+		Category:     NewCategoryRepository(queries),
+		Profile:      NewProfileRepository(queries),
+		Equipment:    NewEquipmentRepository(postgresDB),
+		Location:     NewLocationRepository(postgresDB),
+		Contract:     NewContractRepository(queries),
+		Company:      NewCompanyRepository(queries),
+		Replace:      NewReplaceRepository(postgresDB),
+		Stocktaking:  NewStocktakingRepository(postgresDB),
+		WriteOff:     NewWriteOffRepository(postgresDB),
+		RepairOrder:  NewRepairOrderRepository(postgresDB),
+		Reservation:  NewReservationRepository(postgresDB),
+		StockMinimum: NewStockMinimumRepository(postgresDB),
+		Outbox:       NewOutboxRepository(postgresDB),
+		Audit:        NewAuditRepository(postgresDB),
+		Scope:        NewScopeRepository(postgresDB),
 	}
 }
 
@@ -109,39 +127,34 @@ type Profile interface {
 	Delete(ctx context.Context, id int64) error
 	Restore(ctx context.Context, id int64) error
 	List(ctx context.Context, qp *dto.QueryParams) ([]*model.Profile, int64, error)
+	IDsByTitles(ctx context.Context, titles []string) (map[string]int64, error)
+	SerialRules(ctx context.Context, ids []int64) (map[int64][]string, error)
 }
 
 type Equipment interface {
 	Create(ctx context.Context, equipment *queries.CreateEquipmentParams, location *queries.AddToStorageParams) (int64, error)
+	CreateMany(ctx context.Context, equipments []*queries.CreateEquipmentParams, location *queries.AddToStorageParams, move *queries.MoveToLocationParams) ([]int64, error)
 	Read(ctx context.Context, id int64) (*model.Equipment, error)
+	ReadBySerialNumber(ctx context.Context, serialNumber string) (*model.Equipment, error)
 	Update(ctx context.Context, equipment *model.Equipment) error
 	Delete(ctx context.Context, id int64) error
 	Restore(ctx context.Context, id int64) error
 	List(ctx context.Context, qp *dto.QueryParams) ([]*model.Equipment, int64, error)
+	ExistingSerialNumbers(ctx context.Context, serialNumbers []string) ([]string, error)
+	Duplicates(ctx context.Context, departmentID int64) ([]*model.SerialNumberDuplicate, error)
+	Merge(ctx context.Context, equipmentID, duplicateID, mergedBy int64) (*model.EquipmentMerge, error)
 }
 
 type Location interface {
-	Move(ctx context.Context, location *queries.MoveToLocationParams) error
-	List(ctx context.Context, toDepartmentID int64) ([]*model.Equipment, int64, error)
-	//AddToStorage(ctx context.Context, date *time.Time, equipmentId, employeeId, companyId int64) error
-	//TransferToStorage(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId int64, nowLocation []interface{}) (int64, error)
-	//TransferToDepartment(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toDepartment int64, nowLocation []interface{}) (int64, error)
-	//TransferToEmployee(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toEmployee int64, nowLocation []interface{}) (int64, error)
-	//TransferToEmployeeInDepartment(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toDepartment, toEmployee int64, nowLocation []interface{}) (int64, error)
-	//TransferToContract(ctx context.Context, date *time.Time, code string, equipmentId, employeeId, companyId, toContract int64, transferType string, price string, nowLocation []interface{}) (int64, error)
-	//Delete(ctx context.Context, id int64) error
+	Move(ctx context.Context, location *queries.MoveToLocationParams) (int64, error)
+	MoveMany(ctx context.Context, locations []*queries.MoveToLocationParams) ([]int64, error)
+	Revert(ctx context.Context, locationID, revertedBy int64) ([]int64, error)
+	GetLast(ctx context.Context, equipmentID int64) (*model.Location, error)
+	Current(ctx context.Context, equipmentID int64) (*model.Location, error)
+	History(ctx context.Context, equipmentID int64, qp *dto.QueryParams) ([]*model.Location, int64, error)
+	List(ctx context.Context, qp *dto.QueryParams) ([]*model.Equipment, int64, error)
+	DepartmentBalance(ctx context.Context, params *queries.DepartmentBalanceReportParams) ([]*model.DepartmentBalanceRow, error)
 	//GetById(ctx context.Context, equipmentId int64) (*model.Location, error)
-	//GetHistory(ctx context.Context, equipmentId int64) ([]*model.Location, error)
-	//GetLocationNow(ctx context.Context, equipmentId int64) ([]interface{}, error)
-	//GetByLocationStorage(ctx context.Context) ([]*model.Location, error)
-	//GetByLocationDepartment(ctx context.Context, toDepartment int64) ([]*model.Location, error)
-	//GetByLocationEmployee(ctx context.Context, toEmployee int64) ([]*model.Location, error)
-	//GetByLocationContract(ctx context.Context, toContract int64) ([]*model.Location, error)
-	//GetByLocationDepartmentEmployee(ctx context.Context, toDepartment, toEmployee int64) ([]*model.Location, error)
-	//RemainderByCategory(ctx context.Context, categoryId, departmentId int64, date *time.Time) ([]*model.Location, error)
-	//TransferByCategory(ctx context.Context, categoryId, departmentId int64, fromDate, toDate *time.Time, code string) ([]*model.Location, error)
-	//ToDepartmentTransferByCategory(ctx context.Context, categoryId, departmentId int64, fromDate, toDate *time.Time) ([]*model.Location, error)
-	//FromDepartmentTransferByCategory(ctx context.Context, categoryId, departmentId int64, fromDate, toDate *time.Time) ([]*model.Location, error)
 }
 
 type Contract interface {
@@ -160,13 +173,71 @@ type Company interface {
 	Delete(ctx context.Context, id int64) error
 	Restore(ctx context.Context, id int64) error
 	List(ctx context.Context, qp *dto.QueryParams) ([]*model.Company, int64, error)
+	IDsByTitles(ctx context.Context, titles []string) (map[string]int64, error)
 }
 
 type Replace interface {
-	Create(ctx context.Context, transferIds []int64) error
+	Create(ctx context.Context, moveOut, moveIn *queries.MoveToLocationParams) (*model.Replace, error)
 	FindByLocationId(ctx context.Context, locationId int64) (*model.Replace, error)
 }
 
+type Stocktaking interface {
+	Create(ctx context.Context, stocktaking *queries.CreateStocktakingParams) (int64, error)
+	Read(ctx context.Context, id int64) (*model.Stocktaking, error)
+	Scan(ctx context.Context, scans *queries.CreateStocktakingScansParams) (int64, error)
+	Close(ctx context.Context, id, closedBy int64) error
+	Discrepancies(ctx context.Context, id int64) ([]*model.StocktakingDiscrepancy, error)
+	Apply(ctx context.Context, id int64, locations []*queries.MoveToLocationParams) ([]int64, map[int64]error, error)
+}
+
+type WriteOff interface {
+	Create(ctx context.Context, writeOff *queries.CreateWriteOffParams) (int64, error)
+	Read(ctx context.Context, id int64) (*model.WriteOff, error)
+	List(ctx context.Context, status string, qp *dto.QueryParams) ([]*model.WriteOff, int64, error)
+	Approve(ctx context.Context, id, decidedBy int64, location *queries.MoveToLocationParams) (int64, error)
+	Reject(ctx context.Context, id, decidedBy int64) error
+}
+
+type RepairOrder interface {
+	Send(ctx context.Context, order *queries.CreateRepairOrderParams, location *queries.MoveToLocationParams) (int64, error)
+	Read(ctx context.Context, id int64) (*model.RepairOrder, error)
+	List(ctx context.Context, status string, qp *dto.QueryParams) ([]*model.RepairOrder, int64, error)
+	ListByEquipment(ctx context.Context, equipmentID int64) ([]*model.RepairOrder, error)
+	Update(ctx context.Context, order *queries.UpdateRepairOrderParams) error
+	Return(ctx context.Context, id, returnedBy int64, location *queries.MoveToLocationParams) (int64, error)
+}
+
+type Reservation interface {
+	Create(ctx context.Context, reservation *queries.CreateReservationParams) (int64, error)
+	Read(ctx context.Context, id int64) (*model.Reservation, error)
+	List(ctx context.Context, status string, qp *dto.QueryParams) ([]*model.Reservation, int64, error)
+	Cancel(ctx context.Context, id, closedBy int64) error
+}
+
+type Outbox interface {
+	Relay(ctx context.Context, publisher kafka.Publisher, batchSize int32) (int, error)
+}
+
+type Audit interface {
+	Create(ctx context.Context, entry *queries.CreateAuditLogParams) error
+	List(ctx context.Context, filter *queries.ListAuditLogParams) ([]*model.AuditEntry, int64, error)
+}
+
+type Scope interface {
+	UserDepartment(ctx context.Context, userID int64) (int64, error)
+	EmployeeInDepartment(ctx context.Context, employeeID, departmentID int64) (bool, error)
+	EquipmentInDepartment(ctx context.Context, equipmentID, departmentID int64) (bool, error)
+	WriteOffInDepartment(ctx context.Context, writeOffID, departmentID int64) (bool, error)
+}
+
+type StockMinimum interface {
+	Set(ctx context.Context, minimum *queries.SetStockMinimumParams) (int64, error)
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, lowOnly bool) ([]*model.StockMinimum, error)
+	MarkLow(ctx context.Context, ids []int64) ([]int64, error)
+	Recipients(ctx context.Context, roles []role.Role) ([]*model.User, error)
+}
+
 func validInt64(data pgtype.Int8) int64 {
 	if data.Valid {
 		return data.Int64
@@ -174,6 +245,13 @@ func validInt64(data pgtype.Int8) int64 {
 	return 0
 }
 
+func validInt64Pointer(data pgtype.Int8) *int64 {
+	if data.Valid {
+		return &data.Int64
+	}
+	return nil
+}
+
 func validString(data pgtype.Text) string {
 	if data.Valid {
 		return data.String
@@ -187,3 +265,10 @@ func validTime(data pgtype.Timestamptz) *time.Time {
 	}
 	return nil
 }
+
+func validStrings(data []string) []string {
+	if len(data) != 0 {
+		return data
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type ReservationRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewReservationRepository(postgresDB *pgxpool.Pool) *ReservationRepository {
+	return &ReservationRepository{postgresDB: postgresDB}
+}
+
+// Create reserves equipment that is in storage right now. A stale active
+// reservation is expired first so that it does not block the new one.
+func (r *ReservationRepository) Create(ctx context.Context, reservation *queries.CreateReservationParams) (int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	equipment, err := q.LockEquipment(ctx, reservation.EquipmentID)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if equipment.DeletedAt.Valid {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+	}
+
+	if equipment.WrittenOffAt.Valid {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentWrittenOff)
+	}
+
+	last, err := q.GetLastLocation(ctx, reservation.EquipmentID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if last.MoveCode == model.SentToRepair ||
+		last.ToDepartmentID.Valid || last.ToEmployeeID.Valid || last.ToContractID.Valid {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrNotInStorage)
+	}
+
+	if err := q.ExpireReservations(ctx, reservation.EquipmentID); err != nil {
+		return 0, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	id, err := q.CreateReservation(ctx, reservation)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return id, nil
+}
+
+func (r *ReservationRepository) Read(ctx context.Context, id int64) (*model.Reservation, error) {
+	req, err := queries.New(r.postgresDB).GetReservation(ctx, id)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	return toReservation(req), nil
+}
+
+func (r *ReservationRepository) List(ctx context.Context, status string, qp *dto.QueryParams) ([]*model.Reservation, int64, error) {
+	req, err := queries.New(r.postgresDB).ListReservations(ctx, &queries.ListReservationsParams{
+		Status:           status,
+		DepartmentID:     qp.ScopeDepartmentID,
+		PaginationLimit:  qp.PaginationLimit,
+		PaginationOffset: qp.PaginationOffset,
+	})
+	if err != nil {
+		return nil, 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if len(req) < 1 {
+		return []*model.Reservation{}, 0, nil
+	}
+
+	list := make([]*model.Reservation, len(req))
+	for i, item := range req {
+		list[i] = toReservation(&queries.GetReservationRow{
+			ID:                   item.ID,
+			EquipmentID:          item.EquipmentID,
+			SerialNumber:         item.SerialNumber,
+			ToDepartmentID:       item.ToDepartmentID,
+			ToDepartmentTitle:    item.ToDepartmentTitle,
+			ToEmployeeID:         item.ToEmployeeID,
+			ToEmployeeLastName:   item.ToEmployeeLastName,
+			ToEmployeeFirstName:  item.ToEmployeeFirstName,
+			ToEmployeeMiddleName: item.ToEmployeeMiddleName,
+			ToContractID:         item.ToContractID,
+			ToContractNumber:     item.ToContractNumber,
+			ExpiresAt:            item.ExpiresAt,
+			Comment:              item.Comment,
+			Status:               item.Status,
+			ReservedBy:           item.ReservedBy,
+			ReservedByUsername:   item.ReservedByUsername,
+			ReservedAt:           item.ReservedAt,
+			ClosedBy:             item.ClosedBy,
+			ClosedByUsername:     item.ClosedByUsername,
+			ClosedAt:             item.ClosedAt,
+			LocationID:           item.LocationID,
+		})
+	}
+
+	return list, req[0].Total, nil
+}
+
+func (r *ReservationRepository) Cancel(ctx context.Context, id, closedBy int64) error {
+	ct, err := queries.New(r.postgresDB).CancelReservation(ctx, &queries.CancelReservationParams{
+		ClosedBy: closedBy,
+		ID:       id,
+	})
+	if err != nil {
+		return logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return logger.Error(logger.MsgFailedToUpdate, logger.ErrReservationClosed)
+	}
+
+	return nil
+}
+
+func toReservation(r *queries.GetReservationRow) *model.Reservation {
+	reservation := &model.Reservation{
+		ID: r.ID,
+		Equipment: &model.Equipment{
+			ID:           r.EquipmentID,
+			SerialNumber: r.SerialNumber,
+		},
+		ExpiresAt: validTime(r.ExpiresAt),
+		Comment:   validString(r.Comment),
+		Status:    r.Status,
+		ReservedBy: &model.User{
+			ID:       r.ReservedBy,
+			Username: r.ReservedByUsername,
+		},
+		ReservedAt: validTime(r.ReservedAt),
+		ClosedAt:   validTime(r.ClosedAt),
+	}
+
+	if r.ToDepartmentID.Valid {
+		reservation.ToDepartment = &model.Department{
+			ID:    r.ToDepartmentID.Int64,
+			Title: validString(r.ToDepartmentTitle),
+		}
+	}
+	if r.ToEmployeeID.Valid {
+		reservation.ToEmployee = &model.Employee{
+			ID:         r.ToEmployeeID.Int64,
+			LastName:   validString(r.ToEmployeeLastName),
+			FirstName:  validString(r.ToEmployeeFirstName),
+			MiddleName: validString(r.ToEmployeeMiddleName),
+		}
+	}
+	if r.ToContractID.Valid {
+		reservation.ToContract = &model.Contract{
+			ID:     r.ToContractID.Int64,
+			Number: validString(r.ToContractNumber),
+		}
+	}
+	if r.ClosedBy.Valid {
+		reservation.ClosedBy = &model.User{
+			ID:       r.ClosedBy.Int64,
+			Username: validString(r.ClosedByUsername),
+		}
+	}
+	if r.LocationID.Valid {
+		reservation.Location = &model.Location{ID: r.LocationID.Int64}
+	}
+
+	return reservation
+}
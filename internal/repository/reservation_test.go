@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func truncateReservations(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE reservations
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate reservation: %v", err)
+	}
+}
+
+func addTestReservation(t *testing.T, testDB *pgxpool.Pool, equipmentID, userID, departmentID int64) int64 {
+	t.Helper()
+	id, err := NewReservationRepository(testDB).Create(t.Context(), &queries.CreateReservationParams{
+		EquipmentID:    equipmentID,
+		ToDepartmentID: pgtype.Int8{Int64: departmentID, Valid: true},
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+		ReservedBy:     userID,
+	})
+	if err != nil {
+		t.Fatalf("failed to insert test reservation: %v", err)
+	}
+
+	return id
+}
+
+func TestNewReservationRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *ReservationRepository
+	}{
+		{
+			name: "create reservation repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewReservationRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewReservationRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewReservationRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReservationRepository_Create(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateReservations(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	stored := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, stored.ID, u.ID, 0)
+	issued := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, issued.ID, u.ID, 0)
+	addTestLocation(t, testDB, issued.ID, u.ID, d.ID)
+
+	reservation := func(equipmentID int64) *queries.CreateReservationParams {
+		return &queries.CreateReservationParams{
+			EquipmentID:    equipmentID,
+			ToDepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+			ExpiresAt:      pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+			ReservedBy:     u.ID,
+		}
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx         context.Context
+		reservation *queries.CreateReservationParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "reserve storage equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				reservation: reservation(stored.ID),
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "reserve reserved equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				reservation: reservation(stored.ID),
+			},
+			wantErr: true,
+		},
+		{
+			name: "reserve equipment out of storage",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:         t.Context(),
+				reservation: reservation(issued.ID),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReservationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Create(tt.args.ctx, tt.args.reservation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReservationRepository_Cancel(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateReservations(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	id := addTestReservation(t, testDB, e.ID, u.ID, d.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx      context.Context
+		id       int64
+		closedBy int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "cancel active reservation",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:      t.Context(),
+				id:       id,
+				closedBy: u.ID,
+			},
+			wantErr: false,
+		},
+		{
+			name: "cancel cancelled reservation",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:      t.Context(),
+				id:       id,
+				closedBy: u.ID,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReservationRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			if err := r.Cancel(tt.args.ctx, tt.args.id, tt.args.closedBy); (err != nil) != tt.wantErr {
+				t.Errorf("Cancel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_MoveReserved(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateReservations(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	reserved := addTestDepartment(t, testDB)
+	other := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	id := addTestReservation(t, testDB, e.ID, u.ID, reserved.ID)
+
+	move := func(departmentID int64) *queries.MoveToLocationParams {
+		return &queries.MoveToLocationParams{
+			EquipmentID:    e.ID,
+			UserID:         u.ID,
+			MoveAt:         pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			MoveCode:       "StorageToDepartment",
+			ToDepartmentID: pgtype.Int8{Int64: departmentID, Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		location   *queries.MoveToLocationParams
+		wantStatus string
+		wantErr    bool
+	}{
+		{
+			name:       "move reserved equipment elsewhere",
+			location:   move(other.ID),
+			wantStatus: model.ReservationActive,
+			wantErr:    true,
+		},
+		{
+			name:       "move reserved equipment to reserved destination",
+			location:   move(reserved.ID),
+			wantStatus: model.ReservationConsumed,
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLocationRepository(testDB).Move(t.Context(), tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Move() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			read, err := NewReservationRepository(testDB).Read(t.Context(), id)
+			if err != nil {
+				t.Fatalf("failed to read reservation: %v", err)
+			}
+			if read.Status != tt.wantStatus {
+				t.Errorf("Move() reservation status = %v, want %v", read.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestLocationRepository_RevertReserved(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateReservations(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	id := addTestReservation(t, testDB, e.ID, u.ID, d.ID)
+
+	locationID, err := NewLocationRepository(testDB).Move(t.Context(), &queries.MoveToLocationParams{
+		EquipmentID:    e.ID,
+		UserID:         u.ID,
+		MoveAt:         pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		MoveCode:       "StorageToDepartment",
+		ToDepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to move reserved equipment: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		locationID int64
+		wantStatus string
+		wantErr    bool
+	}{
+		{
+			name:       "revert move consuming reservation",
+			locationID: locationID,
+			wantStatus: model.ReservationActive,
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLocationRepository(testDB).Revert(t.Context(), tt.locationID, u.ID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Revert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			read, err := NewReservationRepository(testDB).Read(t.Context(), id)
+			if err != nil {
+				t.Fatalf("failed to read reservation: %v", err)
+			}
+			if read.Status != tt.wantStatus {
+				t.Errorf("Revert() reservation status = %v, want %v", read.Status, tt.wantStatus)
+			}
+			if read.Location != nil || read.ClosedAt != nil {
+				t.Errorf("Revert() reservation still closed by location %v", read.Location)
+			}
+		})
+	}
+}
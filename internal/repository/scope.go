@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+)
+
+type ScopeRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewScopeRepository(postgresDB *pgxpool.Pool) *ScopeRepository {
+	return &ScopeRepository{postgresDB: postgresDB}
+}
+
+// UserDepartment returns the department of the employee linked to the user,
+// 0 when the user has no employee or the employee no department.
+func (r *ScopeRepository) UserDepartment(ctx context.Context, userID int64) (int64, error) {
+	req, err := queries.New(r.postgresDB).GetUserDepartmentID(ctx, userID)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	return validInt64(req), nil
+}
+
+func (r *ScopeRepository) EmployeeInDepartment(ctx context.Context, employeeID, departmentID int64) (bool, error) {
+	req, err := queries.New(r.postgresDB).IsEmployeeInDepartment(ctx, &queries.IsEmployeeInDepartmentParams{
+		ID:           employeeID,
+		DepartmentID: departmentID,
+	})
+	if err != nil {
+		return false, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	return req, nil
+}
+
+// EquipmentInDepartment reports whether the latest location of the
+// equipment is the department or one of its employees.
+func (r *ScopeRepository) EquipmentInDepartment(ctx context.Context, equipmentID, departmentID int64) (bool, error) {
+	req, err := queries.New(r.postgresDB).IsEquipmentInDepartment(ctx, &queries.IsEquipmentInDepartmentParams{
+		EquipmentID:  equipmentID,
+		DepartmentID: departmentID,
+	})
+	if err != nil {
+		return false, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	return req, nil
+}
+
+// WriteOffInDepartment reports whether the equipment of the write-off was
+// held by the department or one of its employees: where the approving move
+// took it from, or its latest location while the request is undecided.
+func (r *ScopeRepository) WriteOffInDepartment(ctx context.Context, writeOffID, departmentID int64) (bool, error) {
+	req, err := queries.New(r.postgresDB).IsWriteOffInDepartment(ctx, &queries.IsWriteOffInDepartmentParams{
+		ID:           writeOffID,
+		DepartmentID: departmentID,
+	})
+	if err != nil {
+		return false, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	return req, nil
+}
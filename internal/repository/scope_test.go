@@ -0,0 +1,580 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func setTestEmployeeDepartment(t *testing.T, testDB *pgxpool.Pool, employeeID, departmentID int64) {
+	t.Helper()
+
+	const query = `
+		UPDATE employees
+		SET department_id = $2
+		WHERE id = $1;`
+
+	if _, err := testDB.Exec(t.Context(), query, employeeID, departmentID); err != nil {
+		t.Fatalf("failed to set test employee department: %v", err)
+	}
+}
+
+func setTestUserEmployee(t *testing.T, testDB *pgxpool.Pool, userID, employeeID int64) {
+	t.Helper()
+
+	const query = `
+		UPDATE users
+		SET employee_id = $2
+		WHERE id = $1;`
+
+	if _, err := testDB.Exec(t.Context(), query, userID, employeeID); err != nil {
+		t.Fatalf("failed to set test user employee: %v", err)
+	}
+}
+
+func addTestEmployeeLocation(t *testing.T, testDB *pgxpool.Pool, equipmentID, userID, toEmployeeID int64) {
+	t.Helper()
+
+	const query = `
+		INSERT INTO locations (equipment_id, user_id, move_at, move_code, to_employee_id)
+		VALUES ($1, $2, now(), 'StorageToEmployee', $3);`
+
+	if _, err := testDB.Exec(t.Context(), query, equipmentID, userID, toEmployeeID); err != nil {
+		t.Fatalf("failed to insert test employee location: %v", err)
+	}
+}
+
+func TestNewScopeRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *ScopeRepository
+	}{
+		{
+			name: "create scope repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewScopeRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewScopeRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewScopeRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeRepository_UserDepartment(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateUsers(t, testDB)
+		truncateEmployees(t, testDB)
+		testDB.Close()
+	})
+	d := addTestDepartment(t, testDB)
+	e := addTestEmployee(t, testDB)
+	setTestEmployeeDepartment(t, testDB, e.ID, d.ID)
+	manager := addTestUser(t, testDB)
+	setTestUserEmployee(t, testDB, manager.ID, e.ID)
+	free := addTestEmployee(t, testDB)
+	freeUser := addTestUser(t, testDB)
+	setTestUserEmployee(t, testDB, freeUser.ID, free.ID)
+	noEmployee := addTestUser(t, testDB)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx    context.Context
+		userID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "user of employee in department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:    t.Context(),
+				userID: manager.ID,
+			},
+			want:    d.ID,
+			wantErr: false,
+		},
+		{
+			name: "user of employee without department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:    t.Context(),
+				userID: freeUser.ID,
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "user without employee",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:    t.Context(),
+				userID: noEmployee.ID,
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "user not found",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:    t.Context(),
+				userID: 0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ScopeRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.UserDepartment(tt.args.ctx, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserDepartment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UserDepartment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeRepository_EmployeeInDepartment(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateEmployees(t, testDB)
+		testDB.Close()
+	})
+	d := addTestDepartment(t, testDB)
+	other := addTestDepartment(t, testDB)
+	e := addTestEmployee(t, testDB)
+	setTestEmployeeDepartment(t, testDB, e.ID, d.ID)
+	free := addTestEmployee(t, testDB)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx          context.Context
+		employeeID   int64
+		departmentID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "employee of department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				employeeID:   e.ID,
+				departmentID: d.ID,
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "employee of another department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				employeeID:   e.ID,
+				departmentID: other.ID,
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "employee without department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				employeeID:   free.ID,
+				departmentID: d.ID,
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "employee not found",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				employeeID:   0,
+				departmentID: d.ID,
+			},
+			want:    false,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ScopeRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.EmployeeInDepartment(tt.args.ctx, tt.args.employeeID, tt.args.departmentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EmployeeInDepartment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EmployeeInDepartment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeRepository_EquipmentInDepartment(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		truncateEmployees(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	other := addTestDepartment(t, testDB)
+	e := addTestEmployee(t, testDB)
+	setTestEmployeeDepartment(t, testDB, e.ID, d.ID)
+	atDepartment := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, atDepartment.ID, u.ID, d.ID)
+	atEmployee := addTestEquipment(t, testDB)
+	addTestEmployeeLocation(t, testDB, atEmployee.ID, u.ID, e.ID)
+	atOther := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, atOther.ID, u.ID, d.ID)
+	addTestLocation(t, testDB, atOther.ID, u.ID, other.ID)
+	inStorage := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, inStorage.ID, u.ID, 0)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx          context.Context
+		equipmentID  int64
+		departmentID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "equipment at department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				equipmentID:  atDepartment.ID,
+				departmentID: d.ID,
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "equipment at employee of department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				equipmentID:  atEmployee.ID,
+				departmentID: d.ID,
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "equipment moved to another department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				equipmentID:  atOther.ID,
+				departmentID: d.ID,
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "equipment at another department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				equipmentID:  atDepartment.ID,
+				departmentID: other.ID,
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "equipment in storage",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				equipmentID:  inStorage.ID,
+				departmentID: d.ID,
+			},
+			want:    false,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ScopeRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.EquipmentInDepartment(tt.args.ctx, tt.args.equipmentID, tt.args.departmentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EquipmentInDepartment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EquipmentInDepartment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeRepository_WriteOffInDepartment(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateWriteOffs(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	other := addTestDepartment(t, testDB)
+	pending := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, pending.ID, u.ID, d.ID)
+	pendingID := addTestWriteOff(t, testDB, pending.ID, u.ID)
+	approved := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, approved.ID, u.ID, d.ID)
+	approvedID := addTestWriteOff(t, testDB, approved.ID, u.ID)
+
+	move := testWriteOffMove(approved.ID, u.ID)
+	move.FromDepartmentID = pgtype.Int8{Int64: d.ID, Valid: true}
+	if _, err := NewWriteOffRepository(testDB).Approve(t.Context(), approvedID, u.ID, move); err != nil {
+		t.Fatalf("failed to approve test write-off: %v", err)
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx          context.Context
+		writeOffID   int64
+		departmentID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "pending write-off of equipment at department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				writeOffID:   pendingID,
+				departmentID: d.ID,
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "pending write-off of equipment at another department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				writeOffID:   pendingID,
+				departmentID: other.ID,
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "approved write-off of equipment taken from department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:          t.Context(),
+				writeOffID:   approvedID,
+				departmentID: d.ID,
+			},
+			want:    true,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ScopeRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.WriteOffInDepartment(tt.args.ctx, tt.args.writeOffID, tt.args.departmentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WriteOffInDepartment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("WriteOffInDepartment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEquipmentRepository_List_scope(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateLocations(t, testDB)
+		truncateEmployees(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	other := addTestDepartment(t, testDB)
+	e := addTestEmployee(t, testDB)
+	setTestEmployeeDepartment(t, testDB, e.ID, d.ID)
+	atDepartment := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, atDepartment.ID, u.ID, d.ID)
+	atEmployee := addTestEquipment(t, testDB)
+	addTestEmployeeLocation(t, testDB, atEmployee.ID, u.ID, e.ID)
+	atOther := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, atOther.ID, u.ID, other.ID)
+	inStorage := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, inStorage.ID, u.ID, 0)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx context.Context
+		qp  *dto.QueryParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*model.Equipment
+		want1   int64
+		wantErr bool
+	}{
+		{
+			name: "list equipments of every department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				qp: &dto.QueryParams{
+					SortColumn:      "id",
+					SortOrder:       "asc",
+					PaginationLimit: 50,
+				},
+			},
+			want:    []*model.Equipment{atDepartment, atEmployee, atOther, inStorage},
+			want1:   4,
+			wantErr: false,
+		},
+		{
+			name: "list equipments of department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				qp: &dto.QueryParams{
+					SortColumn:        "id",
+					SortOrder:         "asc",
+					PaginationLimit:   50,
+					ScopeDepartmentID: d.ID,
+				},
+			},
+			want:    []*model.Equipment{atDepartment, atEmployee},
+			want1:   2,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EquipmentRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, got1, err := r.List(tt.args.ctx, tt.args.qp)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("List() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
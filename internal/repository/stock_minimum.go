@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type StockMinimumRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewStockMinimumRepository(postgresDB *pgxpool.Pool) *StockMinimumRepository {
+	return &StockMinimumRepository{postgresDB: postgresDB}
+}
+
+// Set creates the minimum for the profile and place or changes the quantity
+// of the existing one.
+func (r *StockMinimumRepository) Set(ctx context.Context, minimum *queries.SetStockMinimumParams) (int64, error) {
+	id, err := queries.New(r.postgresDB).SetStockMinimum(ctx, minimum)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	return id, nil
+}
+
+func (r *StockMinimumRepository) Delete(ctx context.Context, id int64) error {
+	ct, err := queries.New(r.postgresDB).DeleteStockMinimum(ctx, id)
+	if err != nil {
+		return logger.Error(logger.MsgFailedToDelete, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return logger.Error(logger.MsgFailedToDelete, logger.ErrNoRowsAffected)
+	}
+
+	return nil
+}
+
+// List returns the minimums with the current holdings, only the ones below
+// the minimum if lowOnly is set.
+func (r *StockMinimumRepository) List(ctx context.Context, lowOnly bool) ([]*model.StockMinimum, error) {
+	req, err := queries.New(r.postgresDB).ListStockLevels(ctx, lowOnly)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	list := make([]*model.StockMinimum, len(req))
+	for i, item := range req {
+		minimum := &model.StockMinimum{
+			ID: item.ID,
+			Profile: &model.Profile{
+				ID:    item.ProfileID,
+				Title: item.ProfileTitle,
+			},
+			MinQuantity: item.MinQuantity,
+			Quantity:    item.Quantity,
+			LowSince:    validTime(item.LowSince),
+		}
+		if item.DepartmentID.Valid {
+			minimum.Department = &model.Department{
+				ID:    item.DepartmentID.Int64,
+				Title: validString(item.DepartmentTitle),
+			}
+		}
+		list[i] = minimum
+	}
+
+	return list, nil
+}
+
+// MarkLow flags the given minimums as low and clears the flag on the rest,
+// returning the ids that were not flagged before.
+func (r *StockMinimumRepository) MarkLow(ctx context.Context, ids []int64) ([]int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return nil, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	if err := q.ClearStockMinimumsLow(ctx, ids); err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	marked, err := q.MarkStockMinimumsLow(ctx, ids)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, logger.Error("", err)
+	}
+
+	return marked, nil
+}
+
+// Recipients returns the enabled users with an email and one of the given
+// roles.
+func (r *StockMinimumRepository) Recipients(ctx context.Context, roles []role.Role) ([]*model.User, error) {
+	ids := make([]int32, len(roles))
+	for i, item := range roles {
+		ids[i] = int32(item)
+	}
+
+	req, err := queries.New(r.postgresDB).ListUserEmailsByRoles(ctx, ids)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	list := make([]*model.User, len(req))
+	for i, item := range req {
+		list[i] = &model.User{
+			Username: item.Username,
+			Email:    item.Email,
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+)
+
+func truncateStockMinimums(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE stock_minimums
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate stock minimum: %v", err)
+	}
+}
+
+func addTestStockMinimum(t *testing.T, testDB *pgxpool.Pool, profileID, departmentID int64, minQuantity int32) int64 {
+	t.Helper()
+	id, err := NewStockMinimumRepository(testDB).Set(t.Context(), &queries.SetStockMinimumParams{
+		ProfileID:    profileID,
+		DepartmentID: pgtype.Int8{Int64: departmentID, Valid: departmentID != 0},
+		MinQuantity:  minQuantity,
+	})
+	if err != nil {
+		t.Fatalf("failed to insert test stock minimum: %v", err)
+	}
+
+	return id
+}
+
+func TestNewStockMinimumRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *StockMinimumRepository
+	}{
+		{
+			name: "create stock minimum repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewStockMinimumRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewStockMinimumRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewStockMinimumRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStockMinimumRepository_Set(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateStockMinimums(t, testDB)
+		truncateEquipments(t, testDB)
+		truncateDepartments(t, testDB)
+		testDB.Close()
+	})
+	p := addTestProfile(t, testDB)
+	d := addTestDepartment(t, testDB)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		minimum *queries.SetStockMinimumParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "set storage minimum",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				minimum: &queries.SetStockMinimumParams{
+					ProfileID:   p.ID,
+					MinQuantity: 2,
+				},
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "change storage minimum",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				minimum: &queries.SetStockMinimumParams{
+					ProfileID:   p.ID,
+					MinQuantity: 3,
+				},
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "set department minimum",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				minimum: &queries.SetStockMinimumParams{
+					ProfileID:    p.ID,
+					DepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+					MinQuantity:  1,
+				},
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "set zero minimum",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				minimum: &queries.SetStockMinimumParams{
+					ProfileID: p.ID,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StockMinimumRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Set(tt.args.ctx, tt.args.minimum)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Set() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Set() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStockMinimumRepository_List(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateStockMinimums(t, testDB)
+		truncateReservations(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	stored := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, stored.ID, u.ID, 0)
+	reserved := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, reserved.ID, u.ID, 0)
+	addTestReservation(t, testDB, reserved.ID, u.ID, d.ID)
+	addTestStockMinimum(t, testDB, stored.Profile.ID, 0, 1)
+	addTestStockMinimum(t, testDB, stored.Profile.ID, d.ID, 1)
+	addTestStockMinimum(t, testDB, reserved.Profile.ID, 0, 1)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx     context.Context
+		lowOnly bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "list all minimums",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:     t.Context(),
+				lowOnly: false,
+			},
+			want:    []int64{0, 0, 1},
+			wantErr: false,
+		},
+		{
+			name: "list low stock without reserved",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:     t.Context(),
+				lowOnly: true,
+			},
+			want:    []int64{0, 0},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StockMinimumRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.List(tt.args.ctx, tt.args.lowOnly)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			quantities := make([]int64, len(got))
+			for i, item := range got {
+				quantities[i] = item.Quantity
+			}
+			slices.Sort(quantities)
+			if !reflect.DeepEqual(quantities, tt.want) {
+				t.Errorf("List() got = %v, want %v", quantities, tt.want)
+			}
+		})
+	}
+}
+
+func TestStockMinimumRepository_MarkLow(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateStockMinimums(t, testDB)
+		truncateEquipments(t, testDB)
+		testDB.Close()
+	})
+	p := addTestProfile(t, testDB)
+	first := addTestStockMinimum(t, testDB, p.ID, 0, 1)
+	d := addTestDepartment(t, testDB)
+	second := addTestStockMinimum(t, testDB, p.ID, d.ID, 1)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx context.Context
+		ids []int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "mark new low stock",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				ids: []int64{first, second},
+			},
+			want:    []int64{first, second},
+			wantErr: false,
+		},
+		{
+			name: "skip already low stock",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				ids: []int64{first},
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "mark refilled stock low again",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				ids: []int64{first, second},
+			},
+			want:    []int64{second},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StockMinimumRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.MarkLow(tt.args.ctx, tt.args.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarkLow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarkLow() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type StocktakingRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewStocktakingRepository(postgresDB *pgxpool.Pool) *StocktakingRepository {
+	return &StocktakingRepository{postgresDB: postgresDB}
+}
+
+// Create opens a session and snapshots the equipment that is currently
+// at its department, employee or storage.
+func (r *StocktakingRepository) Create(ctx context.Context, stocktaking *queries.CreateStocktakingParams) (int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	st, err := q.CreateStocktaking(ctx, stocktaking)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	param, paramID := list_filter.ParamDepartment, stocktaking.DepartmentID.Int64
+	if stocktaking.EmployeeID.Valid {
+		param, paramID = list_filter.ParamEmployee, stocktaking.EmployeeID.Int64
+	}
+
+	list, err := q.ListEquipmentFromLocation(ctx, &queries.ListEquipmentFromLocationParams{
+		Param:           param,
+		ParamID:         paramID,
+		PaginationLimit: math.MaxInt32,
+	})
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	ids := make([]int64, len(list))
+	for i, item := range list {
+		ids[i] = validInt64(item.ID)
+	}
+
+	if _, err := q.CreateStocktakingItems(ctx, &queries.CreateStocktakingItemsParams{
+		StocktakingID: st.ID,
+		EquipmentIds:  ids,
+	}); err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return st.ID, nil
+}
+
+func (r *StocktakingRepository) Read(ctx context.Context, id int64) (*model.Stocktaking, error) {
+	req, err := queries.New(r.postgresDB).GetStocktaking(ctx, id)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	stocktaking := &model.Stocktaking{
+		ID:        req.ID,
+		OpenedBy:  &model.User{ID: req.OpenedBy},
+		OpenedAt:  validTime(req.OpenedAt),
+		ClosedAt:  validTime(req.ClosedAt),
+		AppliedAt: validTime(req.AppliedAt),
+		Expected:  req.Expected,
+		Scanned:   req.Scanned,
+	}
+
+	if req.DepartmentID.Valid {
+		stocktaking.Department = &model.Department{ID: req.DepartmentID.Int64}
+	}
+	if req.EmployeeID.Valid {
+		stocktaking.Employee = &model.Employee{ID: req.EmployeeID.Int64}
+	}
+	if req.ClosedBy.Valid {
+		stocktaking.ClosedBy = &model.User{ID: req.ClosedBy.Int64}
+	}
+
+	return stocktaking, nil
+}
+
+// Scan stores scanned serial numbers of an open session and returns how many
+// of them are new; repeated scans are ignored.
+func (r *StocktakingRepository) Scan(ctx context.Context, scans *queries.CreateStocktakingScansParams) (int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	st, err := q.LockStocktaking(ctx, scans.StocktakingID)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if st.ClosedAt.Valid {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrStocktakingClosed)
+	}
+
+	ct, err := q.CreateStocktakingScans(ctx, scans)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return ct.RowsAffected(), nil
+}
+
+func (r *StocktakingRepository) Close(ctx context.Context, id, closedBy int64) error {
+	ct, err := queries.New(r.postgresDB).CloseStocktaking(ctx, &queries.CloseStocktakingParams{
+		ClosedBy: closedBy,
+		ID:       id,
+	})
+	if err != nil {
+		return logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return logger.Error(logger.MsgFailedToUpdate, logger.ErrStocktakingClosed)
+	}
+
+	return nil
+}
+
+func (r *StocktakingRepository) Discrepancies(ctx context.Context, id int64) ([]*model.StocktakingDiscrepancy, error) {
+	req, err := queries.New(r.postgresDB).ListStocktakingDiscrepancies(ctx, id)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	list := make([]*model.StocktakingDiscrepancy, len(req))
+	for i, item := range req {
+		discrepancy := &model.StocktakingDiscrepancy{
+			Status:       item.Status,
+			SerialNumber: item.SerialNumber,
+		}
+
+		if item.EquipmentID.Valid {
+			discrepancy.Equipment = &model.Equipment{
+				ID:           item.EquipmentID.Int64,
+				SerialNumber: item.SerialNumber,
+			}
+		}
+		if item.ToDepartmentID.Valid {
+			discrepancy.Department = &model.Department{ID: item.ToDepartmentID.Int64}
+		}
+		if item.ToEmployeeID.Valid {
+			discrepancy.Employee = &model.Employee{ID: item.ToEmployeeID.Int64}
+		}
+		if item.ToContractID.Valid {
+			discrepancy.Contract = &model.Contract{ID: item.ToContractID.Int64}
+		}
+
+		list[i] = discrepancy
+	}
+
+	return list, nil
+}
+
+// Apply inserts correcting moves of a closed session in one transaction;
+// every move is checked against the latest location like MoveMany does.
+// Equipment that can not be moved, being reserved, at repair or written
+// off, is returned in skipped by its id instead of failing the session.
+func (r *StocktakingRepository) Apply(ctx context.Context, id int64, locations []*queries.MoveToLocationParams) ([]int64, map[int64]error, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return nil, nil, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	st, err := q.LockStocktaking(ctx, id)
+	if err != nil {
+		return nil, nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if !st.ClosedAt.Valid {
+		return nil, nil, logger.Error(logger.MsgFailedToValidate, logger.ErrStocktakingNotClosed)
+	}
+
+	if st.AppliedAt.Valid {
+		return nil, nil, logger.Error(logger.MsgFailedToValidate, logger.ErrStocktakingApplied)
+	}
+
+	ids := make([]int64, 0, len(locations))
+	skipped := make(map[int64]error)
+	for _, location := range locations {
+		locationID, err := moveInTx(ctx, q, location)
+		if err != nil {
+			// moveInTx checks before it writes, a refused move leaves the
+			// transaction usable
+			if !blockedMove(err) {
+				return nil, nil, err
+			}
+			skipped[location.EquipmentID] = err
+			continue
+		}
+
+		ids = append(ids, locationID)
+	}
+
+	ct, err := q.SetStocktakingApplied(ctx, id)
+	if err != nil {
+		return nil, nil, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return nil, nil, logger.Error(logger.MsgFailedToUpdate, logger.ErrNoRowsAffected)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, logger.Error("", err)
+	}
+
+	return ids, skipped, nil
+}
+
+// blockedMove reports whether moveInTx refused the move for the state of
+// the equipment rather than failed.
+func blockedMove(err error) bool {
+	for _, target := range []error{
+		logger.ErrEquipmentDeleted,
+		logger.ErrEquipmentWrittenOff,
+		logger.ErrEquipmentInRepair,
+		logger.ErrEquipmentReserved,
+		logger.ErrLocationChanged,
+		logger.ErrIllegalMove,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
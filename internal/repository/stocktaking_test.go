@@ -0,0 +1,497 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/serial"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func truncateStocktakings(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE stocktaking_scans, stocktaking_items, stocktakings
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate stocktaking: %v", err)
+	}
+}
+
+func addTestStocktaking(t *testing.T, testDB *pgxpool.Pool, userID, departmentID int64) int64 {
+	t.Helper()
+	id, err := NewStocktakingRepository(testDB).Create(t.Context(), &queries.CreateStocktakingParams{
+		DepartmentID: pgtype.Int8{Int64: departmentID, Valid: departmentID != 0},
+		OpenedBy:     userID,
+	})
+	if err != nil {
+		t.Fatalf("failed to insert test stocktaking: %v", err)
+	}
+
+	return id
+}
+
+func TestNewStocktakingRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *StocktakingRepository
+	}{
+		{
+			name: "create stocktaking repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewStocktakingRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewStocktakingRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewStocktakingRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStocktakingRepository_Create(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateStocktakings(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	in := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, in.ID, u.ID, 0)
+	addTestLocation(t, testDB, in.ID, u.ID, d.ID)
+	out := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, out.ID, u.ID, 0)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx         context.Context
+		stocktaking *queries.CreateStocktakingParams
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		want         int64
+		wantExpected int64
+		wantErr      bool
+	}{
+		{
+			name: "open stocktaking for department",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				stocktaking: &queries.CreateStocktakingParams{
+					DepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+					OpenedBy:     u.ID,
+				},
+			},
+			want:         1,
+			wantExpected: 1,
+			wantErr:      false,
+		},
+		{
+			name: "open stocktaking for storage",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				stocktaking: &queries.CreateStocktakingParams{
+					OpenedBy: u.ID,
+				},
+			},
+			want:         2,
+			wantExpected: 1,
+			wantErr:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StocktakingRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Create(tt.args.ctx, tt.args.stocktaking)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+			read, err := r.Read(tt.args.ctx, got)
+			if err != nil {
+				t.Fatalf("failed to read stocktaking: %v", err)
+			}
+			if read.Expected != tt.wantExpected {
+				t.Errorf("Create() expected = %v, want %v", read.Expected, tt.wantExpected)
+			}
+		})
+	}
+}
+
+func TestStocktakingRepository_Scan(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateStocktakings(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	addTestLocation(t, testDB, e.ID, u.ID, d.ID)
+	other := addTestEquipment(t, testDB)
+	otherSpelling := strings.ToLower(other.SerialNumber[:5]) + "- " + other.SerialNumber[5:]
+	open := addTestStocktaking(t, testDB, u.ID, d.ID)
+	closed := addTestStocktaking(t, testDB, u.ID, d.ID)
+	if err := NewStocktakingRepository(testDB).Close(t.Context(), closed, u.ID); err != nil {
+		t.Fatalf("failed to close test stocktaking: %v", err)
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx   context.Context
+		scans *queries.CreateStocktakingScansParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "scan serial numbers",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				scans: &queries.CreateStocktakingScansParams{
+					StocktakingID:          open,
+					ScannedBy:              u.ID,
+					SerialNumbers:          []string{e.SerialNumber, "unknown serial number"},
+					CanonicalSerialNumbers: []string{serial.Normalize(e.SerialNumber, serial.Canonical), serial.Normalize("unknown serial number", serial.Canonical)},
+				},
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "scan same serial numbers again",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				scans: &queries.CreateStocktakingScansParams{
+					StocktakingID:          open,
+					ScannedBy:              u.ID,
+					SerialNumbers:          []string{e.SerialNumber},
+					CanonicalSerialNumbers: []string{serial.Normalize(e.SerialNumber, serial.Canonical)},
+				},
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "scan one serial number written two ways",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				scans: &queries.CreateStocktakingScansParams{
+					StocktakingID:          open,
+					ScannedBy:              u.ID,
+					SerialNumbers:          []string{other.SerialNumber, otherSpelling},
+					CanonicalSerialNumbers: []string{serial.Normalize(other.SerialNumber, serial.Canonical), serial.Normalize(otherSpelling, serial.Canonical)},
+				},
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "scan into closed stocktaking",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				scans: &queries.CreateStocktakingScansParams{
+					StocktakingID:          closed,
+					ScannedBy:              u.ID,
+					SerialNumbers:          []string{e.SerialNumber},
+					CanonicalSerialNumbers: []string{serial.Normalize(e.SerialNumber, serial.Canonical)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StocktakingRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Scan(tt.args.ctx, tt.args.scans)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Scan() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStocktakingRepository_Discrepancies(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateStocktakings(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	found := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, found.ID, u.ID, 0)
+	addTestLocation(t, testDB, found.ID, u.ID, d.ID)
+	missing := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, missing.ID, u.ID, 0)
+	addTestLocation(t, testDB, missing.ID, u.ID, d.ID)
+	elsewhere := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, elsewhere.ID, u.ID, 0)
+	id := addTestStocktaking(t, testDB, u.ID, d.ID)
+	if _, err := NewStocktakingRepository(testDB).Scan(t.Context(), &queries.CreateStocktakingScansParams{
+		StocktakingID:          id,
+		ScannedBy:              u.ID,
+		SerialNumbers:          []string{found.SerialNumber, elsewhere.SerialNumber, "unknown serial number"},
+		CanonicalSerialNumbers: []string{serial.Normalize(found.SerialNumber, serial.Canonical), serial.Normalize(elsewhere.SerialNumber, serial.Canonical), serial.Normalize("unknown serial number", serial.Canonical)},
+	}); err != nil {
+		t.Fatalf("failed to scan test stocktaking: %v", err)
+	}
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*model.StocktakingDiscrepancy
+		wantErr bool
+	}{
+		{
+			name: "list discrepancies",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				id:  id,
+			},
+			want: []*model.StocktakingDiscrepancy{
+				{
+					Status:       model.StocktakingFoundElsewhere,
+					Equipment:    &model.Equipment{ID: elsewhere.ID, SerialNumber: elsewhere.SerialNumber},
+					SerialNumber: elsewhere.SerialNumber,
+				},
+				{
+					Status:       model.StocktakingMissing,
+					Equipment:    &model.Equipment{ID: missing.ID, SerialNumber: missing.SerialNumber},
+					SerialNumber: missing.SerialNumber,
+					Department:   &model.Department{ID: d.ID},
+				},
+				{
+					Status:       model.StocktakingUnexpected,
+					SerialNumber: "unknown serial number",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StocktakingRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Discrepancies(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Discrepancies() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Discrepancies() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStocktakingRepository_Apply(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateReservations(t, testDB)
+		truncateStocktakings(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	d := addTestDepartment(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	re := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, re.ID, u.ID, 0)
+	addTestReservation(t, testDB, re.ID, u.ID, addTestDepartment(t, testDB).ID)
+	fe := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, fe.ID, u.ID, 0)
+	open := addTestStocktaking(t, testDB, u.ID, d.ID)
+	closed := addTestStocktaking(t, testDB, u.ID, d.ID)
+	blocked := addTestStocktaking(t, testDB, u.ID, d.ID)
+	for _, id := range []int64{closed, blocked} {
+		if err := NewStocktakingRepository(testDB).Close(t.Context(), id, u.ID); err != nil {
+			t.Fatalf("failed to close test stocktaking: %v", err)
+		}
+	}
+
+	newMove := func(equipmentID int64) *queries.MoveToLocationParams {
+		return &queries.MoveToLocationParams{
+			EquipmentID:    equipmentID,
+			UserID:         u.ID,
+			MoveAt:         pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			MoveCode:       "StorageToDepartment",
+			ToDepartmentID: pgtype.Int8{Int64: d.ID, Valid: true},
+		}
+	}
+	move := newMove(e.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx       context.Context
+		id        int64
+		locations []*queries.MoveToLocationParams
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		want        []int64
+		wantSkipped []int64
+		wantErr     bool
+	}{
+		{
+			name: "apply open stocktaking",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        open,
+				locations: []*queries.MoveToLocationParams{move},
+			},
+			wantErr: true,
+		},
+		{
+			name: "apply closed stocktaking",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        closed,
+				locations: []*queries.MoveToLocationParams{move},
+			},
+			want:    []int64{4},
+			wantErr: false,
+		},
+		{
+			name: "apply stocktaking with reserved equipment",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        blocked,
+				locations: []*queries.MoveToLocationParams{newMove(re.ID), newMove(fe.ID)},
+			},
+			want:        []int64{5},
+			wantSkipped: []int64{re.ID},
+			wantErr:     false,
+		},
+		{
+			name: "apply stocktaking twice",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				id:  closed,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &StocktakingRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, skipped, err := r.Apply(tt.args.ctx, tt.args.id, tt.args.locations)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() got = %v, want %v", got, tt.want)
+			}
+			var gotSkipped []int64
+			for id, err := range skipped {
+				if !errors.Is(err, logger.ErrEquipmentReserved) {
+					t.Errorf("Apply() skipped %d with %v, want %v", id, err, logger.ErrEquipmentReserved)
+				}
+				gotSkipped = append(gotSkipped, id)
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("Apply() skipped = %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+		})
+	}
+}
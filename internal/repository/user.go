@@ -6,19 +6,23 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	queries "github.com/oatsmoke/warehouse_backend/internal/db"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/request_info"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 )
 
 type UserRepository struct {
-	queries queries.Querier
+	queries    queries.Querier
+	postgresDB *pgxpool.Pool
 }
 
-func NewUserRepository(queries queries.Querier) *UserRepository {
+func NewUserRepository(queries queries.Querier, postgresDB *pgxpool.Pool) *UserRepository {
 	return &UserRepository{
-		queries: queries,
+		queries:    queries,
+		postgresDB: postgresDB,
 	}
 }
 
@@ -31,7 +35,15 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) (int64, e
 		}
 	}
 
-	req, err := r.queries.CreateUser(ctx, &queries.CreateUserParams{
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	req, err := q.CreateUser(ctx, &queries.CreateUserParams{
 		Username:     user.Username,
 		PasswordHash: user.PasswordHash,
 		Email:        user.Email,
@@ -42,6 +54,19 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) (int64, e
 		return 0, logger.Error(logger.MsgFailedToInsert, err)
 	}
 
+	if err := addOutboxEvent(ctx, q, &model.Event{
+		Type:       model.EventUserCreated,
+		UserID:     request_info.UserID(ctx),
+		EntityType: model.EntityUser,
+		EntityID:   req.ID,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
 	return req.ID, nil
 }
 
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type WriteOffRepository struct {
+	postgresDB *pgxpool.Pool
+}
+
+func NewWriteOffRepository(postgresDB *pgxpool.Pool) *WriteOffRepository {
+	return &WriteOffRepository{postgresDB: postgresDB}
+}
+
+func (r *WriteOffRepository) Create(ctx context.Context, writeOff *queries.CreateWriteOffParams) (int64, error) {
+	id, err := queries.New(r.postgresDB).CreateWriteOff(ctx, writeOff)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToInsert, err)
+	}
+
+	return id, nil
+}
+
+func (r *WriteOffRepository) Read(ctx context.Context, id int64) (*model.WriteOff, error) {
+	req, err := queries.New(r.postgresDB).GetWriteOff(ctx, id)
+	if err != nil {
+		return nil, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	writeOff := &model.WriteOff{
+		ID: req.ID,
+		Equipment: &model.Equipment{
+			ID:           req.EquipmentID,
+			SerialNumber: req.SerialNumber,
+		},
+		Reason:  req.Reason,
+		Comment: validString(req.Comment),
+		Status:  req.Status,
+		RequestedBy: &model.User{
+			ID:       req.RequestedBy,
+			Username: req.RequestedByUsername,
+		},
+		RequestedAt: validTime(req.RequestedAt),
+		DecidedAt:   validTime(req.DecidedAt),
+	}
+	setWriteOffDecision(writeOff, req.DecidedBy, req.DecidedByUsername, req.LocationID)
+
+	return writeOff, nil
+}
+
+func (r *WriteOffRepository) List(ctx context.Context, status string, qp *dto.QueryParams) ([]*model.WriteOff, int64, error) {
+	req, err := queries.New(r.postgresDB).ListWriteOffs(ctx, &queries.ListWriteOffsParams{
+		Status:           status,
+		DepartmentID:     qp.ScopeDepartmentID,
+		PaginationLimit:  qp.PaginationLimit,
+		PaginationOffset: qp.PaginationOffset,
+	})
+	if err != nil {
+		return nil, 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if len(req) < 1 {
+		return []*model.WriteOff{}, 0, nil
+	}
+
+	list := make([]*model.WriteOff, len(req))
+	for i, item := range req {
+		writeOff := &model.WriteOff{
+			ID: item.ID,
+			Equipment: &model.Equipment{
+				ID:           item.EquipmentID,
+				SerialNumber: item.SerialNumber,
+			},
+			Reason:  item.Reason,
+			Comment: validString(item.Comment),
+			Status:  item.Status,
+			RequestedBy: &model.User{
+				ID:       item.RequestedBy,
+				Username: item.RequestedByUsername,
+			},
+			RequestedAt: validTime(item.RequestedAt),
+			DecidedAt:   validTime(item.DecidedAt),
+		}
+		setWriteOffDecision(writeOff, item.DecidedBy, item.DecidedByUsername, item.LocationID)
+		list[i] = writeOff
+	}
+
+	return list, req[0].Total, nil
+}
+
+// Approve records the final move of a pending request and marks the
+// equipment written off; the move is checked like MoveMany does.
+func (r *WriteOffRepository) Approve(ctx context.Context, id, decidedBy int64, location *queries.MoveToLocationParams) (int64, error) {
+	tx, err := r.postgresDB.Begin(ctx)
+	if err != nil {
+		return 0, logger.Error("", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	writeOff, err := q.LockWriteOff(ctx, id)
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToSelect, err)
+	}
+
+	if writeOff.Status != model.WriteOffPending {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrWriteOffDecided)
+	}
+
+	if writeOff.EquipmentID != location.EquipmentID {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrLocationChanged)
+	}
+
+	locationID, err := moveInTx(ctx, q, location)
+	if err != nil {
+		return 0, err
+	}
+
+	ct, err := q.SetEquipmentWrittenOff(ctx, &queries.SetEquipmentWrittenOffParams{
+		WrittenOffAt: location.MoveAt,
+		ID:           location.EquipmentID,
+	})
+	if err != nil {
+		return 0, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return 0, logger.Error(logger.MsgFailedToUpdate, logger.ErrEquipmentWrittenOff)
+	}
+
+	if _, err := q.DecideWriteOff(ctx, &queries.DecideWriteOffParams{
+		Status:     model.WriteOffApproved,
+		DecidedBy:  decidedBy,
+		LocationID: pgtype.Int8{Int64: locationID, Valid: true},
+		ID:         id,
+	}); err != nil {
+		return 0, logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, logger.Error("", err)
+	}
+
+	return locationID, nil
+}
+
+func (r *WriteOffRepository) Reject(ctx context.Context, id, decidedBy int64) error {
+	ct, err := queries.New(r.postgresDB).DecideWriteOff(ctx, &queries.DecideWriteOffParams{
+		Status:    model.WriteOffRejected,
+		DecidedBy: decidedBy,
+		ID:        id,
+	})
+	if err != nil {
+		return logger.Error(logger.MsgFailedToUpdate, err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return logger.Error(logger.MsgFailedToUpdate, logger.ErrWriteOffDecided)
+	}
+
+	return nil
+}
+
+func setWriteOffDecision(writeOff *model.WriteOff, decidedBy pgtype.Int8, username pgtype.Text, locationID pgtype.Int8) {
+	if decidedBy.Valid {
+		writeOff.DecidedBy = &model.User{
+			ID:       decidedBy.Int64,
+			Username: validString(username),
+		}
+	}
+	if locationID.Valid {
+		writeOff.Location = &model.Location{ID: locationID.Int64}
+	}
+}
@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/postgresql"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func truncateWriteOffs(t *testing.T, testDB *pgxpool.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const query = `
+		TRUNCATE write_offs
+		RESTART IDENTITY CASCADE;`
+
+	if _, err := testDB.Exec(ctx, query); err != nil {
+		t.Fatalf("failed to truncate write-off: %v", err)
+	}
+}
+
+func addTestWriteOff(t *testing.T, testDB *pgxpool.Pool, equipmentID, userID int64) int64 {
+	t.Helper()
+	id, err := NewWriteOffRepository(testDB).Create(t.Context(), &queries.CreateWriteOffParams{
+		EquipmentID: equipmentID,
+		Reason:      model.WriteOffBroken,
+		RequestedBy: userID,
+	})
+	if err != nil {
+		t.Fatalf("failed to insert test write-off: %v", err)
+	}
+
+	return id
+}
+
+func testWriteOffMove(equipmentID, userID int64) *queries.MoveToLocationParams {
+	return &queries.MoveToLocationParams{
+		EquipmentID: equipmentID,
+		UserID:      userID,
+		MoveAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		MoveCode:    "WrittenOff",
+	}
+}
+
+func TestNewWriteOffRepository(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	defer testDB.Close()
+
+	type args struct {
+		postgresDB *pgxpool.Pool
+	}
+	tests := []struct {
+		name string
+		args args
+		want *WriteOffRepository
+	}{
+		{
+			name: "create write-off repository",
+			args: args{
+				postgresDB: testDB,
+			},
+			want: NewWriteOffRepository(testDB),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewWriteOffRepository(tt.args.postgresDB); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewWriteOffRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteOffRepository_Create(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateWriteOffs(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	e := addTestEquipment(t, testDB)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx      context.Context
+		writeOff *queries.CreateWriteOffParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "request write-off",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				writeOff: &queries.CreateWriteOffParams{
+					EquipmentID: e.ID,
+					Reason:      model.WriteOffLost,
+					RequestedBy: u.ID,
+				},
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "request second pending write-off",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx: t.Context(),
+				writeOff: &queries.CreateWriteOffParams{
+					EquipmentID: e.ID,
+					Reason:      model.WriteOffStolen,
+					RequestedBy: u.ID,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &WriteOffRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Create(tt.args.ctx, tt.args.writeOff)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Create() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteOffRepository_Approve(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateWriteOffs(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	e := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, e.ID, u.ID, 0)
+	pending := addTestWriteOff(t, testDB, e.ID, u.ID)
+	moved := addTestEquipment(t, testDB)
+	addTestLocation(t, testDB, moved.ID, u.ID, 0)
+	d := addTestDepartment(t, testDB)
+	addTestLocation(t, testDB, moved.ID, u.ID, d.ID)
+	changed := addTestWriteOff(t, testDB, moved.ID, u.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx       context.Context
+		id        int64
+		decidedBy int64
+		location  *queries.MoveToLocationParams
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "approve pending write-off",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        pending,
+				decidedBy: u.ID,
+				location:  testWriteOffMove(e.ID, u.ID),
+			},
+			wantErr: false,
+		},
+		{
+			name: "approve decided write-off",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        pending,
+				decidedBy: u.ID,
+				location:  testWriteOffMove(e.ID, u.ID),
+			},
+			wantErr: true,
+		},
+		{
+			name: "approve write-off from stale location",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        changed,
+				decidedBy: u.ID,
+				location:  testWriteOffMove(moved.ID, u.ID),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &WriteOffRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			got, err := r.Approve(tt.args.ctx, tt.args.id, tt.args.decidedBy, tt.args.location)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Approve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			read, err := r.Read(tt.args.ctx, tt.args.id)
+			if err != nil {
+				t.Fatalf("failed to read write-off: %v", err)
+			}
+			if read.Status != model.WriteOffApproved || read.Location == nil || read.Location.ID != got {
+				t.Errorf("Approve() got = %v, want approved with location %v", read, got)
+			}
+		})
+	}
+}
+
+func TestWriteOffRepository_Reject(t *testing.T) {
+	testDB := postgresql.ConnectTest()
+	t.Cleanup(func() {
+		truncateWriteOffs(t, testDB)
+		truncateLocations(t, testDB)
+		testDB.Close()
+	})
+	u := addTestUser(t, testDB)
+	e := addTestEquipment(t, testDB)
+	id := addTestWriteOff(t, testDB, e.ID, u.ID)
+
+	type fields struct {
+		postgresDB *pgxpool.Pool
+	}
+	type args struct {
+		ctx       context.Context
+		id        int64
+		decidedBy int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "reject pending write-off",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        id,
+				decidedBy: u.ID,
+			},
+			wantErr: false,
+		},
+		{
+			name: "reject decided write-off",
+			fields: fields{
+				postgresDB: testDB,
+			},
+			args: args{
+				ctx:       t.Context(),
+				id:        id,
+				decidedBy: u.ID,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &WriteOffRepository{
+				postgresDB: tt.fields.postgresDB,
+			}
+			if err := r.Reject(tt.args.ctx, tt.args.id, tt.args.decidedBy); (err != nil) != tt.wantErr {
+				t.Errorf("Reject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"reflect"
+
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/request_info"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+type AuditService struct {
+	auditRepository repository.Audit
+}
+
+func NewAuditService(auditRepository repository.Audit) *AuditService {
+	return &AuditService{
+		auditRepository: auditRepository,
+	}
+}
+
+func (s *AuditService) List(ctx context.Context, req *dto.AuditRequest, qp *dto.QueryParams) (*dto.ListResponse[[]*model.AuditEntry], error) {
+	filter := &queries.ListAuditLogParams{
+		UserID:           req.UserID,
+		Entity:           req.Entity,
+		EntityID:         req.EntityID,
+		Action:           req.Action,
+		RequestID:        req.RequestID,
+		PaginationLimit:  qp.PaginationLimit,
+		PaginationOffset: qp.PaginationOffset,
+	}
+
+	var err error
+	if req.DateFrom != "" {
+		if filter.DateFrom, err = parseMoveAt(req.DateFrom); err != nil {
+			return nil, err
+		}
+	}
+	if req.DateTo != "" {
+		if filter.DateTo, err = parseMoveAt(req.DateTo); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.DateFrom.Valid && filter.DateTo.Valid && !filter.DateFrom.Time.Before(filter.DateTo.Time) {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrInvalidDateRange)
+	}
+
+	list, total, err := s.auditRepository.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d audit entries listed", len(list)))
+	return &dto.ListResponse[[]*model.AuditEntry]{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+// audit records a mutation of the entity by the user of the request with
+// the fields that differ between before and after; before is nil for a
+// creation and after for a deletion. The mutation is already done when it
+// is recorded, so a failed record is only logged.
+func audit(ctx context.Context, auditRepository repository.Audit, entity, action string, entityID int64, before, after any) {
+	diff, err := auditDiff(before, after)
+	if err != nil {
+		logger.Error(logger.MsgFailedToMarshal, err)
+		return
+	}
+
+	entry := &queries.CreateAuditLogParams{
+		UserID:    toPGTypeInt8(request_info.UserID(ctx)),
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		Diff:      diff,
+		RequestID: toPGTypeText(request_info.RequestID(ctx)),
+	}
+	if ip, err := netip.ParseAddr(request_info.ClientIP(ctx)); err == nil {
+		entry.ClientIp = &ip
+	}
+
+	// the repository logs the error
+	_ = auditRepository.Create(ctx, entry)
+}
+
+// auditState reads the entity for its audit record, a failed read leaves
+// the state out.
+func auditState[T any](ctx context.Context, read func(context.Context, int64) (T, error), id int64) any {
+	state, err := read(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return state
+}
+
+// auditDiff compares the top level fields of the JSON forms of before and
+// after and returns the changed ones as model.AuditChange.
+func auditDiff(before, after any) ([]byte, error) {
+	b, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]*model.AuditChange)
+	for k, v := range b {
+		if !reflect.DeepEqual(v, a[k]) {
+			diff[k] = &model.AuditChange{Before: v, After: a[k]}
+		}
+	}
+	for k, v := range a {
+		if _, ok := b[k]; !ok {
+			diff[k] = &model.AuditChange{After: v}
+		}
+	}
+
+	return json.Marshal(diff)
+}
+
+func auditFields(state any) (map[string]any, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// auditPlace is the state of equipment in its audit record, an empty id
+// is null.
+func auditPlace(p place, code moveCode) map[string]any {
+	state := map[string]any{
+		"department_id": toPGTypeInt8(p.departmentID),
+		"employee_id":   toPGTypeInt8(p.employeeID),
+		"contract_id":   toPGTypeInt8(p.contractID),
+	}
+	if code != "" {
+		state["move_code"] = code
+	}
+	return state
+}
+
+// auditMove records a move as the change of the place of the equipment.
+func auditMove(ctx context.Context, auditRepository repository.Audit, action string, move *queries.MoveToLocationParams) {
+	from := place{
+		departmentID: move.FromDepartmentID.Int64,
+		employeeID:   move.FromEmployeeID.Int64,
+		contractID:   move.FromContractID.Int64,
+	}
+	to := place{
+		departmentID: move.ToDepartmentID.Int64,
+		employeeID:   move.ToEmployeeID.Int64,
+		contractID:   move.ToContractID.Int64,
+	}
+
+	audit(ctx, auditRepository, model.EntityEquipment, action, move.EquipmentID, auditPlace(from, ""), auditPlace(to, moveCode(move.MoveCode)))
+}
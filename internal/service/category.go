@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
-	"github.com/oatsmoke/warehouse_backend/internal/lib/kafka"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/oatsmoke/warehouse_backend/internal/repository"
@@ -13,11 +12,13 @@ import (
 
 type CategoryService struct {
 	categoryRepository repository.Category
+	auditRepository    repository.Audit
 }
 
-func NewCategoryService(categoryRepository repository.Category) *CategoryService {
+func NewCategoryService(categoryRepository repository.Category, auditRepository repository.Audit) *CategoryService {
 	return &CategoryService{
 		categoryRepository: categoryRepository,
+		auditRepository:    auditRepository,
 	}
 }
 
@@ -27,8 +28,9 @@ func (s *CategoryService) Create(ctx context.Context, category *model.Category)
 		return err
 	}
 
-	//logger.Info(fmt.Sprintf("category with id %d created", id))
-	kafka.SendMessage(fmt.Sprintf("category with id %d created", id))
+	audit(ctx, s.auditRepository, model.EntityCategory, model.AuditCreate, id, nil, auditState(ctx, s.categoryRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("category with id %d created", id))
 	return nil
 }
 
@@ -43,28 +45,37 @@ func (s *CategoryService) Read(ctx context.Context, id int64) (*model.Category,
 }
 
 func (s *CategoryService) Update(ctx context.Context, category *model.Category) error {
+	before := auditState(ctx, s.categoryRepository.Read, category.ID)
 	if err := s.categoryRepository.Update(ctx, category); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityCategory, model.AuditUpdate, category.ID, before, auditState(ctx, s.categoryRepository.Read, category.ID))
+
 	logger.Info(fmt.Sprintf("category with id %d updated", category.ID))
 	return nil
 }
 
 func (s *CategoryService) Delete(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.categoryRepository.Read, id)
 	if err := s.categoryRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityCategory, model.AuditDelete, id, before, auditState(ctx, s.categoryRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("category with id %d deleted", id))
 	return nil
 }
 
 func (s *CategoryService) Restore(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.categoryRepository.Read, id)
 	if err := s.categoryRepository.Restore(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityCategory, model.AuditRestore, id, before, auditState(ctx, s.categoryRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("category with id %d restored", id))
 	return nil
 }
@@ -12,11 +12,13 @@ import (
 
 type CompanyService struct {
 	companyRepository repository.Company
+	auditRepository   repository.Audit
 }
 
-func NewCompanyService(companyRepository repository.Company) *CompanyService {
+func NewCompanyService(companyRepository repository.Company, auditRepository repository.Audit) *CompanyService {
 	return &CompanyService{
 		companyRepository: companyRepository,
+		auditRepository:   auditRepository,
 	}
 }
 
@@ -26,6 +28,8 @@ func (s *CompanyService) Create(ctx context.Context, company *model.Company) err
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityCompany, model.AuditCreate, id, nil, auditState(ctx, s.companyRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("company with id %d created", id))
 	return nil
 }
@@ -41,28 +45,37 @@ func (s *CompanyService) Read(ctx context.Context, id int64) (*model.Company, er
 }
 
 func (s *CompanyService) Update(ctx context.Context, company *model.Company) error {
+	before := auditState(ctx, s.companyRepository.Read, company.ID)
 	if err := s.companyRepository.Update(ctx, company); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityCompany, model.AuditUpdate, company.ID, before, auditState(ctx, s.companyRepository.Read, company.ID))
+
 	logger.Info(fmt.Sprintf("company with id %d updated", company.ID))
 	return nil
 }
 
 func (s *CompanyService) Delete(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.companyRepository.Read, id)
 	if err := s.companyRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityCompany, model.AuditDelete, id, before, auditState(ctx, s.companyRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("company with id %d deleted", id))
 	return nil
 }
 
 func (s *CompanyService) Restore(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.companyRepository.Read, id)
 	if err := s.companyRepository.Restore(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityCompany, model.AuditRestore, id, before, auditState(ctx, s.companyRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("company with id %d restored", id))
 	return nil
 }
@@ -12,11 +12,13 @@ import (
 
 type ContractService struct {
 	contractRepository repository.Contract
+	auditRepository    repository.Audit
 }
 
-func NewContractService(contractRepository repository.Contract) *ContractService {
+func NewContractService(contractRepository repository.Contract, auditRepository repository.Audit) *ContractService {
 	return &ContractService{
 		contractRepository: contractRepository,
+		auditRepository:    auditRepository,
 	}
 }
 
@@ -26,6 +28,8 @@ func (s *ContractService) Create(ctx context.Context, contract *model.Contract)
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityContract, model.AuditCreate, id, nil, auditState(ctx, s.contractRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("contract with id %d created", id))
 	return nil
 }
@@ -41,28 +45,37 @@ func (s *ContractService) Read(ctx context.Context, id int64) (*model.Contract,
 }
 
 func (s *ContractService) Update(ctx context.Context, contract *model.Contract) error {
+	before := auditState(ctx, s.contractRepository.Read, contract.ID)
 	if err := s.contractRepository.Update(ctx, contract); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityContract, model.AuditUpdate, contract.ID, before, auditState(ctx, s.contractRepository.Read, contract.ID))
+
 	logger.Info(fmt.Sprintf("contract with id %d updated", contract.ID))
 	return nil
 }
 
 func (s *ContractService) Delete(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.contractRepository.Read, id)
 	if err := s.contractRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityContract, model.AuditDelete, id, before, auditState(ctx, s.contractRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("contract with id %d deleted", id))
 	return nil
 }
 
 func (s *ContractService) Restore(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.contractRepository.Read, id)
 	if err := s.contractRepository.Restore(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityContract, model.AuditRestore, id, before, auditState(ctx, s.contractRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("contract with id %d restored", id))
 	return nil
 }
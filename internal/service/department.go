@@ -12,11 +12,13 @@ import (
 
 type DepartmentService struct {
 	departmentRepository repository.Department
+	auditRepository      repository.Audit
 }
 
-func NewDepartmentService(departmentRepository repository.Department) *DepartmentService {
+func NewDepartmentService(departmentRepository repository.Department, auditRepository repository.Audit) *DepartmentService {
 	return &DepartmentService{
 		departmentRepository: departmentRepository,
+		auditRepository:      auditRepository,
 	}
 }
 
@@ -26,6 +28,8 @@ func (s *DepartmentService) Create(ctx context.Context, department *model.Depart
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityDepartment, model.AuditCreate, id, nil, auditState(ctx, s.departmentRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("department with id %d created", id))
 	return nil
 }
@@ -41,28 +45,37 @@ func (s *DepartmentService) Read(ctx context.Context, id int64) (*model.Departme
 }
 
 func (s *DepartmentService) Update(ctx context.Context, department *model.Department) error {
+	before := auditState(ctx, s.departmentRepository.Read, department.ID)
 	if err := s.departmentRepository.Update(ctx, department); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityDepartment, model.AuditUpdate, department.ID, before, auditState(ctx, s.departmentRepository.Read, department.ID))
+
 	logger.Info(fmt.Sprintf("department with id %d updated", department.ID))
 	return nil
 }
 
 func (s *DepartmentService) Delete(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.departmentRepository.Read, id)
 	if err := s.departmentRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityDepartment, model.AuditDelete, id, before, auditState(ctx, s.departmentRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("department with id %d deleted", id))
 	return nil
 }
 
 func (s *DepartmentService) Restore(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.departmentRepository.Read, id)
 	if err := s.departmentRepository.Restore(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityDepartment, model.AuditRestore, id, before, auditState(ctx, s.departmentRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("department with id %d restored", id))
 	return nil
 }
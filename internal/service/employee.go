@@ -12,11 +12,13 @@ import (
 
 type EmployeeService struct {
 	employeeRepository repository.Employee
+	auditRepository    repository.Audit
 }
 
-func NewEmployeeService(employeeRepository repository.Employee) *EmployeeService {
+func NewEmployeeService(employeeRepository repository.Employee, auditRepository repository.Audit) *EmployeeService {
 	return &EmployeeService{
 		employeeRepository: employeeRepository,
+		auditRepository:    auditRepository,
 	}
 }
 
@@ -26,6 +28,8 @@ func (s *EmployeeService) Create(ctx context.Context, employee *model.Employee)
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityEmployee, model.AuditCreate, id, nil, auditState(ctx, s.employeeRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("employee with id %d created", id))
 	return nil
 }
@@ -41,28 +45,37 @@ func (s *EmployeeService) Read(ctx context.Context, id int64) (*model.Employee,
 }
 
 func (s *EmployeeService) Update(ctx context.Context, employee *model.Employee) error {
+	before := auditState(ctx, s.employeeRepository.Read, employee.ID)
 	if err := s.employeeRepository.Update(ctx, employee); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityEmployee, model.AuditUpdate, employee.ID, before, auditState(ctx, s.employeeRepository.Read, employee.ID))
+
 	logger.Info(fmt.Sprintf("employee with id %d updated", employee.ID))
 	return nil
 }
 
 func (s *EmployeeService) Delete(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.employeeRepository.Read, id)
 	if err := s.employeeRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityEmployee, model.AuditDelete, id, before, auditState(ctx, s.employeeRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("employee with id %d deleted", id))
 	return nil
 }
 
 func (s *EmployeeService) Restore(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.employeeRepository.Read, id)
 	if err := s.employeeRepository.Restore(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityEmployee, model.AuditRestore, id, before, auditState(ctx, s.employeeRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("employee with id %d restored", id))
 	return nil
 }
@@ -81,10 +94,13 @@ func (s *EmployeeService) List(ctx context.Context, qp *dto.QueryParams) (*dto.L
 }
 
 func (s *EmployeeService) SetDepartment(ctx context.Context, id, departmentID int64) error {
+	before := auditState(ctx, s.employeeRepository.Read, id)
 	if err := s.employeeRepository.SetDepartment(ctx, id, departmentID); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityEmployee, model.AuditSetDepartment, id, before, auditState(ctx, s.employeeRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("employee with id %d set department id %d", id, departmentID))
 	return nil
 }
@@ -2,46 +2,113 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
+	"strings"
 
-	"github.com/jackc/pgx/v5/pgtype"
 	queries "github.com/oatsmoke/warehouse_backend/internal/db"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/label"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/serial"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/oatsmoke/warehouse_backend/internal/repository"
 )
 
 type EquipmentService struct {
-	equipmentRepository repository.Equipment
-	locationRepository  repository.Location
+	equipmentRepository   repository.Equipment
+	locationRepository    repository.Location
+	companyRepository     repository.Company
+	profileRepository     repository.Profile
+	repairOrderRepository repository.RepairOrder
+	auditRepository       repository.Audit
+	scopeRepository       repository.Scope
+	hub                   *websocket.Hub
 }
 
-func NewEquipmentService(equipmentRepository repository.Equipment, locationRepository repository.Location) *EquipmentService {
+func NewEquipmentService(
+	equipmentRepository repository.Equipment,
+	locationRepository repository.Location,
+	companyRepository repository.Company,
+	profileRepository repository.Profile,
+	repairOrderRepository repository.RepairOrder,
+	auditRepository repository.Audit,
+	scopeRepository repository.Scope,
+	hub *websocket.Hub,
+) *EquipmentService {
 	return &EquipmentService{
-		equipmentRepository: equipmentRepository,
-		locationRepository:  locationRepository,
+		equipmentRepository:   equipmentRepository,
+		locationRepository:    locationRepository,
+		companyRepository:     companyRepository,
+		profileRepository:     profileRepository,
+		repairOrderRepository: repairOrderRepository,
+		auditRepository:       auditRepository,
+		scopeRepository:       scopeRepository,
+		hub:                   hub,
 	}
 }
 
-func (s *EquipmentService) Create(ctx context.Context, userId int64, req *dto.CreateEquipmentRequest) error {
-	d, err := time.Parse(time.RFC3339, req.Date)
+// Create adds equipment with the given serial numbers to storage and, when
+// ParamID is set, moves it to the department. Serial numbers are normalised
+// by the profile rule, every one is created in its own transaction unless
+// AllOrNothing is requested. A department scoped user adds equipment to the
+// own department only.
+func (s *EquipmentService) Create(ctx context.Context, userId int64, req *dto.CreateEquipmentRequest) (*dto.CreateEquipmentResponse, error) {
+	moveAt, err := parseMoveAt(req.Date)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	var move *queries.MoveToLocationParams
+	to := place{departmentID: req.ParamID}
+	if err := sc.place(ctx, to); err != nil {
+		return nil, err
 	}
-	date := pgtype.Timestamptz{
-		Time:  d,
-		Valid: true,
+	if to.departmentID != 0 {
+		code, err := nextMoveCode(place{}, to)
+		if err != nil {
+			return nil, err
+		}
+		move = newMove(0, userId, moveAt, code, place{}, to)
 	}
 
 	l := &queries.AddToStorageParams{
 		UserID:   userId,
-		MoveAt:   date,
-		MoveCode: "AddToStorage",
+		MoveAt:   moveAt,
+		MoveCode: string(addToStorage),
+	}
+
+	rules, err := s.profileRepository.SerialRules(ctx, []int64{req.ProfileID})
+	if err != nil {
+		return nil, err
 	}
 
+	res := &dto.CreateEquipmentResponse{
+		Created: make([]*dto.CreatedEquipment, 0, len(req.SerialNumbers)),
+		Failed:  make([]*dto.FailedEquipment, 0),
+	}
+
+	if req.AllOrNothing {
+		created, err := s.createAll(ctx, req, rules[req.ProfileID], l, move, res)
+		if err == nil {
+			s.publishCreated(created.Created, to)
+		}
+		return created, err
+	}
+
+	seen := make(map[string]bool, len(req.SerialNumbers))
 	for _, sn := range req.SerialNumbers {
+		sn = serial.Normalize(sn, rules[req.ProfileID])
+		if reason := checkSerialNumber(sn, seen); reason != "" {
+			res.Failed = append(res.Failed, &dto.FailedEquipment{SerialNumber: sn, Reason: reason})
+			continue
+		}
 
 		e := &queries.CreateEquipmentParams{
 			SerialNumber: sn,
@@ -52,67 +119,272 @@ func (s *EquipmentService) Create(ctx context.Context, userId int64, req *dto.Cr
 		id, err := s.equipmentRepository.Create(ctx, e, l)
 		if err != nil {
 			logger.Warn(fmt.Sprintf("equipment [%s] create error: %v", sn, err))
+			reason := dto.ReasonCreateFailed
+			if errors.Is(err, logger.ErrAlreadyExists) {
+				reason = dto.ReasonAlreadyExists
+			}
+			res.Failed = append(res.Failed, &dto.FailedEquipment{SerialNumber: sn, Reason: reason})
 			continue
 		}
+		s.auditCreated(ctx, id, e, nil)
 
-		if req.ParamID != 0 {
-			move := &queries.MoveToLocationParams{
-				EquipmentID:    id,
-				UserID:         userId,
-				MoveAt:         date,
-				MoveCode:       fmt.Sprintf("StorageTo%s", req.Param),
-				ToDepartmentID: toPGTypeInt8(req.ParamID),
-			}
+		if move != nil {
+			m := *move
+			m.EquipmentID = id
 
-			if err := s.locationRepository.Move(ctx, move); err != nil {
+			if _, err := s.locationRepository.Move(ctx, &m); err != nil {
 				logger.Warn(fmt.Sprintf("equipment [%s] move error: %v", sn, err))
+				res.Failed = append(res.Failed, &dto.FailedEquipment{ID: id, SerialNumber: sn, Reason: dto.ReasonMoveFailed})
+				continue
 			}
+			auditMove(ctx, s.auditRepository, model.AuditMove, &m)
 		}
 
+		res.Created = append(res.Created, &dto.CreatedEquipment{ID: id, SerialNumber: sn})
 		logger.Info(fmt.Sprintf("equipment [%s] created", sn))
 	}
 
-	return nil
+	if len(res.Created) == 0 {
+		return res, logger.ErrInvalidRows
+	}
+
+	s.publishCreated(res.Created, to)
+	return res, nil
+}
+
+// createAll checks the whole batch first and creates it in one transaction,
+// so either every serial number is created or none is.
+func (s *EquipmentService) createAll(ctx context.Context, req *dto.CreateEquipmentRequest, rule []string, l *queries.AddToStorageParams, move *queries.MoveToLocationParams, res *dto.CreateEquipmentResponse) (*dto.CreateEquipmentResponse, error) {
+	serialNumbers := make([]string, 0, len(req.SerialNumbers))
+	for _, sn := range req.SerialNumbers {
+		serialNumbers = append(serialNumbers, serial.Normalize(sn, rule))
+	}
+
+	existing, err := s.equipmentRepository.ExistingSerialNumbers(ctx, serialNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]bool, len(existing))
+	for _, sn := range existing {
+		exists[sn] = true
+	}
+
+	seen := make(map[string]bool, len(serialNumbers))
+	equipments := make([]*queries.CreateEquipmentParams, 0, len(serialNumbers))
+	for _, sn := range serialNumbers {
+		reason := checkSerialNumber(sn, seen)
+		if reason == "" && exists[sn] {
+			reason = dto.ReasonAlreadyExists
+		}
+		if reason != "" {
+			res.Failed = append(res.Failed, &dto.FailedEquipment{SerialNumber: sn, Reason: reason})
+			continue
+		}
+
+		equipments = append(equipments, &queries.CreateEquipmentParams{
+			SerialNumber: sn,
+			ProfileID:    req.ProfileID,
+			CompanyID:    req.CompanyID,
+		})
+	}
+
+	if len(res.Failed) != 0 || len(equipments) == 0 {
+		return res, logger.ErrInvalidRows
+	}
+
+	ids, err := s.equipmentRepository.CreateMany(ctx, equipments, l, move)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		res.Created = append(res.Created, &dto.CreatedEquipment{ID: id, SerialNumber: equipments[i].SerialNumber})
+		s.auditCreated(ctx, id, equipments[i], move)
+	}
+
+	logger.Info(fmt.Sprintf("%d equipment created", len(ids)))
+	return res, nil
+}
+
+func (s *EquipmentService) publishCreated(created []*dto.CreatedEquipment, to place) {
+	for _, item := range created {
+		publishEquipment(s.hub, websocket.EquipmentCreated, &model.Equipment{ID: item.ID, SerialNumber: item.SerialNumber}, to)
+	}
+}
+
+// auditCreated records created equipment and, when move is set, its move
+// made in the same transaction.
+func (s *EquipmentService) auditCreated(ctx context.Context, id int64, equipment *queries.CreateEquipmentParams, move *queries.MoveToLocationParams) {
+	audit(ctx, s.auditRepository, model.EntityEquipment, model.AuditCreate, id, nil, &model.Equipment{
+		ID:           id,
+		Company:      &model.Company{ID: equipment.CompanyID},
+		Profile:      &model.Profile{ID: equipment.ProfileID},
+		SerialNumber: equipment.SerialNumber,
+	})
+
+	if move != nil {
+		m := *move
+		m.EquipmentID = id
+		auditMove(ctx, s.auditRepository, model.AuditMove, &m)
+	}
+}
+
+func checkSerialNumber(sn string, seen map[string]bool) string {
+	switch {
+	case sn == "":
+		return dto.ReasonEmptySerialNumber
+	case seen[sn]:
+		return dto.ReasonDuplicateSerialNumber
+	}
+	seen[sn] = true
+
+	return ""
 }
 
 func (s *EquipmentService) Read(ctx context.Context, id int64) (*model.Equipment, error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.equipment(ctx, id); err != nil {
+		return nil, err
+	}
+
 	read, err := s.equipmentRepository.Read(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	read.RepairOrders, err = s.repairOrderRepository.ListByEquipment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	logger.Info(fmt.Sprintf("equipment with id %d read", id))
 	return read, nil
 }
 
+// Lookup resolves a scanned code, either a plain serial number or the payload
+// of a printed label, to the equipment and its current location. A label
+// whose serial number differs from the one of the equipment is refused.
+func (s *EquipmentService) Lookup(ctx context.Context, code string) (*dto.LookupEquipmentResponse, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrEmptySerialNumber)
+	}
+
+	var equipment *model.Equipment
+	var err error
+	if id, serialNumber, ok := label.ParsePayload(code); ok {
+		equipment, err = s.equipmentRepository.Read(ctx, id)
+		// a label printed for another equipment must not resolve to this one
+		if err == nil && serial.Normalize(equipment.SerialNumber, serial.Canonical) != serial.Normalize(serialNumber, serial.Canonical) {
+			return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrLabelMismatch)
+		}
+	} else {
+		equipment, err = s.equipmentRepository.ReadBySerialNumber(ctx, code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.equipment(ctx, equipment.ID); err != nil {
+		return nil, err
+	}
+
+	location, err := s.locationRepository.Current(ctx, equipment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("equipment with id %d looked up", equipment.ID))
+	return &dto.LookupEquipmentResponse{
+		Equipment: equipment,
+		Location:  location,
+	}, nil
+}
+
 func (s *EquipmentService) Update(ctx context.Context, equipment *model.Equipment) error {
+	if err := equipmentInScope(ctx, s.scopeRepository, equipment.ID); err != nil {
+		return err
+	}
+
+	rules, err := s.profileRepository.SerialRules(ctx, []int64{equipment.Profile.ID})
+	if err != nil {
+		return err
+	}
+
+	equipment.SerialNumber = serial.Normalize(equipment.SerialNumber, rules[equipment.Profile.ID])
+	if equipment.SerialNumber == "" {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrEmptySerialNumber)
+	}
+
+	before := auditState(ctx, s.equipmentRepository.Read, equipment.ID)
 	if err := s.equipmentRepository.Update(ctx, equipment); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityEquipment, model.AuditUpdate, equipment.ID, before, auditState(ctx, s.equipmentRepository.Read, equipment.ID))
+
 	logger.Info(fmt.Sprintf("equipment with id %d updated", equipment.ID))
 	return nil
 }
 
 func (s *EquipmentService) Delete(ctx context.Context, id int64) error {
+	if err := equipmentInScope(ctx, s.scopeRepository, id); err != nil {
+		return err
+	}
+
+	before := auditState(ctx, s.equipmentRepository.Read, id)
 	if err := s.equipmentRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	var at place
+	if last, err := s.locationRepository.GetLast(ctx, id); err == nil {
+		at = placeTo(last)
+	}
+	publishEquipment(s.hub, websocket.EquipmentDeleted, &model.Equipment{ID: id}, at)
+
+	audit(ctx, s.auditRepository, model.EntityEquipment, model.AuditDelete, id, before, auditState(ctx, s.equipmentRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("equipment with id %d deleted", id))
 	return nil
 }
 
 func (s *EquipmentService) Restore(ctx context.Context, id int64) error {
+	if err := equipmentInScope(ctx, s.scopeRepository, id); err != nil {
+		return err
+	}
+
+	before := auditState(ctx, s.equipmentRepository.Read, id)
 	if err := s.equipmentRepository.Restore(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityEquipment, model.AuditRestore, id, before, auditState(ctx, s.equipmentRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("equipment with id %d restored", id))
 	return nil
 }
 
 func (s *EquipmentService) List(ctx context.Context, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Equipment], error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if qp.ScopeDepartmentID, err = sc.catalog(); err != nil {
+		return nil, err
+	}
+
 	list, total, err := s.equipmentRepository.List(ctx, qp)
 	if err != nil {
 		return nil, err
@@ -124,3 +396,248 @@ func (s *EquipmentService) List(ctx context.Context, qp *dto.QueryParams) (*dto.
 		Total: total,
 	}, nil
 }
+
+// Duplicates lists groups of equipment whose serial numbers collide after
+// the canonical normalisation, see serial.Canonical. A department scoped
+// user gets the collisions within the department.
+func (s *EquipmentService) Duplicates(ctx context.Context) ([]*model.SerialNumberDuplicate, error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	departmentID, err := sc.catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.equipmentRepository.Duplicates(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d serial number duplicates listed", len(list)))
+	return list, nil
+}
+
+// Merge moves the movement history of the duplicate onto the equipment and
+// soft-deletes the duplicate, the merge is kept in equipment_merges.
+func (s *EquipmentService) Merge(ctx context.Context, userID, equipmentID int64, req *dto.MergeEquipmentRequest) (*model.EquipmentMerge, error) {
+	if equipmentID == req.DuplicateID {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrMergeSameEquipment)
+	}
+
+	merge, err := s.equipmentRepository.Merge(ctx, equipmentID, req.DuplicateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	publishEquipment(s.hub, websocket.EquipmentDeleted, &model.Equipment{ID: req.DuplicateID}, place{})
+
+	// the merge changes both, it is found by either id
+	audit(ctx, s.auditRepository, model.EntityEquipment, model.AuditMerge, equipmentID, nil, merge)
+	audit(ctx, s.auditRepository, model.EntityEquipment, model.AuditMerge, req.DuplicateID, nil, merge)
+
+	logger.Info(fmt.Sprintf("equipment with id %d merged into %d, %d moves re-parented", req.DuplicateID, equipmentID, merge.Locations))
+	return merge, nil
+}
+
+const (
+	importCompany      = "company"
+	importProfile      = "profile"
+	importSerialNumber = "serial_number"
+)
+
+// importHeaders maps accepted header titles (lower case) to import columns.
+var importHeaders = map[string]string{
+	"компания":       importCompany,
+	"company":        importCompany,
+	"профиль":        importProfile,
+	"profile":        importProfile,
+	"серийный номер": importSerialNumber,
+	"serial_number":  importSerialNumber,
+}
+
+// Import validates rows of an uploaded file and, unless it is a dry run,
+// creates all equipment in one transaction. The first row is the header.
+func (s *EquipmentService) Import(ctx context.Context, userId int64, req *dto.ImportEquipmentRequest, rows [][]string) (*dto.ImportEquipmentResponse, error) {
+	moveAt, err := parseMoveAt(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	var move *queries.MoveToLocationParams
+	to := place{departmentID: req.ParamID}
+	if err := sc.place(ctx, to); err != nil {
+		return nil, err
+	}
+	if to.departmentID != 0 {
+		code, err := nextMoveCode(place{}, to)
+		if err != nil {
+			return nil, err
+		}
+		move = newMove(0, userId, moveAt, code, place{}, to)
+	}
+
+	columns, err := importColumns(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ImportEquipmentResponse{
+		DryRun: req.DryRun,
+		Rows:   make([]*dto.ImportRowResult, 0, len(rows)-1),
+	}
+
+	var companies, profiles []string
+	for i, row := range rows[1:] {
+		item := &dto.ImportRowResult{
+			Row:          i + 2,
+			SerialNumber: importCell(row, columns[importSerialNumber]),
+			Company:      importCell(row, columns[importCompany]),
+			Profile:      importCell(row, columns[importProfile]),
+		}
+		if item.SerialNumber == "" && item.Company == "" && item.Profile == "" {
+			continue
+		}
+
+		res.Rows = append(res.Rows, item)
+		companies = append(companies, item.Company)
+		profiles = append(profiles, item.Profile)
+	}
+
+	companyIDs, err := s.companyRepository.IDsByTitles(ctx, companies)
+	if err != nil {
+		return nil, err
+	}
+
+	profileIDs, err := s.profileRepository.IDsByTitles(ctx, profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make([]int64, 0, len(profileIDs))
+	for _, id := range profileIDs {
+		known = append(known, id)
+	}
+
+	rules, err := s.profileRepository.SerialRules(ctx, known)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumbers := make([]string, len(res.Rows))
+	for i, item := range res.Rows {
+		item.SerialNumber = serial.Normalize(item.SerialNumber, rules[profileIDs[item.Profile]])
+		serialNumbers[i] = item.SerialNumber
+	}
+
+	existing, err := s.equipmentRepository.ExistingSerialNumbers(ctx, serialNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]bool, len(existing))
+	for _, sn := range existing {
+		exists[sn] = true
+	}
+
+	seen := make(map[string]bool, len(res.Rows))
+	equipments := make([]*queries.CreateEquipmentParams, 0, len(res.Rows))
+	for _, item := range res.Rows {
+		switch {
+		case item.SerialNumber == "":
+			item.Errors = append(item.Errors, logger.ErrEmptySerialNumber.Error())
+		case seen[item.SerialNumber]:
+			item.Errors = append(item.Errors, logger.ErrDuplicateSerialNumber.Error())
+		case exists[item.SerialNumber]:
+			item.Errors = append(item.Errors, logger.ErrAlreadyExists.Error())
+		}
+		seen[item.SerialNumber] = true
+
+		companyID, ok := companyIDs[item.Company]
+		if !ok {
+			item.Errors = append(item.Errors, logger.ErrUnknownCompany.Error())
+		}
+
+		profileID, ok := profileIDs[item.Profile]
+		if !ok {
+			item.Errors = append(item.Errors, logger.ErrUnknownProfile.Error())
+		}
+
+		if len(item.Errors) == 0 {
+			equipments = append(equipments, &queries.CreateEquipmentParams{
+				SerialNumber: item.SerialNumber,
+				ProfileID:    profileID,
+				CompanyID:    companyID,
+			})
+		}
+	}
+
+	res.Total = len(res.Rows)
+	res.Valid = len(equipments)
+
+	if req.DryRun {
+		logger.Info(fmt.Sprintf("equipment import checked: %d of %d rows valid", res.Valid, res.Total))
+		return res, nil
+	}
+
+	if res.Valid != res.Total || res.Total == 0 {
+		return res, logger.ErrInvalidRows
+	}
+
+	l := &queries.AddToStorageParams{
+		UserID:   userId,
+		MoveAt:   moveAt,
+		MoveCode: string(addToStorage),
+	}
+
+	ids, err := s.equipmentRepository.CreateMany(ctx, equipments, l, move)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		res.Rows[i].ID = id
+		s.auditCreated(ctx, id, equipments[i], move)
+		publishEquipment(s.hub, websocket.EquipmentCreated, &model.Equipment{ID: id, SerialNumber: res.Rows[i].SerialNumber}, to)
+	}
+	res.Created = len(ids)
+
+	logger.Info(fmt.Sprintf("%d equipment imported", res.Created))
+	return res, nil
+}
+
+func importColumns(rows [][]string) (map[string]int, error) {
+	if len(rows) == 0 {
+		return nil, logger.ErrMissingColumn
+	}
+
+	columns := make(map[string]int, 3)
+	for i, title := range rows[0] {
+		if column, ok := importHeaders[strings.ToLower(strings.TrimSpace(title))]; ok {
+			columns[column] = i
+		}
+	}
+
+	for _, column := range []string{importCompany, importProfile, importSerialNumber} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("%w: %s", logger.ErrMissingColumn, column)
+		}
+	}
+
+	return columns, nil
+}
+
+func importCell(row []string, i int) string {
+	if i >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[i])
+}
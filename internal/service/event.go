@@ -0,0 +1,66 @@
+package service
+
+import (
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+func publishMove(hub *websocket.Hub, move *queries.MoveToLocationParams) {
+	from := place{
+		departmentID: move.FromDepartmentID.Int64,
+		employeeID:   move.FromEmployeeID.Int64,
+		contractID:   move.FromContractID.Int64,
+	}
+	to := place{
+		departmentID: move.ToDepartmentID.Int64,
+		employeeID:   move.ToEmployeeID.Int64,
+		contractID:   move.ToContractID.Int64,
+	}
+
+	hub.Publish(websocket.EquipmentMoved, moveTopics(move.EquipmentID, moveCode(move.MoveCode), from, to), move)
+}
+
+// moveTopics lists the topics of the equipment and of both places of a move.
+// An empty place is storage unless the move is to or from repair or a
+// write-off.
+func moveTopics(equipmentID int64, code moveCode, from, to place) []string {
+	topics := []string{websocket.TopicEquipments, websocket.EquipmentTopic(equipmentID)}
+
+	if from == (place{}) && code != returnedFromRepair ||
+		to == (place{}) && code != sentToRepair && code != writtenOff {
+		topics = append(topics, websocket.TopicStorage)
+	}
+
+	return append(append(topics, placeTopics(from)...), placeTopics(to)...)
+}
+
+func placeTopics(p place) []string {
+	var topics []string
+	if p.departmentID != 0 {
+		topics = append(topics, websocket.DepartmentTopic(p.departmentID))
+	}
+	if p.employeeID != 0 {
+		topics = append(topics, websocket.EmployeeTopic(p.employeeID))
+	}
+	if p.contractID != 0 {
+		topics = append(topics, websocket.ContractTopic(p.contractID))
+	}
+
+	return topics
+}
+
+// publishEquipment tells the subscribers of the equipment and of the place
+// it is at that it was created or deleted.
+func publishEquipment(hub *websocket.Hub, eventType string, equipment *model.Equipment, at place) {
+	topics := []string{websocket.TopicEquipments, websocket.EquipmentTopic(equipment.ID)}
+	if at == (place{}) {
+		topics = append(topics, websocket.TopicStorage)
+	}
+
+	hub.Publish(eventType, append(topics, placeTopics(at)...), equipment)
+}
+
+func publishUser(hub *websocket.Hub, id int64) {
+	hub.Publish(websocket.UserChanged, []string{websocket.TopicUsers, websocket.UserTopic(id)}, &model.User{ID: id})
+}
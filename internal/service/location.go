@@ -4,28 +4,254 @@ import (
 	"context"
 	"fmt"
 
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/oatsmoke/warehouse_backend/internal/repository"
 )
 
 type LocationService struct {
-	locationRepository repository.Location
-	ReplaceRepository  repository.Replace
-	CategoryRepository repository.Category
+	locationRepository  repository.Location
+	equipmentRepository repository.Equipment
+	ReplaceRepository   repository.Replace
+	auditRepository     repository.Audit
+	scopeRepository     repository.Scope
+	hub                 *websocket.Hub
 }
 
-func NewLocationService(locationRepository repository.Location, replaceRepository repository.Replace, categoryRepository repository.Category) *LocationService {
+func NewLocationService(locationRepository repository.Location, equipmentRepository repository.Equipment, replaceRepository repository.Replace, auditRepository repository.Audit, scopeRepository repository.Scope, hub *websocket.Hub) *LocationService {
 	return &LocationService{
-		locationRepository: locationRepository,
-		ReplaceRepository:  replaceRepository,
-		CategoryRepository: categoryRepository,
+		locationRepository:  locationRepository,
+		equipmentRepository: equipmentRepository,
+		ReplaceRepository:   replaceRepository,
+		auditRepository:     auditRepository,
+		scopeRepository:     scopeRepository,
+		hub:                 hub,
 	}
 }
 
-func (s *LocationService) List(ctx context.Context, toDepartmentID int64) (*dto.ListResponse[[]*model.Equipment], error) {
-	list, total, err := s.locationRepository.List(ctx, toDepartmentID)
+func (s *LocationService) Move(ctx context.Context, userID int64, req *dto.MoveEquipmentRequest) error {
+	to := place{
+		departmentID: req.ToDepartmentID,
+		employeeID:   req.ToEmployeeID,
+		contractID:   req.ToContractID,
+	}
+
+	date, err := parseMoveAt(req.Date)
+	if err != nil {
+		return err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int64]struct{}, len(req.EquipmentIDs))
+	moves := make([]*queries.MoveToLocationParams, 0, len(req.EquipmentIDs))
+	for _, id := range req.EquipmentIDs {
+		if _, ok := seen[id]; ok {
+			return logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+		}
+		seen[id] = struct{}{}
+
+		from, err := s.currentPlace(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := sc.place(ctx, from); err != nil {
+			return err
+		}
+
+		code, err := nextMoveCode(from, to)
+		if err != nil {
+			return err
+		}
+
+		move := newMove(id, userID, date, code, from, to)
+		move.MoveType = toPGTypeText(req.MoveType)
+		move.Price = toPGTypeText(req.Price)
+		move.Comment = toPGTypeText(req.Comment)
+		moves = append(moves, move)
+	}
+
+	ids, err := s.locationRepository.MoveMany(ctx, moves)
+	if err != nil {
+		return err
+	}
+
+	for _, move := range moves {
+		publishMove(s.hub, move)
+		auditMove(ctx, s.auditRepository, model.AuditMove, move)
+	}
+
+	logger.Info(fmt.Sprintf("%d equipment moved", len(ids)))
+	return nil
+}
+
+func (s *LocationService) Replace(ctx context.Context, userID int64, req *dto.ReplaceEquipmentRequest) (*model.Replace, error) {
+	if req.OutEquipmentID == req.InEquipmentID {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+	}
+
+	date, err := parseMoveAt(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := place{contractID: req.ContractID}
+
+	outFrom, err := s.currentPlace(ctx, req.OutEquipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	// the equipment coming back from the contract takes the place of the
+	// one going out, only that place has to be in scope
+	if err := sc.place(ctx, outFrom); err != nil {
+		return nil, err
+	}
+
+	inFrom, err := s.currentPlace(ctx, req.InEquipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inFrom != contract {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+	}
+
+	outCode, err := nextMoveCode(outFrom, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	inCode, err := nextMoveCode(contract, outFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	moveOut := newMove(req.OutEquipmentID, userID, date, outCode, outFrom, contract)
+	moveOut.MoveType = toPGTypeText(req.MoveType)
+	moveOut.Price = toPGTypeText(req.Price)
+	moveOut.Comment = toPGTypeText(req.Comment)
+
+	moveIn := newMove(req.InEquipmentID, userID, date, inCode, contract, outFrom)
+	moveIn.Comment = toPGTypeText(req.Comment)
+
+	replace, err := s.ReplaceRepository.Create(ctx, moveOut, moveIn)
+	if err != nil {
+		return nil, err
+	}
+
+	publishMove(s.hub, moveOut)
+	publishMove(s.hub, moveIn)
+	auditMove(ctx, s.auditRepository, model.AuditMove, moveOut)
+	auditMove(ctx, s.auditRepository, model.AuditMove, moveIn)
+
+	logger.Info(fmt.Sprintf("equipment with id %d replaced by id %d", req.InEquipmentID, req.OutEquipmentID))
+	return replace, nil
+}
+
+func (s *LocationService) Undo(ctx context.Context, userID int64, userRole role.Role, equipmentID int64) error {
+	equipment, err := s.equipmentRepository.Read(ctx, equipmentID)
+	if err != nil {
+		return err
+	}
+
+	if equipment.DeletedAt != nil {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+	}
+
+	if equipment.WrittenOffAt != nil {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentWrittenOff)
+	}
+
+	last, err := s.locationRepository.GetLast(ctx, equipmentID)
+	if err != nil {
+		return err
+	}
+
+	// repair moves belong to their repair order and are not undone
+	if last.ID == 0 || last.MoveCode == string(addToStorage) ||
+		last.MoveCode == string(sentToRepair) || last.MoveCode == string(returnedFromRepair) {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+	}
+
+	if last.User.ID != userID && !userRole.Can(role.EquipmentUndoAny) {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrNotMoveOwner)
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return err
+	}
+
+	if err := sc.place(ctx, placeTo(last)); err != nil {
+		return err
+	}
+
+	ids, err := s.locationRepository.Revert(ctx, last.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	// the equipment goes back from where the reverted move took it
+	topics := moveTopics(equipmentID, moveCode(last.MoveCode), placeTo(last), placeFrom(last))
+	s.hub.Publish(websocket.EquipmentMoved, topics, last)
+	audit(ctx, s.auditRepository, model.EntityEquipment, model.AuditUndoMove, equipmentID,
+		auditPlace(placeTo(last), moveCode(last.MoveCode)), auditPlace(placeFrom(last), ""))
+
+	logger.Info(fmt.Sprintf("%d moves of equipment with id %d reverted", len(ids), equipmentID))
+	return nil
+}
+
+func (s *LocationService) History(ctx context.Context, equipmentID int64, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Location], error) {
+	if _, err := s.equipmentRepository.Read(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.equipment(ctx, equipmentID); err != nil {
+		return nil, err
+	}
+
+	list, total, err := s.locationRepository.History(ctx, equipmentID, qp)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d moves of equipment with id %d listed", len(list), equipmentID))
+	return &dto.ListResponse[[]*model.Location]{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+func (s *LocationService) List(ctx context.Context, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Equipment], error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.list(ctx, qp); err != nil {
+		return nil, err
+	}
+
+	list, total, err := s.locationRepository.List(ctx, qp)
 	if err != nil {
 		return nil, err
 	}
@@ -37,121 +263,100 @@ func (s *LocationService) List(ctx context.Context, toDepartmentID int64) (*dto.
 	}, nil
 }
 
-//// AddToStorage is equipment add to storage
-//func (s *LocationService) AddToStorage(ctx context.Context, date *time.Time, equipmentId, employeeId, companyId int64) error {
-//	if err := s.LocationRepository.AddToStorage(ctx, date, equipmentId, employeeId, companyId); err != nil {
-//		return logger.Err(err, "")
-//	}
-//
-//	return nil
-//}
-//
-//// TransferTo is equipment transfer to
-//func (s *LocationService) TransferTo(ctx context.Context, EmployeeId int64, requests []*model.RequestLocation) error {
-//	var code string
-//	replace := []int64{0, 0}
-//	for _, request := range requests {
-//		nowLocation, _ := s.LocationRepository.GetLocationNow(ctx, request.EquipmentId)
-//		switch {
-//
-//		case request.ToDepartment == 0 && request.ToEmployee == 0 && request.ToContract == 0:
-//			code = strings.ToUpper(request.ThisLocation + "_to_storage")
-//			transferId, err := s.LocationRepository.TransferToStorage(ctx, request.Date, code, request.EquipmentId, EmployeeId, request.Company, nowLocation)
-//			if err != nil {
-//				return logger.Err(err, "")
-//			}
-//			if request.Way == "replace" && replace[0] == 0 {
-//				replace[0] = transferId
-//			} else if request.Way == "replace" && replace[1] == 0 {
-//				replace[1] = transferId
-//			}
-//
-//		case request.ToDepartment != 0 && request.ToEmployee == 0 && request.ToContract == 0:
-//			if request.InDepartment {
-//				code = strings.ToUpper(request.ThisLocation + "_to_" + request.Where + "_in_department")
-//			} else {
-//				code = strings.ToUpper(request.ThisLocation + "_to_" + request.Where)
-//			}
-//			transferId, err := s.LocationRepository.TransferToDepartment(ctx, request.Date, code, request.EquipmentId, EmployeeId, request.Company, request.ToDepartment, nowLocation)
-//			if err != nil {
-//				return logger.Err(err, "")
-//			}
-//			if request.Way == "replace" && replace[0] == 0 {
-//				replace[0] = transferId
-//			} else if request.Way == "replace" && replace[1] == 0 {
-//				replace[1] = transferId
-//			}
-//
-//		case request.ToDepartment == 0 && request.ToEmployee != 0 && request.ToContract == 0:
-//			code = strings.ToUpper(request.ThisLocation + "_to_" + request.Where)
-//			transferId, err := s.LocationRepository.TransferToEmployee(ctx, request.Date, code, request.EquipmentId, EmployeeId, request.Company, request.ToEmployee, nowLocation)
-//			if err != nil {
-//				return logger.Err(err, "")
-//			}
-//			if request.Way == "replace" && replace[0] == 0 {
-//				replace[0] = transferId
-//			} else if request.Way == "replace" && replace[1] == 0 {
-//				replace[1] = transferId
-//			}
-//
-//		case request.ToDepartment != 0 && request.ToEmployee != 0 && request.ToContract == 0:
-//			if request.InDepartment {
-//				code = strings.ToUpper(request.ThisLocation + "_to_" + request.Where + "_in_department")
-//			} else {
-//				code = strings.ToUpper(request.ThisLocation + "_to_" + request.Where)
-//			}
-//			transferId, err := s.LocationRepository.TransferToEmployeeInDepartment(ctx, request.Date, code, request.EquipmentId, EmployeeId, request.Company, request.ToDepartment, request.ToEmployee, nowLocation)
-//			if err != nil {
-//				return logger.Err(err, "")
-//			}
-//			if request.Way == "replace" && replace[0] == 0 {
-//				replace[0] = transferId
-//			} else if request.Way == "replace" && replace[1] == 0 {
-//				replace[1] = transferId
-//			}
-//
-//		case request.ToDepartment == 0 && request.ToEmployee == 0 && request.ToContract != 0:
-//			code = strings.ToUpper(request.ThisLocation + "_to_" + request.Where)
-//			transferId, err := s.LocationRepository.TransferToContract(ctx, request.Date, code, request.EquipmentId, EmployeeId, request.Company, request.ToContract, request.TransferType, request.Price, nowLocation)
-//			if err != nil {
-//				return logger.Err(err, "")
-//			}
-//			if request.Way == "replace" && replace[0] == 0 {
-//				replace[0] = transferId
-//			} else if request.Way == "replace" && replace[1] == 0 {
-//				replace[1] = transferId
-//			}
-//		}
-//	}
-//
-//	if requests[0].Way == "replace" {
-//		err := s.ReplaceRepository.Create(ctx, replace)
-//		if err != nil {
-//			return logger.Err(err, "")
-//		}
-//	}
-//
-//	return nil
-//}
-//
-//// Delete is equipment transfer to
-//func (s *LocationService) Delete(ctx context.Context, id int64) error {
-//	replace, err := s.ReplaceRepository.FindByLocationId(ctx, id)
-//	if err != nil {
-//		return s.LocationRepository.Delete(ctx, id)
-//	}
-//
-//	if err = s.LocationRepository.Delete(ctx, replace.TransferFrom); err != nil {
-//		return logger.Err(err, "")
-//	}
-//
-//	if err = s.LocationRepository.Delete(ctx, replace.TransferTo); err != nil {
-//		return logger.Err(err, "")
-//	}
-//
-//	return nil
-//}
-//
+// Topics returns the check of the websocket topics the request user may
+// subscribe to, limited to the scope of the user.
+func (s *LocationService) Topics(ctx context.Context) (func(topic string) bool, error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(topic string) bool {
+		// the client subscribes after the request is over
+		return sc.topic(context.Background(), topic) == nil
+	}, nil
+}
+
+func (s *LocationService) DepartmentBalance(ctx context.Context, req *dto.DepartmentBalanceRequest) (*model.DepartmentBalance, error) {
+	dateFrom, err := parseMoveAt(req.DateFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	dateTo, err := parseMoveAt(req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dateFrom.Time.Before(dateTo.Time) {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrInvalidDateRange)
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.department(req.DepartmentID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.locationRepository.DepartmentBalance(ctx, &queries.DepartmentBalanceReportParams{
+		DateFrom:     dateFrom,
+		DateTo:       dateTo,
+		DepartmentID: req.DepartmentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := new(model.DepartmentBalanceRow)
+	for _, row := range rows {
+		total.Opening += row.Opening
+		total.FromStorage += row.FromStorage
+		total.FromContract += row.FromContract
+		total.FromDepartment += row.FromDepartment
+		total.FromEmployee += row.FromEmployee
+		total.FromRepair += row.FromRepair
+		total.ToStorage += row.ToStorage
+		total.ToContract += row.ToContract
+		total.ToDepartment += row.ToDepartment
+		total.ToEmployee += row.ToEmployee
+		total.ToRepair += row.ToRepair
+		total.WrittenOff += row.WrittenOff
+		total.Closing += row.Closing
+	}
+
+	logger.Info(fmt.Sprintf("balance of department with id %d reported", req.DepartmentID))
+	return &model.DepartmentBalance{
+		Department: &model.Department{ID: req.DepartmentID},
+		DateFrom:   &dateFrom.Time,
+		DateTo:     &dateTo.Time,
+		Rows:       rows,
+		Total:      total,
+	}, nil
+}
+
+// currentPlace returns where the equipment is now according to its latest
+// location row; soft-deleted equipment cannot be moved anywhere.
+func (s *LocationService) currentPlace(ctx context.Context, equipmentID int64) (place, error) {
+	equipment, err := s.equipmentRepository.Read(ctx, equipmentID)
+	if err != nil {
+		return place{}, err
+	}
+
+	if equipment.DeletedAt != nil {
+		return place{}, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+	}
+
+	last, err := s.locationRepository.GetLast(ctx, equipmentID)
+	if err != nil {
+		return place{}, err
+	}
+
+	return placeTo(last), nil
+}
+
 //// GetById is equipment get by id
 //func (s *LocationService) GetById(ctx context.Context, equipmentId int64) (*model.Location, error) {
 //	res, err := s.LocationRepository.GetById(ctx, equipmentId)
@@ -175,143 +380,3 @@ func (s *LocationService) List(ctx context.Context, toDepartmentID int64) (*dto.
 //	}
 //	return equipments, nil
 //}
-//
-//// GetHistory is equipment get history
-//func (s *LocationService) GetHistory(ctx context.Context, equipmentId int64) ([]*model.Location, error) {
-//	res, err := s.LocationRepository.GetHistory(ctx, equipmentId)
-//	if err != nil {
-//		return nil, logger.Err(err, "")
-//	}
-//	return res, nil
-//}
-//
-//// GetByLocation is equipment get by location
-//func (s *LocationService) GetByLocation(ctx context.Context, toDepartmentId, toEmployeeId, toContractId int64) ([]*model.Location, error) {
-//	switch {
-//	case toDepartmentId != 0:
-//		res, err := s.LocationRepository.GetByLocationDepartment(ctx, toDepartmentId)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		return res, nil
-//
-//	case toEmployeeId != 0:
-//		res, err := s.LocationRepository.GetByLocationEmployee(ctx, toEmployeeId)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		return res, nil
-//
-//	case toContractId != 0:
-//		res, err := s.LocationRepository.GetByLocationContract(ctx, toContractId)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		return res, nil
-//
-//	default:
-//		res, err := s.LocationRepository.GetByLocationStorage(ctx)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		return res, nil
-//	}
-//}
-//
-//// ReportByCategory is equipment report by category
-//func (s *LocationService) ReportByCategory(ctx context.Context, departmentId int64, date *time.Time) (*model.Report, error) {
-//	report := new(model.Report)
-//	fromDate := date
-//	parseTime, err := time.Parse(time.RFC3339, date.String())
-//	if err != nil {
-//		return nil, logger.Err(err, "")
-//	}
-//	toDate := parseTime.AddDate(0, 1, 0)
-//	categories, _, err := s.CategoryRepository.List(ctx, &dto.QueryParams{WithDeleted: false})
-//	if err != nil {
-//		return nil, logger.Err(err, "")
-//	}
-//	report.Categories = categories
-//	departments := make(map[int64]*model.Department)
-//	leftover := make(map[int64][]*model.Location)
-//	total := make(map[int64][]*model.Location)
-//	fromStorage := make(map[int64][]*model.Location)
-//	toStorage := make(map[int64][]*model.Location)
-//	fromContract := make(map[int64][]*model.Location)
-//	toContract := make(map[int64][]*model.Location)
-//	fromDepartment := make(map[int64]map[int64][]*model.Location)
-//	toDepartment := make(map[int64]map[int64][]*model.Location)
-//	for _, category := range categories {
-//		equipment, err := s.LocationRepository.RemainderByCategory(ctx, category.ID, departmentId, fromDate)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		leftover[category.ID] = equipment
-//
-//		equipment, err = s.LocationRepository.RemainderByCategory(ctx, category.ID, departmentId, &toDate)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		total[category.ID] = equipment
-//
-//		equipment, err = s.LocationRepository.TransferByCategory(ctx, category.ID, departmentId, fromDate, &toDate, "STORAGE_TO_DEPARTMENT")
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		fromStorage[category.ID] = equipment
-//
-//		equipment, err = s.LocationRepository.TransferByCategory(ctx, category.ID, departmentId, fromDate, &toDate, "DEPARTMENT_TO_STORAGE")
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		toStorage[category.ID] = equipment
-//
-//		equipment, err = s.LocationRepository.TransferByCategory(ctx, category.ID, departmentId, fromDate, &toDate, "CONTRACT_TO_DEPARTMENT")
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		fromContract[category.ID] = equipment
-//
-//		equipment, err = s.LocationRepository.TransferByCategory(ctx, category.ID, departmentId, fromDate, &toDate, "DEPARTMENT_TO_CONTRACT")
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//		toContract[category.ID] = equipment
-//
-//		equipment, err = s.LocationRepository.FromDepartmentTransferByCategory(ctx, category.ID, departmentId, fromDate, &toDate)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//
-//		locationFromDepartment := make(map[int64][]*model.Location)
-//		for _, row := range equipment {
-//			departments[row.FromDepartment.ID] = row.FromDepartment
-//			locationFromDepartment[row.FromDepartment.ID] = append(locationFromDepartment[row.FromDepartment.ID], row)
-//		}
-//		fromDepartment[category.ID] = locationFromDepartment
-//		equipmentFrom, err := s.LocationRepository.ToDepartmentTransferByCategory(ctx, category.ID, departmentId, fromDate, &toDate)
-//		if err != nil {
-//			return nil, logger.Err(err, "")
-//		}
-//
-//		locationToDepartment := make(map[int64][]*model.Location)
-//		for _, row := range equipmentFrom {
-//			departments[row.ToDepartment.ID] = row.ToDepartment
-//			locationToDepartment[row.ToDepartment.ID] = append(locationToDepartment[row.ToDepartment.ID], row)
-//		}
-//		toDepartment[category.ID] = locationToDepartment
-//	}
-//	for _, department := range departments {
-//		report.Departments = append(report.Departments, department)
-//	}
-//
-//	report.Leftover = leftover
-//	report.Total = total
-//	report.FromStorage = fromStorage
-//	report.ToStorage = toStorage
-//	report.FromContract = fromContract
-//	report.ToContract = toContract
-//	report.FromDepartment = fromDepartment
-//	report.ToDepartment = toDepartment
-//	return report, nil
-//}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+)
+
+type moveState int
+
+const (
+	storageState moveState = iota
+	departmentState
+	employeeState
+	employeeInDepartmentState
+	contractState
+)
+
+type moveCode string
+
+const (
+	addToStorage                               moveCode = "AddToStorage"
+	storageToDepartment                        moveCode = "StorageToDepartment"
+	storageToEmployee                          moveCode = "StorageToEmployee"
+	storageToEmployeeInDepartment              moveCode = "StorageToEmployeeInDepartment"
+	storageToContract                          moveCode = "StorageToContract"
+	departmentToStorage                        moveCode = "DepartmentToStorage"
+	departmentToDepartment                     moveCode = "DepartmentToDepartment"
+	departmentToEmployee                       moveCode = "DepartmentToEmployee"
+	departmentToEmployeeInDepartment           moveCode = "DepartmentToEmployeeInDepartment"
+	departmentToContract                       moveCode = "DepartmentToContract"
+	employeeToStorage                          moveCode = "EmployeeToStorage"
+	employeeToDepartment                       moveCode = "EmployeeToDepartment"
+	employeeToEmployee                         moveCode = "EmployeeToEmployee"
+	employeeToEmployeeInDepartment             moveCode = "EmployeeToEmployeeInDepartment"
+	employeeToContract                         moveCode = "EmployeeToContract"
+	employeeInDepartmentToStorage              moveCode = "EmployeeInDepartmentToStorage"
+	employeeInDepartmentToDepartment           moveCode = "EmployeeInDepartmentToDepartment"
+	employeeInDepartmentToEmployee             moveCode = "EmployeeInDepartmentToEmployee"
+	employeeInDepartmentToEmployeeInDepartment moveCode = "EmployeeInDepartmentToEmployeeInDepartment"
+	employeeInDepartmentToContract             moveCode = "EmployeeInDepartmentToContract"
+	contractToStorage                          moveCode = "ContractToStorage"
+	contractToDepartment                       moveCode = "ContractToDepartment"
+	contractToEmployee                         moveCode = "ContractToEmployee"
+	contractToEmployeeInDepartment             moveCode = "ContractToEmployeeInDepartment"
+	// writtenOff is terminal, it leaves no place and no move follows it.
+	writtenOff moveCode = model.WrittenOff
+	// sentToRepair leaves no place either, only returnedFromRepair follows it.
+	sentToRepair       moveCode = model.SentToRepair
+	returnedFromRepair moveCode = model.ReturnedFromRepair
+)
+
+// transitions lists every legal move; a pair missing here is rejected.
+// Equipment at a contract has to come back before it can go to another one.
+var transitions = map[moveState]map[moveState]moveCode{
+	storageState: {
+		departmentState:           storageToDepartment,
+		employeeState:             storageToEmployee,
+		employeeInDepartmentState: storageToEmployeeInDepartment,
+		contractState:             storageToContract,
+	},
+	departmentState: {
+		storageState:              departmentToStorage,
+		departmentState:           departmentToDepartment,
+		employeeState:             departmentToEmployee,
+		employeeInDepartmentState: departmentToEmployeeInDepartment,
+		contractState:             departmentToContract,
+	},
+	employeeState: {
+		storageState:              employeeToStorage,
+		departmentState:           employeeToDepartment,
+		employeeState:             employeeToEmployee,
+		employeeInDepartmentState: employeeToEmployeeInDepartment,
+		contractState:             employeeToContract,
+	},
+	employeeInDepartmentState: {
+		storageState:              employeeInDepartmentToStorage,
+		departmentState:           employeeInDepartmentToDepartment,
+		employeeState:             employeeInDepartmentToEmployee,
+		employeeInDepartmentState: employeeInDepartmentToEmployeeInDepartment,
+		contractState:             employeeInDepartmentToContract,
+	},
+	contractState: {
+		storageState:              contractToStorage,
+		departmentState:           contractToDepartment,
+		employeeState:             contractToEmployee,
+		employeeInDepartmentState: contractToEmployeeInDepartment,
+	},
+}
+
+type place struct {
+	departmentID int64
+	employeeID   int64
+	contractID   int64
+}
+
+func placeTo(location *model.Location) place {
+	var p place
+	if location.ToDepartment != nil {
+		p.departmentID = location.ToDepartment.ID
+	}
+	if location.ToEmployee != nil {
+		p.employeeID = location.ToEmployee.ID
+	}
+	if location.ToContract != nil {
+		p.contractID = location.ToContract.ID
+	}
+
+	return p
+}
+
+func placeFrom(location *model.Location) place {
+	var p place
+	if location.FromDepartment != nil {
+		p.departmentID = location.FromDepartment.ID
+	}
+	if location.FromEmployee != nil {
+		p.employeeID = location.FromEmployee.ID
+	}
+	if location.FromContract != nil {
+		p.contractID = location.FromContract.ID
+	}
+
+	return p
+}
+
+func (p place) isValid() bool {
+	return p.contractID == 0 || (p.departmentID == 0 && p.employeeID == 0)
+}
+
+func (p place) state() moveState {
+	switch {
+	case p.contractID != 0:
+		return contractState
+	case p.departmentID != 0 && p.employeeID != 0:
+		return employeeInDepartmentState
+	case p.departmentID != 0:
+		return departmentState
+	case p.employeeID != 0:
+		return employeeState
+	default:
+		return storageState
+	}
+}
+
+func nextMoveCode(from, to place) (moveCode, error) {
+	if !to.isValid() {
+		return "", logger.Error(logger.MsgFailedToValidate, logger.ErrInvalidDestination)
+	}
+
+	if from == to {
+		return "", logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+	}
+
+	code, ok := transitions[from.state()][to.state()]
+	if !ok {
+		return "", logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalMove)
+	}
+
+	return code, nil
+}
+
+func newMove(equipmentID, userID int64, moveAt pgtype.Timestamptz, code moveCode, from, to place) *queries.MoveToLocationParams {
+	return &queries.MoveToLocationParams{
+		EquipmentID:      equipmentID,
+		UserID:           userID,
+		MoveAt:           moveAt,
+		MoveCode:         string(code),
+		FromDepartmentID: toPGTypeInt8(from.departmentID),
+		FromEmployeeID:   toPGTypeInt8(from.employeeID),
+		FromContractID:   toPGTypeInt8(from.contractID),
+		ToDepartmentID:   toPGTypeInt8(to.departmentID),
+		ToEmployeeID:     toPGTypeInt8(to.employeeID),
+		ToContractID:     toPGTypeInt8(to.contractID),
+	}
+}
+
+func parseMoveAt(date string) (pgtype.Timestamptz, error) {
+	d, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return pgtype.Timestamptz{}, logger.Error(logger.MsgFailedToParse, err)
+	}
+
+	return pgtype.Timestamptz{
+		Time:  d,
+		Valid: true,
+	}, nil
+}
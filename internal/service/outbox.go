@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oatsmoke/warehouse_backend/internal/lib/kafka"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+const outboxBatchSize = 100
+
+type OutboxService struct {
+	outboxRepository repository.Outbox
+	publisher        kafka.Publisher
+}
+
+func NewOutboxService(outboxRepository repository.Outbox, publisher kafka.Publisher) *OutboxService {
+	return &OutboxService{
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+	}
+}
+
+// Run relays pending events every interval until ctx is done, a full batch
+// is followed by the next one right away.
+func (s *OutboxService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// errors are logged where they happen, the next tick retries
+		if n, err := s.Relay(ctx); err == nil && n == outboxBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *OutboxService) Relay(ctx context.Context) (int, error) {
+	n, err := s.outboxRepository.Relay(ctx, s.publisher, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		logger.Info(fmt.Sprintf("%d outbox events published", n))
+	}
+	return n, nil
+}
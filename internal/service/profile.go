@@ -6,26 +6,35 @@ import (
 
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/serial"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/oatsmoke/warehouse_backend/internal/repository"
 )
 
 type ProfileService struct {
 	profileRepository repository.Profile
+	auditRepository   repository.Audit
 }
 
-func NewProfileService(profileRepository repository.Profile) *ProfileService {
+func NewProfileService(profileRepository repository.Profile, auditRepository repository.Audit) *ProfileService {
 	return &ProfileService{
 		profileRepository: profileRepository,
+		auditRepository:   auditRepository,
 	}
 }
 
 func (s *ProfileService) Create(ctx context.Context, profile *model.Profile) error {
+	if !serial.IsValidRule(profile.SerialRule) {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrInvalidSerialRule)
+	}
+
 	id, err := s.profileRepository.Create(ctx, profile)
 	if err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityProfile, model.AuditCreate, id, nil, auditState(ctx, s.profileRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("profile with id %d created", id))
 	return nil
 }
@@ -41,28 +50,41 @@ func (s *ProfileService) Read(ctx context.Context, id int64) (*model.Profile, er
 }
 
 func (s *ProfileService) Update(ctx context.Context, profile *model.Profile) error {
+	if !serial.IsValidRule(profile.SerialRule) {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrInvalidSerialRule)
+	}
+
+	before := auditState(ctx, s.profileRepository.Read, profile.ID)
 	if err := s.profileRepository.Update(ctx, profile); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityProfile, model.AuditUpdate, profile.ID, before, auditState(ctx, s.profileRepository.Read, profile.ID))
+
 	logger.Info(fmt.Sprintf("profile with id %d updated", profile.ID))
 	return nil
 }
 
 func (s *ProfileService) Delete(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.profileRepository.Read, id)
 	if err := s.profileRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityProfile, model.AuditDelete, id, before, auditState(ctx, s.profileRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("profile with id %d deleted", id))
 	return nil
 }
 
 func (s *ProfileService) Restore(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.profileRepository.Read, id)
 	if err := s.profileRepository.Restore(ctx, id); err != nil {
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityProfile, model.AuditRestore, id, before, auditState(ctx, s.profileRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("profile with id %d restored", id))
 	return nil
 }
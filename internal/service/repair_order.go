@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+// repairTransitions lists the statuses an order can go to from its current
+// one, keeping the status only edits vendor, cost and notes. Returned is
+// reached through Return only.
+var repairTransitions = map[string][]string{
+	model.RepairSent:         {model.RepairDiagnosed, model.RepairRepaired, model.RepairUnrepairable},
+	model.RepairDiagnosed:    {model.RepairRepaired, model.RepairUnrepairable},
+	model.RepairRepaired:     {},
+	model.RepairUnrepairable: {},
+}
+
+type RepairOrderService struct {
+	repairOrderRepository repository.RepairOrder
+	equipmentRepository   repository.Equipment
+	locationRepository    repository.Location
+	auditRepository       repository.Audit
+	scopeRepository       repository.Scope
+	hub                   *websocket.Hub
+}
+
+func NewRepairOrderService(
+	repairOrderRepository repository.RepairOrder,
+	equipmentRepository repository.Equipment,
+	locationRepository repository.Location,
+	auditRepository repository.Audit,
+	scopeRepository repository.Scope,
+	hub *websocket.Hub,
+) *RepairOrderService {
+	return &RepairOrderService{
+		repairOrderRepository: repairOrderRepository,
+		equipmentRepository:   equipmentRepository,
+		locationRepository:    locationRepository,
+		auditRepository:       auditRepository,
+		scopeRepository:       scopeRepository,
+		hub:                   hub,
+	}
+}
+
+// Send moves the equipment from its current place to repair and opens the
+// order, an empty vendor means the internal repair bench.
+func (s *RepairOrderService) Send(ctx context.Context, userID int64, req *dto.SendRepairRequest) (int64, error) {
+	moveAt, err := parseMoveAt(req.Date)
+	if err != nil {
+		return 0, err
+	}
+
+	equipment, err := s.equipmentRepository.Read(ctx, req.EquipmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if equipment.DeletedAt != nil {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+	}
+
+	if equipment.WrittenOffAt != nil {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentWrittenOff)
+	}
+
+	last, err := s.locationRepository.GetLast(ctx, req.EquipmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := sc.place(ctx, placeTo(last)); err != nil {
+		return 0, err
+	}
+
+	move := newMove(req.EquipmentID, userID, moveAt, sentToRepair, placeTo(last), place{})
+	move.Comment = toPGTypeText(req.Vendor)
+
+	id, err := s.repairOrderRepository.Send(ctx, &queries.CreateRepairOrderParams{
+		EquipmentID: req.EquipmentID,
+		Vendor:      toPGTypeText(req.Vendor),
+		Notes:       toPGTypeText(req.Notes),
+		SentBy:      userID,
+		SentAt:      moveAt,
+	}, move)
+	if err != nil {
+		return 0, err
+	}
+
+	publishMove(s.hub, move)
+	auditMove(ctx, s.auditRepository, model.AuditMove, move)
+	audit(ctx, s.auditRepository, model.EntityRepairOrder, model.AuditCreate, id, nil, auditState(ctx, s.repairOrderRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("equipment with id %d sent to repair with id %d", req.EquipmentID, id))
+	return id, nil
+}
+
+func (s *RepairOrderService) Read(ctx context.Context, id int64) (*model.RepairOrder, error) {
+	read, err := s.scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("repair order with id %d read", id))
+	return read, nil
+}
+
+// scoped reads the order if the place the equipment was sent from is in the
+// scope of the request user.
+func (s *RepairOrderService) scoped(ctx context.Context, id int64) (*model.RepairOrder, error) {
+	order, err := s.repairOrderRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.place(ctx, placeFrom(order.SendLocation)); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *RepairOrderService) List(ctx context.Context, status string, qp *dto.QueryParams) (*dto.ListResponse[[]*model.RepairOrder], error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if qp.ScopeDepartmentID, err = sc.catalog(); err != nil {
+		return nil, err
+	}
+
+	list, total, err := s.repairOrderRepository.List(ctx, status, qp)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d repair order listed", len(list)))
+	return &dto.ListResponse[[]*model.RepairOrder]{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+func (s *RepairOrderService) Update(ctx context.Context, id int64, req *dto.UpdateRepairRequest) (*model.RepairOrder, error) {
+	order, err := s.scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRepairTransition(order.Status, req.Status); err != nil {
+		return nil, err
+	}
+
+	if err := s.repairOrderRepository.Update(ctx, &queries.UpdateRepairOrderParams{
+		Status:        req.Status,
+		Vendor:        toPGTypeText(req.Vendor),
+		Cost:          toPGTypeText(req.Cost),
+		Notes:         toPGTypeText(req.Notes),
+		ID:            id,
+		CurrentStatus: order.Status,
+	}); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repairOrderRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityRepairOrder, model.AuditUpdate, id, order, updated)
+
+	logger.Info(fmt.Sprintf("repair order with id %d updated to %s", id, req.Status))
+	return updated, nil
+}
+
+// Return moves the equipment back to the place it was sent from and closes
+// the order.
+func (s *RepairOrderService) Return(ctx context.Context, userID, id int64, req *dto.ReturnRepairRequest) (*model.RepairOrder, error) {
+	moveAt, err := parseMoveAt(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != model.RepairRepaired && order.Status != model.RepairUnrepairable {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalRepairStatus)
+	}
+
+	move := newMove(order.Equipment.ID, userID, moveAt, returnedFromRepair, place{}, placeFrom(order.SendLocation))
+	move.Comment = toPGTypeText(order.Vendor)
+
+	locationID, err := s.repairOrderRepository.Return(ctx, id, userID, move)
+	if err != nil {
+		return nil, err
+	}
+
+	publishMove(s.hub, move)
+	auditMove(ctx, s.auditRepository, model.AuditMove, move)
+
+	returned, err := s.repairOrderRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityRepairOrder, model.AuditReturn, id, order, returned)
+
+	logger.Info(fmt.Sprintf("repair order with id %d returned, location with id %d added", id, locationID))
+	return returned, nil
+}
+
+func checkRepairTransition(from, to string) error {
+	if _, ok := repairTransitions[to]; !ok && to != model.RepairReturned {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrUnknownRepairStatus)
+	}
+
+	next, ok := repairTransitions[from]
+	if !ok {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalRepairStatus)
+	}
+
+	if from == to {
+		return nil
+	}
+
+	if !slices.Contains(next, to) {
+		return logger.Error(logger.MsgFailedToValidate, logger.ErrIllegalRepairStatus)
+	}
+
+	return nil
+}
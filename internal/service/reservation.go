@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+type ReservationService struct {
+	reservationRepository repository.Reservation
+	auditRepository       repository.Audit
+	scopeRepository       repository.Scope
+}
+
+func NewReservationService(reservationRepository repository.Reservation, auditRepository repository.Audit, scopeRepository repository.Scope) *ReservationService {
+	return &ReservationService{
+		reservationRepository: reservationRepository,
+		auditRepository:       auditRepository,
+		scopeRepository:       scopeRepository,
+	}
+}
+
+// Create holds storage equipment for a destination a move from storage can
+// reach; the move there consumes the reservation.
+func (s *ReservationService) Create(ctx context.Context, userID int64, req *dto.ReservationRequest) (int64, error) {
+	expiresAt, err := parseMoveAt(req.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	if !expiresAt.Time.After(time.Now()) {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrInvalidDateRange)
+	}
+
+	to := place{
+		departmentID: req.ToDepartmentID,
+		employeeID:   req.ToEmployeeID,
+		contractID:   req.ToContractID,
+	}
+	if _, err := nextMoveCode(place{}, to); err != nil {
+		return 0, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := sc.place(ctx, to); err != nil {
+		return 0, err
+	}
+
+	id, err := s.reservationRepository.Create(ctx, &queries.CreateReservationParams{
+		EquipmentID:    req.EquipmentID,
+		ToDepartmentID: toPGTypeInt8(to.departmentID),
+		ToEmployeeID:   toPGTypeInt8(to.employeeID),
+		ToContractID:   toPGTypeInt8(to.contractID),
+		ExpiresAt:      expiresAt,
+		Comment:        toPGTypeText(req.Comment),
+		ReservedBy:     userID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityReservation, model.AuditCreate, id, nil, auditState(ctx, s.reservationRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("equipment with id %d reserved with id %d", req.EquipmentID, id))
+	return id, nil
+}
+
+func (s *ReservationService) Read(ctx context.Context, id int64) (*model.Reservation, error) {
+	read, err := s.scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("reservation with id %d read", id))
+	return read, nil
+}
+
+// scoped reads the reservation if its destination is in the scope of the
+// request user.
+func (s *ReservationService) scoped(ctx context.Context, id int64) (*model.Reservation, error) {
+	reservation, err := s.reservationRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	var to place
+	if reservation.ToDepartment != nil {
+		to.departmentID = reservation.ToDepartment.ID
+	}
+	if reservation.ToEmployee != nil {
+		to.employeeID = reservation.ToEmployee.ID
+	}
+	if reservation.ToContract != nil {
+		to.contractID = reservation.ToContract.ID
+	}
+
+	if err := sc.place(ctx, to); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (s *ReservationService) List(ctx context.Context, status string, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Reservation], error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if qp.ScopeDepartmentID, err = sc.catalog(); err != nil {
+		return nil, err
+	}
+
+	list, total, err := s.reservationRepository.List(ctx, status, qp)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d reservation listed", len(list)))
+	return &dto.ListResponse[[]*model.Reservation]{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+func (s *ReservationService) Cancel(ctx context.Context, userID, id int64) error {
+	before, err := s.scoped(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reservationRepository.Cancel(ctx, id, userID); err != nil {
+		return err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityReservation, model.AuditCancel, id, before, auditState(ctx, s.reservationRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("reservation with id %d cancelled", id))
+	return nil
+}
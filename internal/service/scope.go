@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/list_filter"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/request_info"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+// scope is the part of the equipment the user of a request may see and
+// move. A role without role.AllDepartments is limited to the equipment
+// held by the department of the user's employee and its employees; a
+// limited user without a department is out of scope everywhere.
+type scope struct {
+	scopeRepository repository.Scope
+	limited         bool
+	departmentID    int64
+}
+
+func requestScope(ctx context.Context, scopeRepository repository.Scope) (*scope, error) {
+	s := &scope{scopeRepository: scopeRepository}
+	if request_info.UserRole(ctx).Can(role.AllDepartments) {
+		return s, nil
+	}
+
+	departmentID, err := scopeRepository.UserDepartment(ctx, request_info.UserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	s.limited = true
+	s.departmentID = departmentID
+	return s, nil
+}
+
+// equipmentInScope checks the equipment is in the scope of the request user.
+func equipmentInScope(ctx context.Context, scopeRepository repository.Scope, id int64) error {
+	sc, err := requestScope(ctx, scopeRepository)
+	if err != nil {
+		return err
+	}
+
+	return sc.equipment(ctx, id)
+}
+
+func (s *scope) department(id int64) error {
+	if !s.limited {
+		return nil
+	}
+
+	if s.departmentID == 0 || id != s.departmentID {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	return nil
+}
+
+func (s *scope) employee(ctx context.Context, id int64) error {
+	if !s.limited {
+		return nil
+	}
+
+	if s.departmentID == 0 {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	ok, err := s.scopeRepository.EmployeeInDepartment(ctx, id, s.departmentID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	return nil
+}
+
+func (s *scope) equipment(ctx context.Context, id int64) error {
+	if !s.limited {
+		return nil
+	}
+
+	if s.departmentID == 0 {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	ok, err := s.scopeRepository.EquipmentInDepartment(ctx, id, s.departmentID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	return nil
+}
+
+func (s *scope) writeOff(ctx context.Context, id int64) error {
+	if !s.limited {
+		return nil
+	}
+
+	if s.departmentID == 0 {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	ok, err := s.scopeRepository.WriteOffInDepartment(ctx, id, s.departmentID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	return nil
+}
+
+// place checks where equipment is, the storage and contracts are out of
+// the scope of every department.
+func (s *scope) place(ctx context.Context, p place) error {
+	switch {
+	case !s.limited:
+		return nil
+	case p.employeeID != 0:
+		return s.employee(ctx, p.employeeID)
+	case p.departmentID != 0:
+		return s.department(p.departmentID)
+	default:
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+}
+
+// catalog returns the department a list of equipment is limited to, 0 for
+// every department.
+func (s *scope) catalog() (int64, error) {
+	if s.limited && s.departmentID == 0 {
+		return 0, logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	}
+
+	return s.departmentID, nil
+}
+
+// topic checks a websocket topic. The events of the department, its
+// employees and their equipment are in scope; storage, contracts and the
+// stream of every equipment are not. Topics not bound to a place pass.
+func (s *scope) topic(ctx context.Context, topic string) error {
+	if !s.limited {
+		return nil
+	}
+
+	switch kind, id := websocket.ParseTopic(topic); kind {
+	case websocket.KindDepartment:
+		return s.department(id)
+	case websocket.KindEmployee:
+		return s.employee(ctx, id)
+	case websocket.KindEquipment:
+		return s.equipment(ctx, id)
+	case websocket.KindContract, websocket.TopicStorage, websocket.TopicEquipments:
+		return logger.Error(logger.MsgAccessDenied, logger.ErrOutOfScope)
+	default:
+		return nil
+	}
+}
+
+// list checks the place a list of locations is asked for.
+func (s *scope) list(ctx context.Context, qp *dto.QueryParams) error {
+	switch qp.Param {
+	case list_filter.ParamEmployee:
+		return s.place(ctx, place{employeeID: qp.ParamID})
+	case list_filter.ParamContract:
+		return s.place(ctx, place{contractID: qp.ParamID})
+	default:
+		return s.place(ctx, place{departmentID: qp.ParamID})
+	}
+}
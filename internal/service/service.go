@@ -7,35 +7,52 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oatsmoke/warehouse_backend/internal/dto"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/jwt_auth"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/kafka"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/oatsmoke/warehouse_backend/internal/repository"
 )
 
 type Service struct {
-	Auth       *AuthService
-	User       *UserService
-	Employee   *EmployeeService
-	Department *DepartmentService
-	Category   *CategoryService
-	Profile    *ProfileService
-	Equipment  *EquipmentService
-	Location   *LocationService
-	Contract   *ContractService
-	Company    *CompanyService
-}
-
-func New(repository *repository.Repository) *Service {
+	Auth         *AuthService
+	User         *UserService
+	Employee     *EmployeeService
+	Department   *DepartmentService
+	Category     *CategoryService
+	Profile      *ProfileService
+	Equipment    *EquipmentService
+	Location     *LocationService
+	Contract     *ContractService
+	Company      *CompanyService
+	Stocktaking  *StocktakingService
+	WriteOff     *WriteOffService
+	RepairOrder  *RepairOrderService
+	Reservation  *ReservationService
+	StockMinimum *StockMinimumService
+	Outbox       *OutboxService
+	Audit        *AuditService
+}
+
+func New(repository *repository.Repository, hub *websocket.Hub, publisher kafka.Publisher) *Service {
 	return &Service{
-		Auth:       NewAuthService(repository.Auth, repository.User),
-		User:       NewUserService(repository.User, repository.Employee),
-		Employee:   NewEmployeeService(repository.Employee),
-		Department: NewDepartmentService(repository.Department),
-		Category:   NewCategoryService(repository.Category),
-		Profile:    NewProfileService(repository.Profile),
-		Equipment:  NewEquipmentService(repository.Equipment, repository.Location),
-		Location:   NewLocationService(repository.Location, repository.Replace, repository.Category),
-		Contract:   NewContractService(repository.Contract),
-		Company:    NewCompanyService(repository.Company),
+		Auth:         NewAuthService(repository.Auth, repository.User),
+		User:         NewUserService(repository.User, repository.Employee, repository.Audit, hub),
+		Employee:     NewEmployeeService(repository.Employee, repository.Audit),
+		Department:   NewDepartmentService(repository.Department, repository.Audit),
+		Category:     NewCategoryService(repository.Category, repository.Audit),
+		Profile:      NewProfileService(repository.Profile, repository.Audit),
+		Equipment:    NewEquipmentService(repository.Equipment, repository.Location, repository.Company, repository.Profile, repository.RepairOrder, repository.Audit, repository.Scope, hub),
+		Location:     NewLocationService(repository.Location, repository.Equipment, repository.Replace, repository.Audit, repository.Scope, hub),
+		Contract:     NewContractService(repository.Contract, repository.Audit),
+		Company:      NewCompanyService(repository.Company, repository.Audit),
+		Stocktaking:  NewStocktakingService(repository.Stocktaking, repository.Audit, repository.Scope, hub),
+		WriteOff:     NewWriteOffService(repository.WriteOff, repository.Equipment, repository.Location, repository.Audit, repository.Scope, hub),
+		RepairOrder:  NewRepairOrderService(repository.RepairOrder, repository.Equipment, repository.Location, repository.Audit, repository.Scope, hub),
+		Reservation:  NewReservationService(repository.Reservation, repository.Audit, repository.Scope),
+		StockMinimum: NewStockMinimumService(repository.StockMinimum, repository.Audit, hub),
+		Outbox:       NewOutboxService(repository.Outbox, publisher),
+		Audit:        NewAuditService(repository.Audit),
 	}
 }
 
@@ -104,24 +121,28 @@ type Profile interface {
 }
 
 type Equipment interface {
-	Create(ctx context.Context, userId int64, req *dto.CreateEquipmentRequest) error
+	Create(ctx context.Context, userId int64, req *dto.CreateEquipmentRequest) (*dto.CreateEquipmentResponse, error)
 	Read(ctx context.Context, id int64) (*model.Equipment, error)
+	Lookup(ctx context.Context, code string) (*dto.LookupEquipmentResponse, error)
 	Update(ctx context.Context, equipment *model.Equipment) error
 	Delete(ctx context.Context, id int64) error
 	Restore(ctx context.Context, id int64) error
 	List(ctx context.Context, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Equipment], error)
+	Import(ctx context.Context, userId int64, req *dto.ImportEquipmentRequest, rows [][]string) (*dto.ImportEquipmentResponse, error)
+	Duplicates(ctx context.Context) ([]*model.SerialNumberDuplicate, error)
+	Merge(ctx context.Context, userID, equipmentID int64, req *dto.MergeEquipmentRequest) (*model.EquipmentMerge, error)
 }
 
 type Location interface {
-	List(ctx context.Context, toDepartmentID int64) (*dto.ListResponse[[]*model.Equipment], error)
-	//AddToStorage(ctx context.Context, date *time.Time, equipmentId, employeeId, companyId int64) error
-	//TransferTo(ctx context.Context, EmployeeId int64, requests []*model.RequestLocation) error
-	//Delete(ctx context.Context, id int64) error
+	Move(ctx context.Context, userID int64, req *dto.MoveEquipmentRequest) error
+	Replace(ctx context.Context, userID int64, req *dto.ReplaceEquipmentRequest) (*model.Replace, error)
+	Undo(ctx context.Context, userID int64, userRole role.Role, equipmentID int64) error
+	History(ctx context.Context, equipmentID int64, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Location], error)
+	List(ctx context.Context, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Equipment], error)
+	DepartmentBalance(ctx context.Context, req *dto.DepartmentBalanceRequest) (*model.DepartmentBalance, error)
+	Topics(ctx context.Context) (func(topic string) bool, error)
 	//GetById(ctx context.Context, equipmentId int64) (*model.Location, error)
 	//GetByIds(ctx context.Context, equipmentIds []int64) ([]*model.Location, error)
-	//GetHistory(ctx context.Context, equipmentId int64) ([]*model.Location, error)
-	//GetByLocation(ctx context.Context, toDepartment, toEmployee, toContract int64) ([]*model.Location, error)
-	//ReportByCategory(ctx context.Context, departmentId int64, date *time.Time) (*model.Report, error)
 }
 
 type Contract interface {
@@ -169,6 +190,48 @@ func firstRune(str string) rune {
 	return 0
 }
 
+type Stocktaking interface {
+	Open(ctx context.Context, userID int64, req *dto.OpenStocktakingRequest) (int64, error)
+	Read(ctx context.Context, id int64) (*model.StocktakingReport, error)
+	Scan(ctx context.Context, userID, id int64, req *dto.ScanStocktakingRequest) error
+	Close(ctx context.Context, userID, id int64) (*model.StocktakingReport, error)
+	Apply(ctx context.Context, userID, id int64, req *dto.ApplyStocktakingRequest) (*dto.ApplyStocktakingResponse, error)
+}
+
+type WriteOff interface {
+	Create(ctx context.Context, userID int64, req *dto.WriteOffRequest) (int64, error)
+	Read(ctx context.Context, id int64) (*model.WriteOff, error)
+	List(ctx context.Context, status string, qp *dto.QueryParams) (*dto.ListResponse[[]*model.WriteOff], error)
+	Approve(ctx context.Context, userID, id int64, req *dto.ApproveWriteOffRequest) (*model.WriteOff, error)
+	Reject(ctx context.Context, userID, id int64) error
+}
+
+type RepairOrder interface {
+	Send(ctx context.Context, userID int64, req *dto.SendRepairRequest) (int64, error)
+	Read(ctx context.Context, id int64) (*model.RepairOrder, error)
+	List(ctx context.Context, status string, qp *dto.QueryParams) (*dto.ListResponse[[]*model.RepairOrder], error)
+	Update(ctx context.Context, id int64, req *dto.UpdateRepairRequest) (*model.RepairOrder, error)
+	Return(ctx context.Context, userID, id int64, req *dto.ReturnRepairRequest) (*model.RepairOrder, error)
+}
+
+type Reservation interface {
+	Create(ctx context.Context, userID int64, req *dto.ReservationRequest) (int64, error)
+	Read(ctx context.Context, id int64) (*model.Reservation, error)
+	List(ctx context.Context, status string, qp *dto.QueryParams) (*dto.ListResponse[[]*model.Reservation], error)
+	Cancel(ctx context.Context, userID, id int64) error
+}
+
+type StockMinimum interface {
+	Set(ctx context.Context, req *dto.StockMinimumRequest) (int64, error)
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context) ([]*model.StockMinimum, error)
+	LowStock(ctx context.Context) ([]*model.StockMinimum, error)
+}
+
+type Audit interface {
+	List(ctx context.Context, req *dto.AuditRequest, qp *dto.QueryParams) (*dto.ListResponse[[]*model.AuditEntry], error)
+}
+
 func toPGTypeInt8(id int64) pgtype.Int8 {
 	var value pgtype.Int8
 
@@ -181,3 +244,16 @@ func toPGTypeInt8(id int64) pgtype.Int8 {
 
 	return value
 }
+
+func toPGTypeText(str string) pgtype.Text {
+	var value pgtype.Text
+
+	if str != "" {
+		value = pgtype.Text{
+			String: str,
+			Valid:  true,
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/email"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/role"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+type StockMinimumService struct {
+	stockMinimumRepository repository.StockMinimum
+	auditRepository        repository.Audit
+	hub                    *websocket.Hub
+}
+
+func NewStockMinimumService(stockMinimumRepository repository.StockMinimum, auditRepository repository.Audit, hub *websocket.Hub) *StockMinimumService {
+	return &StockMinimumService{
+		stockMinimumRepository: stockMinimumRepository,
+		auditRepository:        auditRepository,
+		hub:                    hub,
+	}
+}
+
+func (s *StockMinimumService) Set(ctx context.Context, req *dto.StockMinimumRequest) (int64, error) {
+	id, err := s.stockMinimumRepository.Set(ctx, &queries.SetStockMinimumParams{
+		ProfileID:    req.ProfileID,
+		DepartmentID: toPGTypeInt8(req.DepartmentID),
+		MinQuantity:  req.MinQuantity,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityStockMinimum, model.AuditSet, id, nil, req)
+
+	logger.Info(fmt.Sprintf("stock minimum with id %d set to %d", id, req.MinQuantity))
+	return id, nil
+}
+
+func (s *StockMinimumService) Delete(ctx context.Context, id int64) error {
+	if err := s.stockMinimumRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityStockMinimum, model.AuditDelete, id, nil, nil)
+
+	logger.Info(fmt.Sprintf("stock minimum with id %d deleted", id))
+	return nil
+}
+
+func (s *StockMinimumService) List(ctx context.Context) ([]*model.StockMinimum, error) {
+	list, err := s.stockMinimumRepository.List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d stock minimum listed", len(list)))
+	return list, nil
+}
+
+func (s *StockMinimumService) LowStock(ctx context.Context) ([]*model.StockMinimum, error) {
+	list, err := s.stockMinimumRepository.List(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d low stock listed", len(list)))
+	return list, nil
+}
+
+// Run checks the stock right away and then every interval until ctx is done.
+func (s *StockMinimumService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// errors are logged where they happen, the next tick retries
+		_ = s.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check compares the holdings with the minimums and alerts about the ones
+// that dropped below since the previous check, so a shortage is reported
+// once until it is refilled. A minimum is flagged low only after the alert
+// went out, a failed send is retried on the next check.
+func (s *StockMinimumService) Check(ctx context.Context) error {
+	list, err := s.stockMinimumRepository.List(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	low := make([]int64, 0, len(list))
+	alerts := make([]*model.StockMinimum, 0, len(list))
+	for _, minimum := range list {
+		if minimum.Quantity < int64(minimum.MinQuantity) {
+			low = append(low, minimum.ID)
+			if minimum.LowSince == nil {
+				alerts = append(alerts, minimum)
+			}
+		}
+	}
+
+	if len(alerts) > 0 {
+		if err := s.sendLowStock(ctx, alerts); err != nil {
+			return err
+		}
+	}
+
+	marked, err := s.stockMinimumRepository.MarkLow(ctx, low)
+	if err != nil {
+		return err
+	}
+
+	if len(marked) < 1 {
+		return nil
+	}
+
+	byID := make(map[int64]*model.StockMinimum, len(alerts))
+	for _, alert := range alerts {
+		byID[alert.ID] = alert
+	}
+
+	published := make([]*model.StockMinimum, 0, len(marked))
+	topics := []string{websocket.TopicStock}
+	for _, id := range marked {
+		alert, ok := byID[id]
+		if !ok {
+			continue
+		}
+		published = append(published, alert)
+		if alert.Department != nil {
+			topics = append(topics, websocket.DepartmentTopic(alert.Department.ID))
+		}
+	}
+	s.hub.Publish(websocket.StockLow, topics, published)
+
+	logger.Info(fmt.Sprintf("%d low stock alerted", len(marked)))
+	return nil
+}
+
+func (s *StockMinimumService) sendLowStock(ctx context.Context, alerts []*model.StockMinimum) error {
+	users, err := s.stockMinimumRepository.Recipients(ctx, role.WithPermission(role.StockManage))
+	if err != nil {
+		return err
+	}
+
+	if len(users) < 1 {
+		return nil
+	}
+
+	to := make([]*email.SendTo, len(users))
+	for i, user := range users {
+		to[i] = &email.SendTo{
+			Email:    user.Email,
+			Username: user.Username,
+		}
+	}
+
+	items := make([]*email.LowStockItem, len(alerts))
+	for i, alert := range alerts {
+		items[i] = &email.LowStockItem{
+			Profile:     alert.Profile.Title,
+			Place:       "central storage",
+			Quantity:    alert.Quantity,
+			MinQuantity: alert.MinQuantity,
+		}
+		if alert.Department != nil {
+			items[i].Place = alert.Department.Title
+		}
+	}
+
+	return email.SendLowStock(to, items)
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/serial"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+type StocktakingService struct {
+	stocktakingRepository repository.Stocktaking
+	auditRepository       repository.Audit
+	scopeRepository       repository.Scope
+	hub                   *websocket.Hub
+}
+
+func NewStocktakingService(stocktakingRepository repository.Stocktaking, auditRepository repository.Audit, scopeRepository repository.Scope, hub *websocket.Hub) *StocktakingService {
+	return &StocktakingService{
+		stocktakingRepository: stocktakingRepository,
+		auditRepository:       auditRepository,
+		scopeRepository:       scopeRepository,
+		hub:                   hub,
+	}
+}
+
+// Open starts a session for a department, an employee or the storage
+// when neither is set.
+func (s *StocktakingService) Open(ctx context.Context, userID int64, req *dto.OpenStocktakingRequest) (int64, error) {
+	if req.DepartmentID != 0 && req.EmployeeID != 0 {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrInvalidDestination)
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := sc.place(ctx, place{departmentID: req.DepartmentID, employeeID: req.EmployeeID}); err != nil {
+		return 0, err
+	}
+
+	id, err := s.stocktakingRepository.Create(ctx, &queries.CreateStocktakingParams{
+		DepartmentID: toPGTypeInt8(req.DepartmentID),
+		EmployeeID:   toPGTypeInt8(req.EmployeeID),
+		OpenedBy:     userID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityStocktaking, model.AuditCreate, id, nil, auditState(ctx, s.stocktakingRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("stocktaking with id %d opened", id))
+	return id, nil
+}
+
+func (s *StocktakingService) Read(ctx context.Context, id int64) (*model.StocktakingReport, error) {
+	stocktaking, err := s.scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.report(ctx, stocktaking)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("stocktaking with id %d read", id))
+	return report, nil
+}
+
+// scoped reads the session if its place is in the scope of the request
+// user.
+func (s *StocktakingService) scoped(ctx context.Context, id int64) (*model.Stocktaking, error) {
+	stocktaking, err := s.stocktakingRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.place(ctx, stocktakingPlace(stocktaking)); err != nil {
+		return nil, err
+	}
+
+	return stocktaking, nil
+}
+
+func (s *StocktakingService) report(ctx context.Context, stocktaking *model.Stocktaking) (*model.StocktakingReport, error) {
+	id := stocktaking.ID
+	discrepancies, err := s.stocktakingRepository.Discrepancies(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.StocktakingReport{
+		Stocktaking:    stocktaking,
+		Missing:        make([]*model.StocktakingDiscrepancy, 0),
+		Unexpected:     make([]*model.StocktakingDiscrepancy, 0),
+		FoundElsewhere: make([]*model.StocktakingDiscrepancy, 0),
+	}
+
+	for _, discrepancy := range discrepancies {
+		switch discrepancy.Status {
+		case model.StocktakingMissing:
+			report.Missing = append(report.Missing, discrepancy)
+		case model.StocktakingUnexpected:
+			report.Unexpected = append(report.Unexpected, discrepancy)
+		case model.StocktakingFoundElsewhere:
+			report.FoundElsewhere = append(report.FoundElsewhere, discrepancy)
+		}
+	}
+
+	return report, nil
+}
+
+func (s *StocktakingService) Scan(ctx context.Context, userID, id int64, req *dto.ScanStocktakingRequest) error {
+	if _, err := s.scoped(ctx, id); err != nil {
+		return err
+	}
+
+	serialNumbers := make([]string, 0, len(req.SerialNumbers))
+	canonicalSerialNumbers := make([]string, 0, len(req.SerialNumbers))
+	for _, sn := range req.SerialNumbers {
+		if sn = strings.TrimSpace(sn); sn != "" {
+			serialNumbers = append(serialNumbers, sn)
+			canonicalSerialNumbers = append(canonicalSerialNumbers, serial.Normalize(sn, serial.Canonical))
+		}
+	}
+
+	added, err := s.stocktakingRepository.Scan(ctx, &queries.CreateStocktakingScansParams{
+		StocktakingID:          id,
+		ScannedBy:              userID,
+		SerialNumbers:          serialNumbers,
+		CanonicalSerialNumbers: canonicalSerialNumbers,
+	})
+	if err != nil {
+		return err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityStocktaking, model.AuditScan, id, nil, map[string]any{
+		"serial_numbers": serialNumbers,
+		"added":          added,
+	})
+
+	logger.Info(fmt.Sprintf("stocktaking with id %d: %d serial numbers scanned", id, added))
+	return nil
+}
+
+// Close finishes scanning and returns the discrepancy report.
+func (s *StocktakingService) Close(ctx context.Context, userID, id int64) (*model.StocktakingReport, error) {
+	before, err := s.scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.stocktakingRepository.Close(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	after, err := s.stocktakingRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityStocktaking, model.AuditClose, id, before, after)
+
+	logger.Info(fmt.Sprintf("stocktaking with id %d closed", id))
+	return s.report(ctx, after)
+}
+
+// Apply moves equipment found elsewhere to the place of a closed session.
+// Missing and unexpected items are left for a manual decision.
+func (s *StocktakingService) Apply(ctx context.Context, userID, id int64, req *dto.ApplyStocktakingRequest) (*dto.ApplyStocktakingResponse, error) {
+	moveAt, err := parseMoveAt(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	stocktaking, err := s.scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.report(ctx, stocktaking)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Stocktaking.ClosedAt == nil {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrStocktakingNotClosed)
+	}
+
+	if report.Stocktaking.AppliedAt != nil {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrStocktakingApplied)
+	}
+
+	to := stocktakingPlace(report.Stocktaking)
+
+	res := &dto.ApplyStocktakingResponse{
+		Skipped: make([]*dto.FailedEquipment, 0),
+	}
+
+	moves := make([]*queries.MoveToLocationParams, 0, len(report.FoundElsewhere))
+	serialNumbers := make(map[int64]string, len(report.FoundElsewhere))
+	for _, item := range report.FoundElsewhere {
+		from := placeOf(item)
+		if from == to {
+			continue
+		}
+
+		code, err := nextMoveCode(from, to)
+		if err != nil {
+			res.Skipped = append(res.Skipped, &dto.FailedEquipment{
+				ID:           item.Equipment.ID,
+				SerialNumber: item.SerialNumber,
+				Reason:       dto.ReasonIllegalMove,
+			})
+			continue
+		}
+
+		move := newMove(item.Equipment.ID, userID, moveAt, code, from, to)
+		move.Comment = toPGTypeText(req.Comment)
+		moves = append(moves, move)
+		serialNumbers[item.Equipment.ID] = item.SerialNumber
+	}
+
+	ids, skipped, err := s.stocktakingRepository.Apply(ctx, id, moves)
+	if err != nil {
+		return nil, err
+	}
+	res.Locations = ids
+
+	for _, move := range moves {
+		if err, ok := skipped[move.EquipmentID]; ok {
+			res.Skipped = append(res.Skipped, &dto.FailedEquipment{
+				ID:           move.EquipmentID,
+				SerialNumber: serialNumbers[move.EquipmentID],
+				Reason:       skipReason(err),
+			})
+			continue
+		}
+
+		auditMove(ctx, s.auditRepository, model.AuditApply, move)
+		publishMove(s.hub, move)
+	}
+	audit(ctx, s.auditRepository, model.EntityStocktaking, model.AuditApply, id, report.Stocktaking, auditState(ctx, s.stocktakingRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("stocktaking with id %d applied: %d moves", id, len(ids)))
+	return res, nil
+}
+
+// skipReason names the state that kept an item found elsewhere in place.
+func skipReason(err error) string {
+	switch {
+	case errors.Is(err, logger.ErrEquipmentDeleted):
+		return dto.ReasonEquipmentDeleted
+	case errors.Is(err, logger.ErrEquipmentWrittenOff):
+		return dto.ReasonEquipmentWrittenOff
+	case errors.Is(err, logger.ErrEquipmentInRepair):
+		return dto.ReasonEquipmentInRepair
+	case errors.Is(err, logger.ErrEquipmentReserved):
+		return dto.ReasonEquipmentReserved
+	case errors.Is(err, logger.ErrLocationChanged):
+		return dto.ReasonLocationChanged
+	default:
+		return dto.ReasonIllegalMove
+	}
+}
+
+func stocktakingPlace(stocktaking *model.Stocktaking) place {
+	var p place
+	if stocktaking.Department != nil {
+		p.departmentID = stocktaking.Department.ID
+	}
+	if stocktaking.Employee != nil {
+		p.employeeID = stocktaking.Employee.ID
+	}
+
+	return p
+}
+
+func placeOf(item *model.StocktakingDiscrepancy) place {
+	var p place
+	if item.Department != nil {
+		p.departmentID = item.Department.ID
+	}
+	if item.Employee != nil {
+		p.employeeID = item.Employee.ID
+	}
+	if item.Contract != nil {
+		p.contractID = item.Contract.ID
+	}
+
+	return p
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/oatsmoke/warehouse_backend/internal/lib/email"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/generate"
 	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
 	"github.com/oatsmoke/warehouse_backend/internal/model"
 	"github.com/oatsmoke/warehouse_backend/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -17,12 +18,16 @@ import (
 type UserService struct {
 	userRepository     repository.User
 	employeeRepository repository.Employee
+	auditRepository    repository.Audit
+	hub                *websocket.Hub
 }
 
-func NewUserService(userRepository repository.User, employeeRepository repository.Employee) *UserService {
+func NewUserService(userRepository repository.User, employeeRepository repository.Employee, auditRepository repository.Audit, hub *websocket.Hub) *UserService {
 	return &UserService{
 		userRepository:     userRepository,
 		employeeRepository: employeeRepository,
+		auditRepository:    auditRepository,
+		hub:                hub,
 	}
 }
 
@@ -57,6 +62,10 @@ func (s *UserService) Create(ctx context.Context, user *model.User) error {
 
 	go email.Send([]*email.SendTo{sendTo})
 
+	publishUser(s.hub, id)
+
+	audit(ctx, s.auditRepository, model.EntityUser, model.AuditCreate, id, nil, auditState(ctx, s.userRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("user with id %d created", id))
 	return nil
 }
@@ -72,21 +81,31 @@ func (s *UserService) Read(ctx context.Context, id int64) (*model.User, error) {
 }
 
 func (s *UserService) Update(ctx context.Context, user *model.User) error {
+	before := auditState(ctx, s.userRepository.Read, user.ID)
 	err := s.userRepository.Update(ctx, user)
 	if err != nil {
 		return err
 	}
 
+	publishUser(s.hub, user.ID)
+
+	audit(ctx, s.auditRepository, model.EntityUser, model.AuditUpdate, user.ID, before, auditState(ctx, s.userRepository.Read, user.ID))
+
 	logger.Info(fmt.Sprintf("user with id %d updated", user.ID))
 	return nil
 }
 
 func (s *UserService) Delete(ctx context.Context, id int64) error {
+	before := auditState(ctx, s.userRepository.Read, id)
 	err := s.userRepository.Delete(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	publishUser(s.hub, id)
+
+	audit(ctx, s.auditRepository, model.EntityUser, model.AuditDelete, id, before, nil)
+
 	logger.Info(fmt.Sprintf("user with id %d deleted", id))
 	return nil
 }
@@ -142,6 +161,8 @@ func (s *UserService) SetPassword(ctx context.Context, id int64, oldPassword, ne
 		return err
 	}
 
+	audit(ctx, s.auditRepository, model.EntityUser, model.AuditSetPassword, id, nil, nil)
+
 	logger.Info(fmt.Sprintf("user with id %d changed password", id))
 	return nil
 }
@@ -172,15 +193,22 @@ func (s *UserService) ResetPassword(ctx context.Context, id int64) error {
 
 	go email.Send([]*email.SendTo{sendTo})
 
+	audit(ctx, s.auditRepository, model.EntityUser, model.AuditResetPassword, id, nil, nil)
+
 	logger.Info(fmt.Sprintf("user with id %d reset password", id))
 	return nil
 }
 
 func (s *UserService) SetEnabled(ctx context.Context, id int64, enabled bool) error {
+	before := auditState(ctx, s.userRepository.Read, id)
 	if err := s.userRepository.SetEnabled(ctx, id, enabled); err != nil {
 		return err
 	}
 
+	publishUser(s.hub, id)
+
+	audit(ctx, s.auditRepository, model.EntityUser, model.AuditSetEnabled, id, before, auditState(ctx, s.userRepository.Read, id))
+
 	logger.Info(fmt.Sprintf("user with id %d set enabled to %t", id, enabled))
 	return nil
 }
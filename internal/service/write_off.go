@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	queries "github.com/oatsmoke/warehouse_backend/internal/db"
+	"github.com/oatsmoke/warehouse_backend/internal/dto"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/logger"
+	"github.com/oatsmoke/warehouse_backend/internal/lib/websocket"
+	"github.com/oatsmoke/warehouse_backend/internal/model"
+	"github.com/oatsmoke/warehouse_backend/internal/repository"
+)
+
+type WriteOffService struct {
+	writeOffRepository  repository.WriteOff
+	equipmentRepository repository.Equipment
+	locationRepository  repository.Location
+	auditRepository     repository.Audit
+	scopeRepository     repository.Scope
+	hub                 *websocket.Hub
+}
+
+func NewWriteOffService(
+	writeOffRepository repository.WriteOff,
+	equipmentRepository repository.Equipment,
+	locationRepository repository.Location,
+	auditRepository repository.Audit,
+	scopeRepository repository.Scope,
+	hub *websocket.Hub,
+) *WriteOffService {
+	return &WriteOffService{
+		writeOffRepository:  writeOffRepository,
+		equipmentRepository: equipmentRepository,
+		locationRepository:  locationRepository,
+		auditRepository:     auditRepository,
+		scopeRepository:     scopeRepository,
+		hub:                 hub,
+	}
+}
+
+// Create files a pending request, an equipment can have only one.
+func (s *WriteOffService) Create(ctx context.Context, userID int64, req *dto.WriteOffRequest) (int64, error) {
+	switch req.Reason {
+	case model.WriteOffBroken, model.WriteOffLost, model.WriteOffStolen, model.WriteOffObsolete:
+	default:
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrUnknownWriteOffReason)
+	}
+
+	equipment, err := s.equipmentRepository.Read(ctx, req.EquipmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if equipment.DeletedAt != nil {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentDeleted)
+	}
+
+	if equipment.WrittenOffAt != nil {
+		return 0, logger.Error(logger.MsgFailedToValidate, logger.ErrEquipmentWrittenOff)
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := sc.equipment(ctx, req.EquipmentID); err != nil {
+		return 0, err
+	}
+
+	id, err := s.writeOffRepository.Create(ctx, &queries.CreateWriteOffParams{
+		EquipmentID: req.EquipmentID,
+		Reason:      req.Reason,
+		Comment:     toPGTypeText(req.Comment),
+		RequestedBy: userID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityWriteOff, model.AuditCreate, id, nil, auditState(ctx, s.writeOffRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("write-off with id %d requested", id))
+	return id, nil
+}
+
+func (s *WriteOffService) Read(ctx context.Context, id int64) (*model.WriteOff, error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.writeOff(ctx, id); err != nil {
+		return nil, err
+	}
+
+	read, err := s.writeOffRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("write-off with id %d read", id))
+	return read, nil
+}
+
+func (s *WriteOffService) List(ctx context.Context, status string, qp *dto.QueryParams) (*dto.ListResponse[[]*model.WriteOff], error) {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if qp.ScopeDepartmentID, err = sc.catalog(); err != nil {
+		return nil, err
+	}
+
+	list, total, err := s.writeOffRepository.List(ctx, status, qp)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("%d write-off listed", len(list)))
+	return &dto.ListResponse[[]*model.WriteOff]{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+// Approve moves the equipment from its current place to the terminal
+// written off state; it stays in history but leaves every holdings list.
+func (s *WriteOffService) Approve(ctx context.Context, userID, id int64, req *dto.ApproveWriteOffRequest) (*model.WriteOff, error) {
+	moveAt, err := parseMoveAt(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	writeOff, err := s.writeOffRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if writeOff.Status != model.WriteOffPending {
+		return nil, logger.Error(logger.MsgFailedToValidate, logger.ErrWriteOffDecided)
+	}
+
+	last, err := s.locationRepository.GetLast(ctx, writeOff.Equipment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.place(ctx, placeTo(last)); err != nil {
+		return nil, err
+	}
+
+	move := newMove(writeOff.Equipment.ID, userID, moveAt, writtenOff, placeTo(last), place{})
+	move.Comment = toPGTypeText(writeOff.Reason)
+
+	locationID, err := s.writeOffRepository.Approve(ctx, id, userID, move)
+	if err != nil {
+		return nil, err
+	}
+
+	publishMove(s.hub, move)
+	auditMove(ctx, s.auditRepository, model.AuditMove, move)
+
+	approved, err := s.writeOffRepository.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityWriteOff, model.AuditApprove, id, writeOff, approved)
+
+	logger.Info(fmt.Sprintf("write-off with id %d approved, location with id %d added", id, locationID))
+	return approved, nil
+}
+
+func (s *WriteOffService) Reject(ctx context.Context, userID, id int64) error {
+	sc, err := requestScope(ctx, s.scopeRepository)
+	if err != nil {
+		return err
+	}
+
+	if err := sc.writeOff(ctx, id); err != nil {
+		return err
+	}
+
+	before := auditState(ctx, s.writeOffRepository.Read, id)
+	if err := s.writeOffRepository.Reject(ctx, id, userID); err != nil {
+		return err
+	}
+
+	audit(ctx, s.auditRepository, model.EntityWriteOff, model.AuditReject, id, before, auditState(ctx, s.writeOffRepository.Read, id))
+
+	logger.Info(fmt.Sprintf("write-off with id %d rejected", id))
+	return nil
+}
@@ -0,0 +1,17 @@
+-- Create "location_reversals" table
+CREATE TABLE "public"."location_reversals" (
+  "id" bigserial NOT NULL,
+  "location_id" bigint NOT NULL,
+  "replace_id" bigint NULL,
+  "equipment_id" bigint NOT NULL,
+  "reverted_by" bigint NOT NULL,
+  "reverted_at" timestamptz NOT NULL,
+  "location" jsonb NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "location_reversals_equipment_id_fkey" FOREIGN KEY ("equipment_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "location_reversals_reverted_by_fkey" FOREIGN KEY ("reverted_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT
+);
+-- Create index "idx_location_reversals_equipment" to table: "location_reversals"
+CREATE INDEX "idx_location_reversals_equipment" ON "public"."location_reversals" ("equipment_id");
+-- Create index "idx_location_reversals_reverted_by" to table: "location_reversals"
+CREATE INDEX "idx_location_reversals_reverted_by" ON "public"."location_reversals" ("reverted_by");
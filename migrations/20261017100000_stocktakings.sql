@@ -0,0 +1,45 @@
+-- Create "stocktakings" table
+CREATE TABLE "public"."stocktakings" (
+  "id" bigserial NOT NULL,
+  "department_id" bigint NULL,
+  "employee_id" bigint NULL,
+  "opened_by" bigint NOT NULL,
+  "opened_at" timestamptz NOT NULL,
+  "closed_by" bigint NULL,
+  "closed_at" timestamptz NULL,
+  "applied_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "stocktakings_closed_by_fkey" FOREIGN KEY ("closed_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "stocktakings_department_id_fkey" FOREIGN KEY ("department_id") REFERENCES "public"."departments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "stocktakings_employee_id_fkey" FOREIGN KEY ("employee_id") REFERENCES "public"."employees" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "stocktakings_opened_by_fkey" FOREIGN KEY ("opened_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT
+);
+-- Create index "idx_stocktakings_department" to table: "stocktakings"
+CREATE INDEX "idx_stocktakings_department" ON "public"."stocktakings" ("department_id");
+-- Create index "idx_stocktakings_employee" to table: "stocktakings"
+CREATE INDEX "idx_stocktakings_employee" ON "public"."stocktakings" ("employee_id");
+-- Create "stocktaking_items" table
+CREATE TABLE "public"."stocktaking_items" (
+  "stocktaking_id" bigint NOT NULL,
+  "equipment_id" bigint NOT NULL,
+  PRIMARY KEY ("stocktaking_id", "equipment_id"),
+  CONSTRAINT "stocktaking_items_equipment_id_fkey" FOREIGN KEY ("equipment_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "stocktaking_items_stocktaking_id_fkey" FOREIGN KEY ("stocktaking_id") REFERENCES "public"."stocktakings" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create "stocktaking_scans" table
+CREATE TABLE "public"."stocktaking_scans" (
+  "id" bigserial NOT NULL,
+  "stocktaking_id" bigint NOT NULL,
+  "serial_number" character varying(100) NOT NULL,
+  "equipment_id" bigint NULL,
+  "scanned_by" bigint NOT NULL,
+  "scanned_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "stocktaking_scans_equipment_id_fkey" FOREIGN KEY ("equipment_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "stocktaking_scans_scanned_by_fkey" FOREIGN KEY ("scanned_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "stocktaking_scans_stocktaking_id_fkey" FOREIGN KEY ("stocktaking_id") REFERENCES "public"."stocktakings" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "stocktaking_scans_stocktaking_id_serial_number_key" to table: "stocktaking_scans"
+CREATE UNIQUE INDEX "stocktaking_scans_stocktaking_id_serial_number_key" ON "public"."stocktaking_scans" ("stocktaking_id", "serial_number");
+-- Create index "idx_stocktaking_scans_equipment" to table: "stocktaking_scans"
+CREATE INDEX "idx_stocktaking_scans_equipment" ON "public"."stocktaking_scans" ("equipment_id");
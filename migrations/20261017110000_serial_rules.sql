@@ -0,0 +1,2 @@
+-- Modify "profiles" table
+ALTER TABLE "public"."profiles" ADD COLUMN "serial_rule" text[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,17 @@
+-- Create "equipment_merges" table
+CREATE TABLE "public"."equipment_merges" (
+  "id" bigserial NOT NULL,
+  "equipment_id" bigint NOT NULL,
+  "duplicate_id" bigint NOT NULL,
+  "merged_by" bigint NOT NULL,
+  "merged_at" timestamptz NOT NULL,
+  "locations" bigint NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "equipment_merges_duplicate_id_fkey" FOREIGN KEY ("duplicate_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "equipment_merges_equipment_id_fkey" FOREIGN KEY ("equipment_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "equipment_merges_merged_by_fkey" FOREIGN KEY ("merged_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT
+);
+-- Create index "idx_equipment_merges_duplicate" to table: "equipment_merges"
+CREATE INDEX "idx_equipment_merges_duplicate" ON "public"."equipment_merges" ("duplicate_id");
+-- Create index "idx_equipment_merges_equipment" to table: "equipment_merges"
+CREATE INDEX "idx_equipment_merges_equipment" ON "public"."equipment_merges" ("equipment_id");
@@ -0,0 +1,26 @@
+-- Modify "equipments" table
+ALTER TABLE "public"."equipments" ADD COLUMN "written_off_at" timestamptz NULL;
+-- Create "write_offs" table
+CREATE TABLE "public"."write_offs" (
+  "id" bigserial NOT NULL,
+  "equipment_id" bigint NOT NULL,
+  "reason" character varying(100) NOT NULL,
+  "comment" character varying(100) NULL,
+  "status" character varying(100) NOT NULL,
+  "requested_by" bigint NOT NULL,
+  "requested_at" timestamptz NOT NULL,
+  "decided_by" bigint NULL,
+  "decided_at" timestamptz NULL,
+  "location_id" bigint NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "write_offs_decided_by_fkey" FOREIGN KEY ("decided_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "write_offs_equipment_id_fkey" FOREIGN KEY ("equipment_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "write_offs_location_id_fkey" FOREIGN KEY ("location_id") REFERENCES "public"."locations" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "write_offs_requested_by_fkey" FOREIGN KEY ("requested_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT
+);
+-- Create index "idx_write_offs_equipment" to table: "write_offs"
+CREATE INDEX "idx_write_offs_equipment" ON "public"."write_offs" ("equipment_id");
+-- Create index "idx_write_offs_pending" to table: "write_offs"
+CREATE UNIQUE INDEX "idx_write_offs_pending" ON "public"."write_offs" ("equipment_id") WHERE ((status)::text = 'pending'::text);
+-- Create index "idx_write_offs_status" to table: "write_offs"
+CREATE INDEX "idx_write_offs_status" ON "public"."write_offs" ("status");
@@ -0,0 +1,28 @@
+-- Create "repair_orders" table
+CREATE TABLE "public"."repair_orders" (
+  "id" bigserial NOT NULL,
+  "equipment_id" bigint NOT NULL,
+  "status" character varying(100) NOT NULL,
+  "vendor" character varying(100) NULL,
+  "cost" character varying(100) NULL,
+  "notes" text NULL,
+  "sent_by" bigint NOT NULL,
+  "sent_at" timestamptz NOT NULL,
+  "send_location_id" bigint NOT NULL,
+  "returned_by" bigint NULL,
+  "returned_at" timestamptz NULL,
+  "return_location_id" bigint NULL,
+  "updated_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "repair_orders_equipment_id_fkey" FOREIGN KEY ("equipment_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "repair_orders_return_location_id_fkey" FOREIGN KEY ("return_location_id") REFERENCES "public"."locations" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "repair_orders_returned_by_fkey" FOREIGN KEY ("returned_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "repair_orders_send_location_id_fkey" FOREIGN KEY ("send_location_id") REFERENCES "public"."locations" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "repair_orders_sent_by_fkey" FOREIGN KEY ("sent_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT
+);
+-- Create index "idx_repair_orders_equipment" to table: "repair_orders"
+CREATE INDEX "idx_repair_orders_equipment" ON "public"."repair_orders" ("equipment_id");
+-- Create index "idx_repair_orders_open" to table: "repair_orders"
+CREATE UNIQUE INDEX "idx_repair_orders_open" ON "public"."repair_orders" ("equipment_id") WHERE ((status)::text <> 'returned'::text);
+-- Create index "idx_repair_orders_status" to table: "repair_orders"
+CREATE INDEX "idx_repair_orders_status" ON "public"."repair_orders" ("status");
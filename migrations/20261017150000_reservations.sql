@@ -0,0 +1,28 @@
+-- Create "reservations" table
+CREATE TABLE "public"."reservations" (
+  "id" bigserial NOT NULL,
+  "equipment_id" bigint NOT NULL,
+  "to_department_id" bigint NULL,
+  "to_employee_id" bigint NULL,
+  "to_contract_id" bigint NULL,
+  "expires_at" timestamptz NOT NULL,
+  "comment" character varying(100) NULL,
+  "status" character varying(100) NOT NULL,
+  "reserved_by" bigint NOT NULL,
+  "reserved_at" timestamptz NOT NULL,
+  "closed_by" bigint NULL,
+  "closed_at" timestamptz NULL,
+  "location_id" bigint NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "reservations_closed_by_fkey" FOREIGN KEY ("closed_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "reservations_equipment_id_fkey" FOREIGN KEY ("equipment_id") REFERENCES "public"."equipments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "reservations_location_id_fkey" FOREIGN KEY ("location_id") REFERENCES "public"."locations" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "reservations_reserved_by_fkey" FOREIGN KEY ("reserved_by") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "reservations_to_contract_id_fkey" FOREIGN KEY ("to_contract_id") REFERENCES "public"."contracts" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "reservations_to_department_id_fkey" FOREIGN KEY ("to_department_id") REFERENCES "public"."departments" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT,
+  CONSTRAINT "reservations_to_employee_id_fkey" FOREIGN KEY ("to_employee_id") REFERENCES "public"."employees" ("id") ON UPDATE NO ACTION ON DELETE RESTRICT
+);
+-- Create index "idx_reservations_active" to table: "reservations"
+CREATE UNIQUE INDEX "idx_reservations_active" ON "public"."reservations" ("equipment_id") WHERE ((status)::text = 'active'::text);
+-- Create index "idx_reservations_equipment" to table: "reservations"
+CREATE INDEX "idx_reservations_equipment" ON "public"."reservations" ("equipment_id");
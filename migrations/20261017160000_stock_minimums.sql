@@ -0,0 +1,14 @@
+-- Create "stock_minimums" table
+CREATE TABLE "public"."stock_minimums" (
+  "id" bigserial NOT NULL,
+  "profile_id" bigint NOT NULL,
+  "department_id" bigint NULL,
+  "min_quantity" integer NOT NULL,
+  "low_since" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "stock_minimums_department_id_fkey" FOREIGN KEY ("department_id") REFERENCES "public"."departments" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "stock_minimums_profile_id_fkey" FOREIGN KEY ("profile_id") REFERENCES "public"."profiles" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "stock_minimums_min_quantity_check" CHECK (min_quantity > 0)
+);
+-- Create index "idx_stock_minimums_place" to table: "stock_minimums"
+CREATE UNIQUE INDEX "idx_stock_minimums_place" ON "public"."stock_minimums" ("profile_id", "department_id") NULLS NOT DISTINCT;
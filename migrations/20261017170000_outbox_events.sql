@@ -0,0 +1,15 @@
+-- Create "outbox_events" table
+CREATE TABLE "public"."outbox_events" (
+  "id" bigserial NOT NULL,
+  "event_type" character varying(100) NOT NULL,
+  "entity_id" bigint NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" text NULL,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT now(),
+  "published_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_outbox_events_pending" to table: "outbox_events"
+CREATE INDEX "idx_outbox_events_pending" ON "public"."outbox_events" ("id") WHERE (published_at IS NULL);
@@ -0,0 +1,17 @@
+-- Create "audit_log" table
+CREATE TABLE "public"."audit_log" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NULL,
+  "entity" character varying(50) NOT NULL,
+  "entity_id" bigint NOT NULL,
+  "action" character varying(50) NOT NULL,
+  "diff" jsonb NOT NULL,
+  "request_id" character varying(64) NULL,
+  "client_ip" inet NULL,
+  "created_at" timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_audit_log_entity" to table: "audit_log"
+CREATE INDEX "idx_audit_log_entity" ON "public"."audit_log" ("entity", "entity_id");
+-- Create index "idx_audit_log_user" to table: "audit_log"
+CREATE INDEX "idx_audit_log_user" ON "public"."audit_log" ("user_id");
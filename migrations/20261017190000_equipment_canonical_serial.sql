@@ -0,0 +1,4 @@
+-- Modify "equipments" table
+ALTER TABLE "public"."equipments" ADD COLUMN "canonical_serial_number" character varying(100) NOT NULL GENERATED ALWAYS AS (COALESCE(NULLIF(ltrim(upper(regexp_replace((serial_number)::text, '[[:space:]-]+'::text, ''::text, 'g'::text)), '0'::text), ''::text), '0'::text)) STORED;
+-- Create index "idx_equipments_canonical_serial_number" to table: "equipments"
+CREATE INDEX "idx_equipments_canonical_serial_number" ON "public"."equipments" ("canonical_serial_number");
@@ -0,0 +1,4 @@
+-- Drop repeated scans of one equipment, only the first one is kept
+DELETE FROM "public"."stocktaking_scans" s USING "public"."stocktaking_scans" f WHERE s.stocktaking_id = f.stocktaking_id AND s.equipment_id = f.equipment_id AND s.id > f.id;
+-- Create index "idx_stocktaking_scans_stocktaking_equipment" to table: "stocktaking_scans"
+CREATE UNIQUE INDEX "idx_stocktaking_scans_stocktaking_equipment" ON "public"."stocktaking_scans" ("stocktaking_id", "equipment_id") WHERE (equipment_id IS NOT NULL);
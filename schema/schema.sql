@@ -10,6 +10,7 @@ create table profiles
     id          bigserial primary key,
     title       varchar(100)                                         not null unique,
     category_id bigint references categories (id) on delete restrict not null,
+    serial_rule text[]                                               not null default '{}',
     deleted_at  timestamp with time zone
 );
 create index idx_profiles_category on profiles (category_id);
@@ -23,14 +24,18 @@ create table companies
 
 create table equipments
 (
-    id            bigserial primary key,
-    company_id    bigint references companies (id) on delete restrict not null,
-    profile_id    bigint references profiles (id) on delete restrict  not null,
-    serial_number varchar(100)                                        not null unique,
-    deleted_at    timestamp with time zone
+    id             bigserial primary key,
+    company_id     bigint references companies (id) on delete restrict not null,
+    profile_id     bigint references profiles (id) on delete restrict  not null,
+    serial_number  varchar(100)                                        not null unique,
+    deleted_at     timestamp with time zone,
+    written_off_at timestamp with time zone,
+    -- the serial number without spaces, dashes and leading zeros in upper case, see serial.Canonical
+    canonical_serial_number varchar(100) generated always as (coalesce(nullif(ltrim(upper(regexp_replace(serial_number, '[[:space:]-]+', '', 'g')), '0'), ''), '0')) stored not null
 );
 create index idx_equipments_company on equipments (company_id);
 create index idx_equipments_profile on equipments (profile_id);
+create index idx_equipments_canonical_serial_number on equipments (canonical_serial_number);
 
 create table departments
 (
@@ -106,4 +111,160 @@ create table replaces
     move_out_id bigint references locations on delete cascade not null
 );
 create index idx_replaces_move_in on replaces (move_in_id);
-create index idx_replaces_move_out on replaces (move_out_id);
\ No newline at end of file
+create index idx_replaces_move_out on replaces (move_out_id);
+
+create table location_reversals
+(
+    id           bigserial primary key,
+    location_id  bigint                                               not null,
+    replace_id   bigint,
+    equipment_id bigint references equipments (id) on delete restrict not null,
+    reverted_by  bigint references users (id) on delete restrict      not null,
+    reverted_at  timestamp with time zone                             not null,
+    location     jsonb                                                not null
+);
+create index idx_location_reversals_equipment on location_reversals (equipment_id);
+create index idx_location_reversals_reverted_by on location_reversals (reverted_by);
+
+create table stocktakings
+(
+    id            bigserial primary key,
+    department_id bigint references departments (id) on delete restrict,
+    employee_id   bigint references employees (id) on delete restrict,
+    opened_by     bigint references users (id) on delete restrict not null,
+    opened_at     timestamp with time zone                        not null,
+    closed_by     bigint references users (id) on delete restrict,
+    closed_at     timestamp with time zone,
+    applied_at    timestamp with time zone
+);
+create index idx_stocktakings_department on stocktakings (department_id);
+create index idx_stocktakings_employee on stocktakings (employee_id);
+
+create table stocktaking_items
+(
+    stocktaking_id bigint references stocktakings (id) on delete cascade not null,
+    equipment_id   bigint references equipments (id) on delete restrict  not null,
+    primary key (stocktaking_id, equipment_id)
+);
+
+create table stocktaking_scans
+(
+    id             bigserial primary key,
+    stocktaking_id bigint references stocktakings (id) on delete cascade not null,
+    serial_number  varchar(100)                                          not null,
+    equipment_id   bigint references equipments (id) on delete restrict,
+    scanned_by     bigint references users (id) on delete restrict       not null,
+    scanned_at     timestamp with time zone                              not null,
+    unique (stocktaking_id, serial_number)
+);
+create index idx_stocktaking_scans_equipment on stocktaking_scans (equipment_id);
+-- one equipment is counted once however its serial number was spelled
+create unique index idx_stocktaking_scans_stocktaking_equipment on stocktaking_scans (stocktaking_id, equipment_id)
+    where equipment_id is not null;
+
+create table equipment_merges
+(
+    id           bigserial primary key,
+    equipment_id bigint references equipments (id) on delete restrict not null,
+    duplicate_id bigint references equipments (id) on delete restrict not null,
+    merged_by    bigint references users (id) on delete restrict      not null,
+    merged_at    timestamp with time zone                             not null,
+    locations    bigint                                               not null
+);
+create index idx_equipment_merges_equipment on equipment_merges (equipment_id);
+create index idx_equipment_merges_duplicate on equipment_merges (duplicate_id);
+
+create table write_offs
+(
+    id           bigserial primary key,
+    equipment_id bigint references equipments (id) on delete restrict not null,
+    reason       varchar(100)                                         not null,
+    comment      varchar(100),
+    status       varchar(100)                                         not null,
+    requested_by bigint references users (id) on delete restrict      not null,
+    requested_at timestamp with time zone                             not null,
+    decided_by   bigint references users (id) on delete restrict,
+    decided_at   timestamp with time zone,
+    location_id  bigint references locations (id) on delete restrict
+);
+create index idx_write_offs_equipment on write_offs (equipment_id);
+create index idx_write_offs_status on write_offs (status);
+create unique index idx_write_offs_pending on write_offs (equipment_id) where status = 'pending';
+
+create table repair_orders
+(
+    id                 bigserial primary key,
+    equipment_id       bigint references equipments (id) on delete restrict not null,
+    status             varchar(100)                                         not null,
+    vendor             varchar(100),
+    cost               varchar(100),
+    notes              text,
+    sent_by            bigint references users (id) on delete restrict      not null,
+    sent_at            timestamp with time zone                             not null,
+    send_location_id   bigint references locations (id) on delete restrict  not null,
+    returned_by        bigint references users (id) on delete restrict,
+    returned_at        timestamp with time zone,
+    return_location_id bigint references locations (id) on delete restrict,
+    updated_at         timestamp with time zone                             not null
+);
+create index idx_repair_orders_equipment on repair_orders (equipment_id);
+create index idx_repair_orders_status on repair_orders (status);
+create unique index idx_repair_orders_open on repair_orders (equipment_id) where status <> 'returned';
+
+create table reservations
+(
+    id               bigserial primary key,
+    equipment_id     bigint references equipments (id) on delete restrict not null,
+    to_department_id bigint references departments (id) on delete restrict,
+    to_employee_id   bigint references employees (id) on delete restrict,
+    to_contract_id   bigint references contracts (id) on delete restrict,
+    expires_at       timestamp with time zone                             not null,
+    comment          varchar(100),
+    status           varchar(100)                                         not null,
+    reserved_by      bigint references users (id) on delete restrict      not null,
+    reserved_at      timestamp with time zone                             not null,
+    closed_by        bigint references users (id) on delete restrict,
+    closed_at        timestamp with time zone,
+    location_id      bigint references locations (id) on delete restrict
+);
+create index idx_reservations_equipment on reservations (equipment_id);
+create unique index idx_reservations_active on reservations (equipment_id) where status = 'active';
+
+create table stock_minimums
+(
+    id            bigserial primary key,
+    profile_id    bigint references profiles (id) on delete cascade    not null,
+    department_id bigint references departments (id) on delete cascade,
+    min_quantity  integer check (min_quantity > 0)                     not null,
+    low_since     timestamp with time zone
+);
+create unique index idx_stock_minimums_place on stock_minimums (profile_id, department_id) nulls not distinct;
+
+create table outbox_events
+(
+    id              bigserial primary key,
+    event_type      varchar(100)                           not null,
+    entity_id       bigint                                 not null,
+    payload         jsonb                                  not null,
+    created_at      timestamp with time zone default now() not null,
+    attempts        integer                  default 0     not null,
+    last_error      text,
+    next_attempt_at timestamp with time zone default now() not null,
+    published_at    timestamp with time zone
+);
+create index idx_outbox_events_pending on outbox_events (id) where published_at is null;
+
+create table audit_log
+(
+    id         bigserial primary key,
+    user_id    bigint,
+    entity     varchar(50)                            not null,
+    entity_id  bigint                                 not null,
+    action     varchar(50)                            not null,
+    diff       jsonb                                  not null,
+    request_id varchar(64),
+    client_ip  inet,
+    created_at timestamp with time zone default now() not null
+);
+create index idx_audit_log_entity on audit_log (entity, entity_id);
+create index idx_audit_log_user on audit_log (user_id);
\ No newline at end of file